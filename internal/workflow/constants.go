@@ -1,14 +1,21 @@
 package workflow
 
 const (
-	ContentTypeJSON        = "application/json"
-	ContentTypeJSONL       = "application/jsonl"
-	LegacyCLIWorkflowIDStr = "legacycli"
-	ContentLocationKey     = "Content-Location"
+	ContentTypeJSON          = "application/json"
+	ContentTypeJSONL         = "application/jsonl"
+	ContentTypeCycloneDXJSON = "application/vnd.cyclonedx+json"
+	ContentTypeSPDXJSON      = "application/spdx+json"
+	ContentTypeText          = "text/plain"
+	ContentTypeProblemsJSON  = "application/vnd.snyk.problems+json"
+	ContentTypeDOT           = "text/vnd.graphviz"
+	ContentTypeMermaid       = "text/vnd.mermaid"
+	LegacyCLIWorkflowIDStr   = "legacycli"
+	ContentLocationKey       = "Content-Location"
 )
 
 var (
 	MetaKeyNormalisedTargetFile = "normalisedTargetFile"
 	MetaKeyTargetFileFromPlugin = "targetFileFromPlugin"
 	MetaKeyTarget               = "target"
+	MetaKeyPluginRunReport      = "pluginRunReport"
 )
@@ -32,6 +32,25 @@ const (
 	FlagIncludeProvenance             = "include-provenance"
 	FlagIncludeComponentMetadata      = "include-component-metadata"
 	FlagUseSBOMResolution             = "use-sbom-resolution"
+	FlagDetectOnly                    = "detect-only"
+	FlagOnlyPlugins                   = "only-plugins"
+	FlagSkipPlugins                   = "skip-plugins"
+	FlagExternalPluginsDir            = "external-plugins-dir"
+	FlagPluginTimeout                 = "plugin-timeout"
+	FlagScanTimeout                   = "scan-timeout"
+	FlagCommandTimeout                = "command-timeout"
+	FlagResultCacheDir                = "result-cache-dir"
+	FlagResultCacheMaxSize            = "result-cache-max-size"
+	FlagClearResultCache              = "clear-result-cache"
+	FlagSBOMFormat                    = "sbom-format"
+	FlagStreamOutput                  = "stream-output"
+	FlagProblemsReport                = "problems-report"
+	FlagStrictGraphValidation         = "strict-graph-validation"
+	FlagRespectGitignore              = "respect-gitignore"
+	FlagGraphFormat                   = "graph-format"
+	FlagGraphDepth                    = "graph-depth"
+	FlagGraphCollapseWorkspace        = "graph-collapse-workspace"
+	FlagGraphHighlight                = "graph-highlight"
 	FlagPrintEffectiveGraph           = "effective-graph"
 	FlagPrintEffectiveGraphWithErrors = "effective-graph-with-errors"
 	FlagDotnetRuntimeResolution       = "dotnet-runtime-resolution"
@@ -39,4 +58,10 @@ const (
 	// TODO: rename this flag to remove the uv-specific reference.
 	FlagUvWorkspacePackages = "internal-uv-workspace-packages"
 	FlagForceSingleGraph    = "force-single-graph"
+	FlagWhyLabels           = "internal-why-labels"
+	FlagDiffFrom            = "from"
+	FlagDiffTo              = "to"
+	FlagJSON                = "json"
+	FlagWhyPackage          = "package"
+	FlagMaxPaths            = "max-paths"
 )
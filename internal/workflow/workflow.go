@@ -9,4 +9,28 @@ const WorkflowIDStr = "depgraph"
 var (
 	WorkflowID gafworkflow.Identifier = gafworkflow.NewWorkflowIdentifier(WorkflowIDStr)
 	DataTypeID gafworkflow.Identifier = gafworkflow.NewTypeIdentifier(WorkflowID, WorkflowIDStr)
+	// DetectionDataTypeID identifies the project detection report returned by
+	// --detect-only runs in place of dep-graphs.
+	DetectionDataTypeID gafworkflow.Identifier = gafworkflow.NewTypeIdentifier(WorkflowID, "detection")
+	// SBOMDataTypeID identifies the single SBOM document returned by
+	// --sbom-format runs in place of dep-graphs.
+	SBOMDataTypeID gafworkflow.Identifier = gafworkflow.NewTypeIdentifier(WorkflowID, "sbom")
+	// ProblemsDataTypeID identifies the report of projects that failed to
+	// resolve, returned in place of dep-graphs by --problems-report runs.
+	ProblemsDataTypeID gafworkflow.Identifier = gafworkflow.NewTypeIdentifier(WorkflowID, "problems")
+	// GraphDataTypeID identifies the DOT or Mermaid diagrams returned by
+	// --graph-format runs in place of dep-graphs.
+	GraphDataTypeID gafworkflow.Identifier = gafworkflow.NewTypeIdentifier(WorkflowID, "graph")
+
+	// DiffWorkflowID identifies the `depgraph diff` subcommand, which compares
+	// the dep-graphs of two scans.
+	DiffWorkflowID gafworkflow.Identifier = gafworkflow.NewWorkflowIdentifier(WorkflowIDStr + ".diff")
+	// DiffDataTypeID identifies the diff report returned by DiffWorkflowID.
+	DiffDataTypeID gafworkflow.Identifier = gafworkflow.NewTypeIdentifier(DiffWorkflowID, "diff")
+
+	// WhyWorkflowID identifies the `depgraph why` subcommand, which lists the
+	// dependency paths to a package.
+	WhyWorkflowID gafworkflow.Identifier = gafworkflow.NewWorkflowIdentifier(WorkflowIDStr + ".why")
+	// WhyDataTypeID identifies the path report returned by WhyWorkflowID.
+	WhyDataTypeID gafworkflow.Identifier = gafworkflow.NewTypeIdentifier(WhyWorkflowID, "why")
 )
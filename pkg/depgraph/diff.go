@@ -0,0 +1,221 @@
+package depgraph
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/rs/zerolog"
+	"github.com/snyk/go-application-framework/pkg/configuration"
+	gafworkflow "github.com/snyk/go-application-framework/pkg/workflow"
+
+	"github.com/snyk/cli-extension-dep-graph/v2/internal/workflow"
+	"github.com/snyk/cli-extension-dep-graph/v2/pkg/depgraph/parsers"
+	"github.com/snyk/cli-extension-dep-graph/v2/pkg/graphdiff"
+)
+
+// diffCallback compares the dep-graphs of the scans --from and --to name.
+// Each side is a saved JSONL output, a directory to scan, or a git ref to
+// check out into a temporary worktree and scan.
+func diffCallback(ctx gafworkflow.InvocationContext, _ []gafworkflow.Data) ([]gafworkflow.Data, error) {
+	config := ctx.GetConfiguration()
+	logger := ctx.GetEnhancedLogger()
+
+	logger.Print("DepGraph diff workflow start")
+
+	sides := make([][]graphdiff.Project, 2)
+	for i, flag := range []string{workflow.FlagDiffFrom, workflow.FlagDiffTo} {
+		spec := config.GetString(flag)
+		if spec == "" {
+			return nil, fmt.Errorf("--%s is required", flag)
+		}
+		projects, err := loadProjects(ctx, config, logger, spec)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load --%s %q: %w", flag, spec, err)
+		}
+		sides[i] = projects
+	}
+
+	report := graphdiff.Diff(sides[0], sides[1])
+
+	if config.GetBool(workflow.FlagJSON) {
+		reportBytes, err := json.Marshal(report)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal diff report: %w", err)
+		}
+		return []gafworkflow.Data{gafworkflow.NewData(DiffDataTypeID, workflow.ContentTypeJSON, reportBytes)}, nil
+	}
+
+	var buf bytes.Buffer
+	if err := graphdiff.WriteTable(&buf, report); err != nil {
+		return nil, fmt.Errorf("failed to render diff report: %w", err)
+	}
+	return []gafworkflow.Data{gafworkflow.NewData(DiffDataTypeID, workflow.ContentTypeText, buf.Bytes())}, nil
+}
+
+// loadProjects returns the projects of the scan spec names: a saved JSONL
+// file, a directory, or otherwise a git ref of the repository containing
+// the input directory.
+func loadProjects(
+	ctx gafworkflow.InvocationContext,
+	config configuration.Configuration,
+	logger *zerolog.Logger,
+	spec string,
+) ([]graphdiff.Project, error) {
+	info, err := os.Stat(spec)
+	switch {
+	case err == nil && info.IsDir():
+		return scanProjects(ctx, config, spec)
+	case err == nil:
+		data, readErr := os.ReadFile(spec)
+		if readErr != nil {
+			return nil, fmt.Errorf("failed to read saved output: %w", readErr)
+		}
+		return graphdiff.ProjectsFromJSONL(data) //nolint:wrapcheck // wrapped by the caller with the side it loads
+	case !errors.Is(err, os.ErrNotExist):
+		return nil, fmt.Errorf("failed to stat: %w", err)
+	}
+
+	inputDir := config.GetString(configuration.INPUT_DIRECTORY)
+	if inputDir == "" {
+		inputDir = "."
+	}
+	dir, cleanup, err := checkoutRef(ctx.Context(), inputDir, spec)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if cleanupErr := cleanup(); cleanupErr != nil {
+			logger.Printf("failed to remove worktree for %q: %v", spec, cleanupErr)
+		}
+	}()
+	return scanProjects(ctx, config, dir)
+}
+
+// scanOutputFlags are the depgraph workflow flags that replace or reshape its
+// dep-graph output.
+var scanOutputFlags = []string{
+	workflow.FlagDetectOnly,
+	workflow.FlagSBOMFormat,
+	workflow.FlagGraphFormat,
+	workflow.FlagProblemsReport,
+	workflow.FlagStreamOutput,
+	workflow.FlagPrintOutputJsonlWithErrors,
+	workflow.FlagPrintEffectiveGraphWithErrors,
+}
+
+// scanProjects runs the depgraph workflow on dir with the scan flags of the
+// subcommand config belongs to.
+func scanProjects(ctx gafworkflow.InvocationContext, config configuration.Configuration, dir string) ([]graphdiff.Project, error) {
+	cfg := config.Clone()
+	cfg.Set(configuration.INPUT_DIRECTORY, dir)
+	// The scan must return plain dep-graphs, whatever output the outer
+	// command was asked for.
+	for _, flag := range scanOutputFlags {
+		cfg.Unset(flag)
+	}
+
+	data, err := ctx.GetEngine().InvokeWithConfig(WorkflowID, cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve dependencies in %s: %w", dir, err)
+	}
+	return projectsFromData(data)
+}
+
+// projectsFromData converts the depgraph workflow's output to projects.
+func projectsFromData(data []gafworkflow.Data) ([]graphdiff.Project, error) {
+	var projects []graphdiff.Project
+	for _, d := range data {
+		if d.GetIdentifier().String() != DataTypeID.String() {
+			continue
+		}
+		payload, ok := d.GetPayload().([]byte)
+		if !ok {
+			return nil, fmt.Errorf("unexpected %T payload in depgraph workflow data", d.GetPayload())
+		}
+		normalisedTargetFile, _ := d.GetMetaData(workflow.MetaKeyNormalisedTargetFile)
+
+		if d.GetContentType() == workflow.ContentTypeJSONL {
+			lines, err := parsers.NewJSONL().ParseOutput(payload)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse JSONL depgraph workflow data: %w", err)
+			}
+			// The workspace JSONL item only records its first project's target
+			// file, which its lines share.
+			for i := range lines {
+				if lines[i].NormalisedTargetFile == "" {
+					lines[i].NormalisedTargetFile = normalisedTargetFile
+				}
+			}
+			converted, err := graphdiff.ProjectsFromOutputs(lines)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read depgraph workflow data: %w", err)
+			}
+			projects = append(projects, converted...)
+			continue
+		}
+
+		output := parsers.DepGraphOutput{NormalisedTargetFile: normalisedTargetFile, DepGraph: payload}
+		if targetFile, err := d.GetMetaData(workflow.MetaKeyTargetFileFromPlugin); err == nil {
+			output.TargetFileFromPlugin = &targetFile
+		}
+		converted, err := graphdiff.ProjectsFromOutputs([]parsers.DepGraphOutput{output})
+		if err != nil {
+			return nil, fmt.Errorf("failed to read depgraph workflow data: %w", err)
+		}
+		if errs := d.GetErrorList(); len(errs) > 0 {
+			converted[0].Error = errs[0].Detail
+			if converted[0].Error == "" {
+				converted[0].Error = errs[0].Title
+			}
+		}
+		projects = append(projects, converted...)
+	}
+	return projects, nil
+}
+
+// checkoutRef checks ref out into a temporary worktree of the git repository
+// containing dir, returning the directory there that corresponds to dir and
+// a function removing the worktree.
+func checkoutRef(ctx context.Context, dir, ref string) (string, func() error, error) {
+	topLevel, err := git(ctx, dir, "rev-parse", "--show-toplevel")
+	if err != nil {
+		return "", nil, fmt.Errorf("%q is neither a file, a directory nor a git ref: %w", ref, err)
+	}
+	prefix, err := git(ctx, dir, "rev-parse", "--show-prefix")
+	if err != nil {
+		return "", nil, err
+	}
+
+	worktree, err := os.MkdirTemp("", "snyk-depgraph-diff-")
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to create worktree directory: %w", err)
+	}
+	cleanup := func() error {
+		_, removeErr := git(context.Background(), topLevel, "worktree", "remove", "--force", worktree)
+		return errors.Join(removeErr, os.RemoveAll(worktree))
+	}
+
+	if _, err := git(ctx, topLevel, "worktree", "add", "--detach", worktree, ref); err != nil {
+		return "", nil, errors.Join(fmt.Errorf("%q is neither a file, a directory nor a git ref: %w", ref, err), os.RemoveAll(worktree))
+	}
+	return filepath.Join(worktree, filepath.FromSlash(prefix)), cleanup, nil
+}
+
+// git runs a git command in dir, returning its trimmed standard output.
+func git(ctx context.Context, dir string, args ...string) (string, error) {
+	cmd := exec.CommandContext(ctx, "git", append([]string{"-C", dir}, args...)...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("git %s: %w: %s", strings.Join(args, " "), err, strings.TrimSpace(stderr.String()))
+	}
+	return strings.TrimSpace(string(out)), nil
+}
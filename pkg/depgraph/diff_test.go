@@ -0,0 +1,125 @@
+package depgraph
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/snyk/error-catalog-golang-public/snyk_errors"
+	"github.com/snyk/go-application-framework/pkg/configuration"
+	gafworkflow "github.com/snyk/go-application-framework/pkg/workflow"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/snyk/cli-extension-dep-graph/v2/internal/workflow"
+	"github.com/snyk/cli-extension-dep-graph/v2/pkg/graphdiff"
+)
+
+const diffTestDepGraph = `{"schemaVersion":"1.3.0","pkgManager":{"name":"npm"},"pkgs":[{"id":"app@1.0.0","info":{"name":"app","version":"1.0.0"}}],` +
+	`"graph":{"rootNodeId":"root-node","nodes":[{"nodeId":"root-node","pkgId":"app@1.0.0","deps":[]}]}}`
+
+func Test_projectsFromData(t *testing.T) {
+	resolved := gafworkflow.NewData(DataTypeID, workflow.ContentTypeJSON, []byte(diffTestDepGraph))
+	resolved.SetMetaData(workflow.MetaKeyNormalisedTargetFile, "package-lock.json")
+
+	failed := gafworkflow.NewData(DataTypeID, workflow.ContentTypeJSON, []byte("null"))
+	failed.SetMetaData(workflow.MetaKeyNormalisedTargetFile, "lib/package-lock.json")
+	failed.AddError(snyk_errors.Error{Title: "Out of sync", Detail: "lockfile out of sync"})
+
+	workspace := gafworkflow.NewData(DataTypeID, workflow.ContentTypeJSONL, []byte(`{"depGraph":`+diffTestDepGraph+`}`+"\n"))
+	workspace.SetMetaData(workflow.MetaKeyNormalisedTargetFile, "uv.lock")
+
+	report := gafworkflow.NewData(SBOMDataTypeID, workflow.ContentTypeCycloneDXJSON, []byte("{}"))
+
+	projects, err := projectsFromData([]gafworkflow.Data{resolved, failed, workspace, report})
+	require.NoError(t, err)
+
+	require.Len(t, projects, 3, "data other than dep-graphs is skipped")
+	assert.Equal(t, "package-lock.json", projects[0].Key.NormalisedTargetFile)
+	assert.Equal(t, "npm", projects[0].Key.Type)
+	assert.Empty(t, projects[0].Error)
+
+	assert.Equal(t, "lib/package-lock.json", projects[1].Key.NormalisedTargetFile)
+	assert.Equal(t, "lockfile out of sync", projects[1].Error)
+
+	assert.Equal(t, "uv.lock", projects[2].Key.NormalisedTargetFile, "workspace lines take the item's target file")
+	assert.NotNil(t, projects[2].DepGraph)
+}
+
+func Test_diffCallback_scansIgnoreOutputFlags(t *testing.T) {
+	const withMs = `{"schemaVersion":"1.3.0","pkgManager":{"name":"npm"},` +
+		`"pkgs":[{"id":"app@1.0.0","info":{"name":"app","version":"1.0.0"}},{"id":"ms@2.0.0","info":{"name":"ms","version":"2.0.0"}}],` +
+		`"graph":{"rootNodeId":"root-node","nodes":[{"nodeId":"root-node","pkgId":"app@1.0.0","deps":[{"nodeId":"ms@2.0.0"}]},` +
+		`{"nodeId":"ms@2.0.0","pkgId":"ms@2.0.0","deps":[]}]}}`
+	fromDir, toDir := t.TempDir(), t.TempDir()
+
+	ctx := setupTestContext(t, true)
+	ctx.config.Set(workflow.FlagDiffFrom, fromDir)
+	ctx.config.Set(workflow.FlagDiffTo, toDir)
+	ctx.config.Set(workflow.FlagJSON, true)
+	ctx.config.Set(workflow.FlagGraphFormat, "dot")
+	ctx.config.Set(workflow.FlagProblemsReport, true)
+	ctx.engine.EXPECT().InvokeWithConfig(WorkflowID, gomock.Any()).DoAndReturn(
+		func(_ gafworkflow.Identifier, cfg configuration.Configuration) ([]gafworkflow.Data, error) {
+			for _, flag := range scanOutputFlags {
+				if value := cfg.GetString(flag); value != "" && value != "false" {
+					// What the depgraph workflow returns in place of dep-graphs.
+					return []gafworkflow.Data{gafworkflow.NewData(GraphDataTypeID, workflow.ContentTypeText, []byte("digraph {}"))}, nil
+				}
+			}
+			depGraph := diffTestDepGraph
+			if cfg.GetString(configuration.INPUT_DIRECTORY) == toDir {
+				depGraph = withMs
+			}
+			data := gafworkflow.NewData(DataTypeID, workflow.ContentTypeJSON, []byte(depGraph))
+			data.SetMetaData(workflow.MetaKeyNormalisedTargetFile, "package-lock.json")
+			return []gafworkflow.Data{data}, nil
+		}).Times(2)
+
+	output, err := diffCallback(ctx.invocationContext, nil)
+	require.NoError(t, err)
+	require.Len(t, output, 1)
+
+	var report graphdiff.Report
+	require.NoError(t, json.Unmarshal(output[0].GetPayload().([]byte), &report))
+	require.Len(t, report.Projects, 1, "both scans return their dep-graph")
+	assert.Equal(t, []graphdiff.Package{{Name: "ms", Version: "2.0.0"}}, report.Projects[0].Added)
+}
+
+func Test_checkoutRef(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	repo := t.TempDir()
+	runGit := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-C", repo, "-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+		out, err := cmd.CombinedOutput()
+		require.NoError(t, err, string(out))
+	}
+	runGit("init", "--quiet")
+	require.NoError(t, os.MkdirAll(filepath.Join(repo, "app"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(repo, "app", "package.json"), []byte(`{"name":"app"}`), 0o600))
+	runGit("add", ".")
+	runGit("commit", "--quiet", "-m", "initial")
+	runGit("tag", "v1")
+	require.NoError(t, os.WriteFile(filepath.Join(repo, "app", "package.json"), []byte(`{"name":"app","version":"2.0.0"}`), 0o600))
+
+	dir, cleanup, err := checkoutRef(context.Background(), filepath.Join(repo, "app"), "v1")
+	require.NoError(t, err)
+
+	content, err := os.ReadFile(filepath.Join(dir, "package.json"))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"name":"app"}`, string(content), "the worktree holds the ref, not the working copy")
+
+	require.NoError(t, cleanup())
+	assert.NoDirExists(t, dir)
+
+	_, _, err = checkoutRef(context.Background(), repo, "no-such-ref")
+	assert.ErrorContains(t, err, "neither a file, a directory nor a git ref")
+}
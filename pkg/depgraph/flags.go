@@ -7,7 +7,9 @@ import (
 )
 
 const (
-	flagSetName = "depgraph"
+	flagSetName     = "depgraph"
+	diffFlagSetName = "depgraph.diff"
+	whyFlagSetName  = "depgraph.why"
 )
 
 func getFlagSet() *pflag.FlagSet {
@@ -18,7 +20,7 @@ func getFlagSet() *pflag.FlagSet {
 	flagSet.Bool(workflow.FlagPrintOutputJsonlWithErrors, false, "Print output JSONL with errors")
 	flagSet.Bool(workflow.FlagDev, false, "Include dev dependencies")
 	flagSet.String(workflow.FlagFile, "", "Input file")
-	flagSet.String(workflow.FlagDetectionDepth, "", "Detection depth")
+	flagSet.String(workflow.FlagDetectionDepth, "", "Number of directory levels --all-projects searches for projects, the current directory being 1.")
 	flagSet.BoolP(workflow.FlagPruneRepeatedSubdependencies, "p", false, "Prune repeated sub-dependencies")
 	flagSet.Bool(workflow.FlagMavenAggregateProject, false, "Ensure all modules are resolvable by the Maven reactor.")
 	flagSet.Bool(workflow.FlagMavenSkipWrapper, false, "Use system Maven instead of the Maven wrapper.")
@@ -44,6 +46,37 @@ func getFlagSet() *pflag.FlagSet {
 	flagSet.Bool(workflow.FlagIncludeComponentMetadata, false, "Include component metadata (e.g. hashes, distribution URLs) as node labels.")
 	flagSet.String(workflow.FlagExcludePaths, "", "Comma-separated paths to exclude from scanning.")
 	flagSet.Bool(workflow.FlagUseSBOMResolution, false, "Use SBOM resolution instead of legacy CLI.")
+	flagSet.Bool(workflow.FlagDetectOnly, false, "List the projects each plugin would resolve, without building dependency graphs. Requires --use-sbom-resolution.")
+	flagSet.String(workflow.FlagOnlyPlugins, "", "Comma-separated resolver plugins to run, e.g. cargo,pnpm. Requires --use-sbom-resolution.")
+	flagSet.String(workflow.FlagExternalPluginsDir, "", "Directory of external resolver plugin manifests to run alongside the built-in plugins. Requires --use-sbom-resolution.")
+	flagSet.String(workflow.FlagSkipPlugins, "", "Comma-separated resolver plugins not to run, e.g. legacycli. Requires --use-sbom-resolution.")
+	flagSet.Duration(workflow.FlagPluginTimeout, 0, "Maximum time each resolver plugin may run, e.g. 10m. 0 means no limit. Requires --use-sbom-resolution.")
+	flagSet.Duration(workflow.FlagScanTimeout, 0, "Maximum time all resolver plugins may run in total, e.g. 30m. 0 means no limit. Requires --use-sbom-resolution.")
+	flagSet.Duration(workflow.FlagCommandTimeout, 0, "Maximum time each external command a resolver plugin runs may take, e.g. 5m. 0 means no limit. Requires --use-sbom-resolution.")
+	flagSet.String(workflow.FlagResultCacheDir, "",
+		"Directory to cache resolver results in, so unchanged lockfiles are not resolved again. Requires --use-sbom-resolution.")
+	flagSet.Int(workflow.FlagResultCacheMaxSize, 256, "Maximum size of the result cache in megabytes. 0 means no limit.")
+	flagSet.Bool(workflow.FlagClearResultCache, false, "Empty the result cache before resolving.")
+	flagSet.String(workflow.FlagSBOMFormat, "",
+		"Return SBOM documents instead of dep-graphs: cyclonedx1.5+json, cyclonedx1.6+json, spdx2.3+json or spdx3.0+json. "+
+			"CycloneDX covers all projects in one document; SPDX does so with --all-projects, otherwise returns one document per project as JSON Lines. "+
+			"Packages carry the checksums plugins record, as with --include-provenance. Requires --use-sbom-resolution.")
+	flagSet.String(workflow.FlagStreamOutput, "",
+		"Write each dependency graph as a JSONL line to this file, or - for stdout, as soon as it is resolved instead of returning them all at the end. "+
+			"Requires --use-sbom-resolution.")
+	flagSet.Bool(workflow.FlagProblemsReport, false,
+		"Return a JSON report of the projects that failed to resolve instead of the dependency graphs and the warning. "+
+			"Requires --use-sbom-resolution.")
+	flagSet.String(workflow.FlagGraphFormat, "",
+		"Return each dependency graph as a diagram instead: dot or mermaid. Requires --use-sbom-resolution.")
+	flagSet.Int(workflow.FlagGraphDepth, 0, "Only draw packages up to this many dependencies from the root. 0 means no limit.")
+	flagSet.Bool(workflow.FlagGraphCollapseWorkspace, false, "Draw workspace packages without their dependencies.")
+	flagSet.String(workflow.FlagGraphHighlight, "", "Highlight a package, as <name>[@version], and the paths to it.")
+	flagSet.Bool(workflow.FlagStrictGraphValidation, false,
+		"Fail projects whose dependency graph has dangling references, unreachable nodes, missing versions, conflicting packages or cycles "+
+			"instead of logging them. Requires --use-sbom-resolution.")
+	flagSet.Bool(workflow.FlagRespectGitignore, false,
+		"Skip the files and directories ignored by .gitignore files when detecting projects with --all-projects. Requires --use-sbom-resolution.")
 	flagSet.Bool(workflow.FlagPrintEffectiveGraph, false, "Return the pruned dependency graph.")
 	flagSet.Bool(workflow.FlagPrintEffectiveGraphWithErrors, false, "Return errors in the pruned dependency graph output.")
 	flagSet.Bool(workflow.FlagDotnetRuntimeResolution, false, "Required. You must use this option when you test .NET projects using Runtime Resolution Scanning.")
@@ -55,3 +88,29 @@ func getFlagSet() *pflag.FlagSet {
 
 	return flagSet
 }
+
+// getDiffFlagSet returns the flags of `depgraph diff`: the scan flags, applied
+// to every directory or git ref it resolves, plus the two sides to compare.
+func getDiffFlagSet() *pflag.FlagSet {
+	flagSet := pflag.NewFlagSet(diffFlagSetName, pflag.ExitOnError)
+	flagSet.AddFlagSet(getFlagSet())
+
+	flagSet.String(workflow.FlagDiffFrom, "", "The earlier scan: a directory, a git ref, or a saved --print-output-jsonl-with-errors file.")
+	flagSet.String(workflow.FlagDiffTo, "", "The later scan: a directory, a git ref, or a saved --print-output-jsonl-with-errors file.")
+	flagSet.Bool(workflow.FlagJSON, false, "Return the diff as JSON instead of a table.")
+
+	return flagSet
+}
+
+// getWhyFlagSet returns the flags of `depgraph why`: the scan flags plus the
+// package to explain and how many paths to list.
+func getWhyFlagSet() *pflag.FlagSet {
+	flagSet := pflag.NewFlagSet(whyFlagSetName, pflag.ExitOnError)
+	flagSet.AddFlagSet(getFlagSet())
+
+	flagSet.String(workflow.FlagWhyPackage, "", "The package to list the dependency paths to, as <name>[@version].")
+	flagSet.Int(workflow.FlagMaxPaths, 100, "Stop after this many paths. 0 means no limit.")
+	flagSet.Bool(workflow.FlagJSON, false, "Return the paths as JSON instead of a tree.")
+
+	return flagSet
+}
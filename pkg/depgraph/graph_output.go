@@ -0,0 +1,91 @@
+package depgraph
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/snyk/dep-graph/go/pkg/depgraph"
+	"github.com/snyk/go-application-framework/pkg/configuration"
+	gafworkflow "github.com/snyk/go-application-framework/pkg/workflow"
+
+	"github.com/snyk/cli-extension-dep-graph/v2/internal/workflow"
+	"github.com/snyk/cli-extension-dep-graph/v2/pkg/ecosystems"
+	"github.com/snyk/cli-extension-dep-graph/v2/pkg/graphrender"
+	"github.com/snyk/cli-extension-dep-graph/v2/pkg/graphwhy"
+)
+
+var graphContentTypes = map[graphrender.Format]string{
+	graphrender.FormatDOT:     workflow.ContentTypeDOT,
+	graphrender.FormatMermaid: workflow.ContentTypeMermaid,
+}
+
+// graphDiagrams renders the resolved dep-graphs as the diagrams
+// --graph-format selects.
+type graphDiagrams struct {
+	format graphrender.Format
+	opts   graphrender.Options
+}
+
+// graphDiagramsFromConfig returns the diagrams --graph-format selects, or nil
+// if it is unset and dep-graphs should be returned.
+func graphDiagramsFromConfig(config configuration.Configuration) (*graphDiagrams, error) {
+	name := config.GetString(workflow.FlagGraphFormat)
+	if name == "" {
+		return nil, nil //nolint:nilnil // no format selected is not an error
+	}
+	for _, conflicting := range []string{workflow.FlagSBOMFormat, workflow.FlagStreamOutput} {
+		if config.GetString(conflicting) != "" {
+			return nil, fmt.Errorf("--%s cannot be combined with --%s", workflow.FlagGraphFormat, conflicting)
+		}
+	}
+	format, err := graphrender.ParseFormat(name)
+	if err != nil {
+		return nil, fmt.Errorf("invalid --%s: %w", workflow.FlagGraphFormat, err)
+	}
+
+	diagrams := &graphDiagrams{
+		format: format,
+		opts: graphrender.Options{
+			MaxDepth:          config.GetInt(workflow.FlagGraphDepth),
+			CollapseWorkspace: config.GetBool(workflow.FlagGraphCollapseWorkspace),
+		},
+	}
+	if spec := config.GetString(workflow.FlagGraphHighlight); spec != "" {
+		query, err := graphwhy.ParseQuery(spec)
+		if err != nil {
+			return nil, fmt.Errorf("invalid --%s %q: %w", workflow.FlagGraphHighlight, spec, err)
+		}
+		diagrams.opts.Highlight = &query
+	}
+	return diagrams, nil
+}
+
+// workflowData renders one diagram per result. Workspace packages are those
+// of every result, so siblings are recognised across projects.
+func (d *graphDiagrams) workflowData(results []ecosystems.SCAResult) ([]gafworkflow.Data, error) {
+	graphs := make([]*depgraph.DepGraph, 0, len(results))
+	for i := range results {
+		if results[i].DepGraph != nil {
+			graphs = append(graphs, results[i].DepGraph)
+		}
+	}
+	opts := d.opts
+	opts.Workspace = graphrender.WorkspacePackages(graphs)
+
+	data := make([]gafworkflow.Data, 0, len(results))
+	for i := range results {
+		result := &results[i]
+		if result.DepGraph == nil {
+			continue
+		}
+		var buf bytes.Buffer
+		if err := graphrender.Render(&buf, result.DepGraph, d.format, opts); err != nil {
+			return nil, fmt.Errorf("failed to render dependency graph for %s: %w", result.ResolverMetadata.NormalisedTargetFile, err)
+		}
+		diagram := gafworkflow.NewData(workflow.GraphDataTypeID, graphContentTypes[d.format], buf.Bytes())
+		diagram.SetMetaData(workflow.ContentLocationKey, result.ResolverMetadata.NormalisedTargetFile)
+		diagram.SetMetaData(workflow.MetaKeyNormalisedTargetFile, result.ResolverMetadata.NormalisedTargetFile)
+		data = append(data, diagram)
+	}
+	return data, nil
+}
@@ -0,0 +1,100 @@
+package depgraph
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/snyk/error-catalog-golang-public/snyk_errors"
+	gafworkflow "github.com/snyk/go-application-framework/pkg/workflow"
+
+	"github.com/snyk/cli-extension-dep-graph/v2/internal/workflow"
+	"github.com/snyk/cli-extension-dep-graph/v2/pkg/ecosystems"
+)
+
+// problemsReport lists the potential projects that failed to resolve, for
+// consumers that count failures rather than read the rendered warning.
+type problemsReport struct {
+	Total    int       `json:"total"`
+	Failed   int       `json:"failed"`
+	Problems []problem `json:"problems"`
+}
+
+// problem is one failed SCAResult. The catalog fields are empty for errors
+// that do not come from the error catalog.
+type problem struct {
+	TargetFile     string   `json:"targetFile"`
+	PluginName     string   `json:"pluginName,omitempty"`
+	Code           string   `json:"code,omitempty"`
+	Title          string   `json:"title,omitempty"`
+	Detail         string   `json:"detail,omitempty"`
+	Classification string   `json:"classification,omitempty"`
+	Message        string   `json:"message"`
+	Causes         []string `json:"causes,omitempty"`
+}
+
+func newProblemsReport(problemResults []ecosystems.SCAResult, totalResults int) problemsReport {
+	report := problemsReport{Total: totalResults, Failed: len(problemResults), Problems: make([]problem, 0, len(problemResults))}
+	for i := range problemResults {
+		result := &problemResults[i]
+		p := problem{
+			TargetFile: result.ResolverMetadata.NormalisedTargetFile,
+			PluginName: result.ResolverMetadata.PluginName,
+			Message:    result.Error.Error(),
+			Causes:     causeChain(result.Error),
+		}
+		var snykErr snyk_errors.Error
+		if errors.As(result.Error, &snykErr) {
+			p.Code = snykErr.ErrorCode
+			p.Title = snykErr.Title
+			p.Detail = snykErr.Detail
+			p.Classification = snykErr.Classification
+		}
+		report.Problems = append(report.Problems, p)
+	}
+	return report
+}
+
+// causeChain returns the messages of the errors err wraps, outermost first,
+// skipping those that only repeat the message of the error wrapping them.
+// The errors of an errors.Join are listed in order, each followed by its own
+// causes.
+func causeChain(err error) []string {
+	var causes []string
+	var walk func(err error)
+	walk = func(err error) {
+		last := err.Error()
+		for _, cause := range wrappedErrors(err) {
+			if msg := cause.Error(); msg != last {
+				causes = append(causes, msg)
+			}
+			walk(cause)
+		}
+	}
+	walk(err)
+	return causes
+}
+
+// wrappedErrors returns the errors err wraps directly, through either form
+// of Unwrap.
+func wrappedErrors(err error) []error {
+	switch wrapper := err.(type) {
+	case interface{ Unwrap() []error }:
+		return wrapper.Unwrap()
+	case interface{ Unwrap() error }:
+		if cause := wrapper.Unwrap(); cause != nil {
+			return []error{cause}
+		}
+	}
+	return nil
+}
+
+// problemsWorkflowData returns the problems report as a workflow.Data of its
+// own type, so it can be told apart from dep-graphs.
+func problemsWorkflowData(problemResults []ecosystems.SCAResult, totalResults int) (gafworkflow.Data, error) {
+	reportBytes, err := json.Marshal(newProblemsReport(problemResults, totalResults))
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal problems report: %w", err)
+	}
+	return gafworkflow.NewData(workflow.ProblemsDataTypeID, workflow.ContentTypeProblemsJSON, reportBytes), nil
+}
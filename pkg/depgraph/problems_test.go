@@ -0,0 +1,48 @@
+package depgraph
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/snyk/error-catalog-golang-public/snyk_errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_causeChain(t *testing.T) {
+	tests := map[string]struct {
+		err  error
+		want []string
+	}{
+		"no causes": {
+			err:  errors.New("failed to parse lockfile"),
+			want: nil,
+		},
+		"wrapped causes, outermost first": {
+			err:  fmt.Errorf("resolving: %w", fmt.Errorf("exec: %w", errors.New("executable file not found"))),
+			want: []string{"exec: executable file not found", "executable file not found"},
+		},
+		"causes repeating their wrapper's message are skipped": {
+			err:  snyk_errors.Error{Title: "Python not found", Cause: snyk_errors.Error{Title: "Python not found", Cause: errors.New("exit status 1")}},
+			want: []string{"exit status 1"},
+		},
+		"joined errors each list their causes": {
+			err: fmt.Errorf("invalid dep-graph: %w", errors.Join(
+				fmt.Errorf("pruning: %w", errors.New("node a@1.0.0 not found")),
+				errors.New("cycle through b@1.0.0"),
+			)),
+			want: []string{
+				"pruning: node a@1.0.0 not found\ncycle through b@1.0.0",
+				"pruning: node a@1.0.0 not found",
+				"node a@1.0.0 not found",
+				"cycle through b@1.0.0",
+			},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tt.want, causeChain(tt.err))
+		})
+	}
+}
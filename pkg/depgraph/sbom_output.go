@@ -0,0 +1,105 @@
+package depgraph
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/snyk/go-application-framework/pkg/configuration"
+	gafworkflow "github.com/snyk/go-application-framework/pkg/workflow"
+
+	"github.com/snyk/cli-extension-dep-graph/v2/internal/workflow"
+	"github.com/snyk/cli-extension-dep-graph/v2/pkg/ecosystems"
+	"github.com/snyk/cli-extension-dep-graph/v2/pkg/sbomexport"
+)
+
+// sbomFormat is an SBOM document type --sbom-format can select.
+type sbomFormat struct {
+	contentType string
+	write       func(w io.Writer, results []ecosystems.SCAResult, subject string) error
+	// perProject renders one document per project, as JSONL, unless
+	// --all-projects asks for a combined one.
+	perProject bool
+}
+
+var sbomFormats = map[string]sbomFormat{
+	"cyclonedx1.5+json": {
+		contentType: workflow.ContentTypeCycloneDXJSON,
+		write: func(w io.Writer, results []ecosystems.SCAResult, subject string) error {
+			return sbomexport.WriteCycloneDX(w, results, sbomexport.CycloneDX15, subject)
+		},
+	},
+	"cyclonedx1.6+json": {
+		contentType: workflow.ContentTypeCycloneDXJSON,
+		write: func(w io.Writer, results []ecosystems.SCAResult, subject string) error {
+			return sbomexport.WriteCycloneDX(w, results, sbomexport.CycloneDX16, subject)
+		},
+	},
+	"spdx2.3+json": {
+		contentType: workflow.ContentTypeSPDXJSON,
+		write: func(w io.Writer, results []ecosystems.SCAResult, subject string) error {
+			return sbomexport.WriteSPDX(w, results, sbomexport.SPDX23, subject)
+		},
+		perProject: true,
+	},
+	"spdx3.0+json": {
+		contentType: workflow.ContentTypeSPDXJSON,
+		write: func(w io.Writer, results []ecosystems.SCAResult, subject string) error {
+			return sbomexport.WriteSPDX(w, results, sbomexport.SPDX30, subject)
+		},
+		perProject: true,
+	},
+}
+
+// sbomFormatFromConfig returns the format --sbom-format selects, or nil if
+// it is unset and dep-graphs should be returned.
+func sbomFormatFromConfig(config configuration.Configuration) (*sbomFormat, error) {
+	name := config.GetString(workflow.FlagSBOMFormat)
+	if name == "" {
+		return nil, nil //nolint:nilnil // no format selected is not an error
+	}
+	format, ok := sbomFormats[strings.ToLower(name)]
+	if !ok {
+		supported := slices.Sorted(maps.Keys(sbomFormats))
+		return nil, fmt.Errorf("unsupported --%s %q, expected one of: %s", workflow.FlagSBOMFormat, name, strings.Join(supported, ", "))
+	}
+	return &format, nil
+}
+
+// workflowData renders results as one SBOM workflow.Data, naming the
+// documents after the scanned directory: a single document, or, for formats
+// that split them when allProjects is unset, one document per project. Those
+// are returned as JSONL, one document per line, because the CLI only prints
+// the first workflow.Data a workflow returns.
+func (f *sbomFormat) workflowData(results []ecosystems.SCAResult, inputDir string, allProjects bool) ([]gafworkflow.Data, error) {
+	subject := inputDir
+	if abs, err := filepath.Abs(inputDir); err == nil {
+		subject = filepath.Base(abs)
+	}
+
+	if !f.perProject || allProjects || len(results) <= 1 {
+		var buf bytes.Buffer
+		if err := f.write(&buf, results, subject); err != nil {
+			return nil, fmt.Errorf("failed to render SBOM: %w", err)
+		}
+		return []gafworkflow.Data{gafworkflow.NewData(workflow.SBOMDataTypeID, f.contentType, buf.Bytes())}, nil
+	}
+
+	var lines bytes.Buffer
+	for _, result := range results {
+		var buf bytes.Buffer
+		if err := f.write(&buf, []ecosystems.SCAResult{result}, subject); err != nil {
+			return nil, fmt.Errorf("failed to render SBOM: %w", err)
+		}
+		if err := json.Compact(&lines, buf.Bytes()); err != nil {
+			return nil, fmt.Errorf("failed to render SBOM: %w", err)
+		}
+		lines.WriteByte('\n')
+	}
+	return []gafworkflow.Data{gafworkflow.NewData(workflow.SBOMDataTypeID, workflow.ContentTypeJSONL, lines.Bytes())}, nil
+}
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"slices"
 	"strconv"
 	"strings"
 
@@ -13,15 +14,14 @@ import (
 	"github.com/snyk/dep-graph/go/pkg/depgraph"
 	"github.com/snyk/error-catalog-golang-public/snyk_errors"
 	"github.com/snyk/go-application-framework/pkg/configuration"
+	"github.com/snyk/go-application-framework/pkg/ui"
 	gafworkflow "github.com/snyk/go-application-framework/pkg/workflow"
 
 	"github.com/snyk/cli-extension-dep-graph/v2/internal/legacycli"
-	"github.com/snyk/cli-extension-dep-graph/v2/internal/remoteconv"
 	"github.com/snyk/cli-extension-dep-graph/v2/internal/snykclient"
 	"github.com/snyk/cli-extension-dep-graph/v2/internal/workflow"
 	"github.com/snyk/cli-extension-dep-graph/v2/pkg/ecosystems"
-	"github.com/snyk/cli-extension-dep-graph/v2/pkg/ecosystems/legacy"
-	ecosystemslogger "github.com/snyk/cli-extension-dep-graph/v2/pkg/ecosystems/logger"
+	"github.com/snyk/cli-extension-dep-graph/v2/pkg/ecosystems/orchestrator"
 	"github.com/snyk/cli-extension-dep-graph/v2/pkg/ecosystems/python/uv"
 )
 
@@ -30,134 +30,370 @@ func handleSBOMResolution(
 	config configuration.Configuration,
 	logger *zerolog.Logger,
 ) ([]gafworkflow.Data, error) {
-	orgID := config.GetString(configuration.ORGANIZATION)
-	if orgID == "" {
-		logger.Printf("ERROR: failed to determine org id\n")
-		return nil, snykclient.NewEmptyOrgError()
+	registry, err := orchestrator.NewDefaultPluginRegistry(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create plugin registry: %w", err)
 	}
-
-	remoteRepoURL := config.GetString("remote-repo-url")
-	snykClient := snykclient.NewSnykClient(
-		ctx.GetNetworkAccess().GetHttpClient(),
-		config.GetString(configuration.API_URL),
-		orgID,
-	)
-
-	converter := remoteconv.NewRemoteSBOMConverter(snykClient, ecosystemslogger.NewFromZerolog(logger))
-
-	return handleSBOMResolutionDI(
-		ctx,
-		config,
-		logger,
-		[]ecosystems.SCAPlugin{
-			uv.NewPlugin(uv.NewClient(), converter, remoteRepoURL),
-			legacy.NewPlugin(ctx),
-		},
-	)
+	return resolveWithRegistry(ctx, config, logger, registry)
 }
 
 const errMsgNoSupportedProjects = "no supported projects detected"
 
+// handleSBOMResolutionDI runs the given plugins strictly in order, with no
+// feature flag checks and no implicit legacy fallback.
 func handleSBOMResolutionDI(
 	ctx gafworkflow.InvocationContext,
 	config configuration.Configuration,
 	logger *zerolog.Logger,
 	scaPlugins []ecosystems.SCAPlugin,
+) ([]gafworkflow.Data, error) {
+	registry, err := orchestrator.NewPluginRegistry(ctx, scaPlugins...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create plugin registry: %w", err)
+	}
+	return resolveWithRegistry(ctx, config, logger, registry)
+}
+
+func resolveWithRegistry(
+	ctx gafworkflow.InvocationContext,
+	config configuration.Configuration,
+	logger *zerolog.Logger,
+	registry *orchestrator.PluginRegistry,
 ) ([]gafworkflow.Data, error) {
 	inputDir := config.GetString(configuration.INPUT_DIRECTORY)
 	if inputDir == "" {
 		inputDir = "."
 	}
 
+	if config.GetBool(workflow.FlagDetectOnly) {
+		return detectWithRegistry(inputDir, config, registry)
+	}
+
 	orgID := config.GetString(configuration.ORGANIZATION)
 	if orgID == "" {
 		logger.Printf("ERROR: failed to determine org id\n")
 		return nil, snykclient.NewEmptyOrgError()
 	}
 
-	allProjects := config.GetBool(workflow.FlagAllProjects)
-	failFast := config.GetBool(workflow.FlagFailFast)
-	forceIncludeWorkspacePackages := config.GetBool(workflow.FlagUvWorkspacePackages)
-	targetFile := config.GetString(workflow.FlagFile)
-	pluginOptions := buildPluginOptions(config)
+	collector, err := newResultCollector(config, ctx.GetUserInterface(), logger, inputDir)
+	if err != nil {
+		return nil, err
+	}
+	if collector.stream != nil {
+		defer collector.stream.Close()
+	}
 
-	pluginLogger := ecosystemslogger.NewFromZerolog(logger)
-	workflowData := []gafworkflow.Data{}
-	var problemResults []ecosystems.SCAResult
-	totalResults := 0
-
-	for _, sp := range scaPlugins {
-		// The sbom_resolution flow runs batch-mode logic (failFast,
-		// JSONL bridge, ExcludePaths propagation) over the entire
-		// plugin output. Collect via the streaming callback into a
-		// local slice; per-plugin peak memory is unchanged from the
-		// previous PluginResult shape — the streaming wins land in
-		// consumers that pipe each SCAResult straight to disk or
-		// network rather than accumulating them.
-		var (
-			results        []ecosystems.SCAResult
-			processedFiles []string
-			seen           = make(map[string]struct{})
-		)
-		err := sp.BuildDepGraphsFromDir(
-			ctx.Context(),
-			pluginLogger,
-			inputDir,
-			pluginOptions,
-			func(r ecosystems.SCAResult) error {
-				results = append(results, r)
-				for _, p := range r.ProcessedFiles {
-					if _, ok := seen[p]; ok {
-						continue
-					}
-					seen[p] = struct{}{}
-					processedFiles = append(processedFiles, p)
-				}
-				return nil
-			},
-		)
+	report, err := registry.Resolve(inputDir, buildPluginOptions(config), collector.collect)
+	if err != nil {
+		var ffErr *orchestrator.FailFastError
+		if errors.As(err, &ffErr) {
+			// The failing result already went through the collector, which logged it.
+			return nil, createFailFastError(ffErr.Result.ResolverMetadata.NormalisedTargetFile, ffErr.Result.Error)
+		}
+		return nil, err //nolint:wrapcheck // plugin and per-result errors are surfaced to the user as-is
+	}
+
+	workflowData, err := collector.finish()
+	if err != nil {
+		return nil, err
+	}
+
+	if collector.total == 0 {
+		return nil, newExitCodeError(3, errMsgNoSupportedProjects, legacycli.ErrNoDepGraphsFound)
+	}
+
+	if config.GetBool(workflow.FlagProblemsReport) {
+		// The CLI prints only the first workflow.Data, so the report takes
+		// the place of the dep-graphs rather than following them.
+		problemsData, err := problemsWorkflowData(collector.problems, collector.total)
 		if err != nil {
-			return nil, fmt.Errorf("error building results: %w", err)
+			return nil, err
 		}
-		if len(results) == 0 {
+		workflowData = []gafworkflow.Data{problemsData}
+	} else if !collector.withErrors {
+		// TODO: This is a temporary implementation for rendering warnings.
+		// The long-term plan is for the CLI to handle all warning rendering.
+		// This will require extensions to handle `workflow.Data` objects with
+		// errors and propagate them upstream rather than rendering them directly.
+		// This change will require coordinated updates across extensions to
+		// ensure backwards compatibility and avoid breakages.
+		outputAnyWarnings(ctx, logger, collector.problems, collector.total)
+	}
+
+	if err := attachRunReport(workflowData, report); err != nil {
+		return nil, err
+	}
+
+	return workflowData, nil
+}
+
+// detectWithRegistry returns the registry's detection report as a single
+// JSON workflow.Data instead of resolving any dep-graphs.
+func detectWithRegistry(
+	inputDir string,
+	config configuration.Configuration,
+	registry *orchestrator.PluginRegistry,
+) ([]gafworkflow.Data, error) {
+	report, err := registry.Detect(inputDir, buildPluginOptions(config))
+	if err != nil {
+		return nil, fmt.Errorf("project detection failed: %w", err)
+	}
+	reportBytes, err := json.Marshal(report)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal detection report: %w", err)
+	}
+	return []gafworkflow.Data{
+		gafworkflow.NewData(workflow.DetectionDataTypeID, workflow.ContentTypeJSON, reportBytes),
+	}, nil
+}
+
+// attachRunReport records which plugin handled, skipped or missed each
+// manifest, so consumers can explain why a project was resolved by a given
+// plugin. The report covers the whole run, so it is attached to the first
+// workflow.Data only rather than copied onto every project.
+func attachRunReport(workflowData []gafworkflow.Data, report *orchestrator.RunReport) error {
+	if len(workflowData) == 0 {
+		return nil
+	}
+	reportBytes, err := json.Marshal(report)
+	if err != nil {
+		return fmt.Errorf("failed to marshal plugin run report: %w", err)
+	}
+	workflowData[0].SetMetaData(workflow.MetaKeyPluginRunReport, string(reportBytes))
+	return nil
+}
+
+// resultCollector converts the registry's results into workflow data as they
+// arrive. Its collect method is the registry's onGraph callback, so calls are
+// serialized and need no locking.
+type resultCollector struct {
+	logger                        *zerolog.Logger
+	allProjects                   bool
+	forceIncludeWorkspacePackages bool
+	targetFile                    string
+	// sbom, when set, renders the resolved dep-graphs into SBOM documents
+	// named after inputDir instead of returning them individually.
+	sbom     *sbomFormat
+	inputDir string
+	// graph, when set, renders the resolved dep-graphs as diagrams instead
+	// of returning them.
+	graph *graphDiagrams
+	// stream, when set, writes every result out as it arrives instead of
+	// keeping it as workflow data.
+	stream *streamWriter
+	// withErrors returns every result, failed ones with their errors, as one
+	// JSONL workflow.Data in the legacy CLI's JSONL-with-errors line format,
+	// instead of failing the run or warning about failures.
+	withErrors bool
+	// effectiveGraph prunes repeated sub-dependencies from every dep-graph.
+	effectiveGraph bool
+
+	workflowData []gafworkflow.Data
+	problems     []ecosystems.SCAResult
+	total        int
+
+	// lines holds the encoded results, with their first target file, when
+	// withErrors is set.
+	lines           [][]byte
+	firstLineResult *ecosystems.SCAResult
+
+	// bridged holds the results destined for the combined JSONL workflow.Data
+	// of the monitor bridge, which is inserted at bridgeIndex once the run
+	// completes.
+	bridged     []ecosystems.SCAResult
+	bridgeIndex int
+
+	// resolved holds the successful results destined for the SBOM document
+	// or the diagrams.
+	resolved []ecosystems.SCAResult
+}
+
+// newResultCollector returns a collector for the output config selects.
+func newResultCollector(
+	config configuration.Configuration,
+	userInterface ui.UserInterface,
+	logger *zerolog.Logger,
+	inputDir string,
+) (*resultCollector, error) {
+	// SBOM documents and diagrams have no place for failed results.
+	withErrorsEffective := config.GetBool(workflow.FlagPrintEffectiveGraphWithErrors)
+	for _, withErrorsFlag := range []string{workflow.FlagPrintOutputJsonlWithErrors, workflow.FlagPrintEffectiveGraphWithErrors} {
+		if !config.GetBool(withErrorsFlag) {
 			continue
 		}
-
-		if ffErr := checkFailFast(logger, failFast, allProjects, results); ffErr != nil {
-			return nil, ffErr
+		for _, conflicting := range []string{workflow.FlagSBOMFormat, workflow.FlagGraphFormat} {
+			if config.GetString(conflicting) != "" {
+				return nil, fmt.Errorf("--%s cannot be combined with --%s", withErrorsFlag, conflicting)
+			}
 		}
+	}
+
+	sbom, err := sbomFormatFromConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	stream, err := streamWriterFromConfig(config, userInterface)
+	if err != nil {
+		return nil, err
+	}
+	graph, err := graphDiagramsFromConfig(config)
+	if err != nil {
+		return nil, err
+	}
 
-		pluginData, pluginProblems, err := convertPluginResults(
-			logger, sp, results, allProjects, forceIncludeWorkspacePackages, targetFile,
-		)
+	// With --prune-repeated-subdependencies the registry has already pruned
+	// every graph, so the effective graph needs no further pruning.
+	effectiveGraph := (config.GetBool(workflow.FlagPrintEffectiveGraph) || withErrorsEffective) &&
+		!config.GetBool(workflow.FlagPruneRepeatedSubdependencies)
+
+	return &resultCollector{
+		sbom:                          sbom,
+		stream:                        stream,
+		graph:                         graph,
+		inputDir:                      inputDir,
+		logger:                        logger,
+		allProjects:                   config.GetBool(workflow.FlagAllProjects),
+		forceIncludeWorkspacePackages: config.GetBool(workflow.FlagUvWorkspacePackages),
+		targetFile:                    config.GetString(workflow.FlagFile),
+		withErrors:                    config.GetBool(workflow.FlagPrintOutputJsonlWithErrors) || withErrorsEffective,
+		effectiveGraph:                effectiveGraph,
+		workflowData:                  []gafworkflow.Data{},
+	}, nil
+}
+
+func (c *resultCollector) collect(result ecosystems.SCAResult) error {
+	c.total++
+	for _, warning := range result.Warnings {
+		c.logger.Printf("Dependency graph for %s has a problem: %s", result.ResolverMetadata.NormalisedTargetFile, warning)
+	}
+
+	if c.effectiveGraph && result.DepGraph != nil {
+		pruned, err := ecosystems.PruneRepeatedSubdependencies(result.DepGraph)
 		if err != nil {
-			return nil, err
+			return fmt.Errorf("failed to prune dep-graph for %s: %w", result.ResolverMetadata.NormalisedTargetFile, err)
+		}
+		result.DepGraph = pruned
+	}
+
+	if (c.sbom != nil || c.graph != nil) && result.Error == nil {
+		c.resolved = append(c.resolved, result)
+		return nil
+	}
+
+	if c.stream != nil {
+		return c.streamResult(&result)
+	}
+
+	if isMonitorJSONLBridgeInvocation(result.ResolverMetadata.PluginName, c.forceIncludeWorkspacePackages, c.targetFile) {
+		if result.Error != nil {
+			logResultError(c.logger, result.ResolverMetadata.NormalisedTargetFile, result.Error)
+			c.problems = append(c.problems, result)
+			return result.Error
+		}
+		if len(c.bridged) == 0 {
+			c.bridgeIndex = len(c.workflowData)
+		}
+		c.bridged = append(c.bridged, result)
+		return nil
+	}
+
+	if result.Error != nil {
+		logResultError(c.logger, result.ResolverMetadata.NormalisedTargetFile, result.Error)
+		c.problems = append(c.problems, result)
+		if !c.withErrors {
+			if !c.allProjects {
+				return result.Error
+			}
+			return nil
 		}
+	}
 
-		workflowData = append(workflowData, pluginData...)
-		problemResults = append(problemResults, pluginProblems...)
-		totalResults += len(results)
+	if c.withErrors {
+		return c.collectLine(&result)
+	}
 
-		pluginOptions.WithExcludePaths(processedFiles)
+	data, err := workflowDataFromDepGraph(&result)
+	if err != nil {
+		return fmt.Errorf("failed to create workflow data: %w", err)
+	}
+	c.workflowData = append(c.workflowData, data)
+	return nil
+}
 
-		if !allProjects {
-			break
+// collectLine encodes result as a line of the JSONL-with-errors output.
+func (c *resultCollector) collectLine(result *ecosystems.SCAResult) error {
+	line, err := marshalResultLine(result)
+	if err != nil {
+		return err
+	}
+	if c.firstLineResult == nil {
+		c.firstLineResult = &ecosystems.SCAResult{
+			ProjectDescriptor: result.ProjectDescriptor,
+			ResolverMetadata:  result.ResolverMetadata,
 		}
 	}
+	c.lines = append(c.lines, line)
+	return nil
+}
 
-	if totalResults == 0 {
-		return nil, newExitCodeError(3, errMsgNoSupportedProjects, legacycli.ErrNoDepGraphsFound)
+// streamResult writes result out straight away. Only the failures are kept,
+// without their dep-graphs, for the warning summary.
+func (c *resultCollector) streamResult(result *ecosystems.SCAResult) error {
+	if err := c.stream.write(result); err != nil {
+		return err
+	}
+	if result.Error == nil {
+		return nil
 	}
 
-	// TODO: This is a temporary implementation for rendering warnings.
-	// The long-term plan is for the CLI to handle all warning rendering.
-	// This will require extensions to handle `workflow.Data` objects with
-	// errors and propagate them upstream rather than rendering them directly.
-	// This change will require coordinated updates across extensions to
-	// ensure backwards compatibility and avoid breakages.
-	outputAnyWarnings(ctx, logger, problemResults, totalResults)
+	logResultError(c.logger, result.ResolverMetadata.NormalisedTargetFile, result.Error)
+	problem := *result
+	problem.DepGraph = nil
+	c.problems = append(c.problems, problem)
+	if !c.allProjects && !c.withErrors {
+		return result.Error
+	}
+	return nil
+}
 
-	return workflowData, nil
+// finish returns the collected workflow data, with the monitor bridge's
+// combined JSONL item in the position its first result arrived at, or the
+// SBOM documents or diagrams when --sbom-format or --graph-format is set. Nothing is returned when results
+// were streamed, and the stream is closed.
+func (c *resultCollector) finish() ([]gafworkflow.Data, error) {
+	if c.stream != nil {
+		return []gafworkflow.Data{}, c.stream.Close()
+	}
+	if c.sbom != nil {
+		return c.sbom.workflowData(c.resolved, c.inputDir, c.allProjects)
+	}
+	if c.graph != nil {
+		return c.graph.workflowData(c.resolved)
+	}
+	if len(c.lines) > 0 {
+		c.workflowData = append(c.workflowData, c.linesWorkflowData())
+	}
+	if len(c.bridged) == 0 {
+		return c.workflowData, nil
+	}
+	bridgeData, _, err := combineWorkspaceResultsAsJSONL(c.logger, c.bridged)
+	if err != nil {
+		return nil, err
+	}
+	return slices.Insert(c.workflowData, c.bridgeIndex, bridgeData...), nil
+}
+
+// linesWorkflowData returns the collected lines as one JSONL workflow.Data,
+// which the CLI prints whole, labelled with the target file of the first.
+func (c *resultCollector) linesWorkflowData() gafworkflow.Data {
+	data := gafworkflow.NewData(workflow.DataTypeID, workflow.ContentTypeJSONL, bytes.Join(c.lines, []byte("\n")))
+	targetFile := c.firstLineResult.ResolverMetadata.NormalisedTargetFile
+	data.SetMetaData(workflow.ContentLocationKey, targetFile)
+	data.SetMetaData(workflow.MetaKeyNormalisedTargetFile, targetFile)
+	if tf := c.firstLineResult.ProjectDescriptor.Identity.TargetFile; tf != nil {
+		data.SetMetaData(workflow.MetaKeyTargetFileFromPlugin, *tf)
+	}
+	return data
 }
 
 func buildPluginOptions(config configuration.Configuration) *ecosystems.SCAPluginOptions {
@@ -174,38 +410,28 @@ func buildPluginOptions(config configuration.Configuration) *ecosystems.SCAPlugi
 		WithExclude(parseExcludeFlag(config.GetString(workflow.FlagExclude))).
 		WithExcludePaths(parseExcludeFlag(config.GetString(workflow.FlagExcludePaths))).
 		WithFailFast(config.GetBool(workflow.FlagFailFast)).
-		WithForceSingleGraph(config.GetBool(workflow.FlagForceSingleGraph))
+		WithOnlyPlugins(parseExcludeFlag(config.GetString(workflow.FlagOnlyPlugins))).
+		WithSkipPlugins(parseExcludeFlag(config.GetString(workflow.FlagSkipPlugins))).
+		WithPluginTimeout(config.GetDuration(workflow.FlagPluginTimeout)).
+		WithScanTimeout(config.GetDuration(workflow.FlagScanTimeout)).
+		WithCommandTimeout(config.GetDuration(workflow.FlagCommandTimeout)).
+		WithPruneRepeatedSubdependencies(config.GetBool(workflow.FlagPruneRepeatedSubdependencies)).
+		WithStrictGraphValidation(config.GetBool(workflow.FlagStrictGraphValidation)).
+		WithRespectGitignore(config.GetBool(workflow.FlagRespectGitignore)).
+		WithForceSingleGraph(config.GetBool(workflow.FlagForceSingleGraph)).
+		// SBOM documents carry the checksums plugins record as provenance.
+		WithIncludeProvenance(config.GetBool(workflow.FlagIncludeProvenance) || config.GetString(workflow.FlagSBOMFormat) != "").
+		WithWhyLabels(config.GetBool(workflow.FlagWhyLabels))
 
 	if targetFile := config.GetString(workflow.FlagFile); targetFile != "" {
 		opts = opts.WithTargetFile(targetFile)
 	}
-	return opts
-}
-
-func checkFailFast(logger *zerolog.Logger, failFast, allProjects bool, results []ecosystems.SCAResult) error {
-	if !failFast || !allProjects {
-		return nil
-	}
-	for _, result := range results {
-		if result.Error != nil {
-			logResultError(logger, result.ResolverMetadata.NormalisedTargetFile, result.Error)
-			return createFailFastError(result.ResolverMetadata.NormalisedTargetFile, result.Error)
-		}
+	// As with --strict-out-of-sync, values the CLI would reject are left for
+	// the legacy CLI to report rather than limiting native discovery.
+	if depth, err := strconv.Atoi(config.GetString(workflow.FlagDetectionDepth)); err == nil && depth > 0 {
+		opts = opts.WithDetectionDepth(depth)
 	}
-	return nil
-}
-
-func convertPluginResults(
-	logger *zerolog.Logger,
-	plugin ecosystems.SCAPlugin,
-	results []ecosystems.SCAResult,
-	allProjects, forceIncludeWorkspacePackages bool,
-	targetFile string,
-) ([]gafworkflow.Data, []ecosystems.SCAResult, error) {
-	if isMonitorJSONLBridgeInvocation(plugin, forceIncludeWorkspacePackages, targetFile) {
-		return combineWorkspaceResultsAsJSONL(logger, results)
-	}
-	return processResultsIndividually(logger, results, allProjects)
+	return opts
 }
 
 func logResultError(logger *zerolog.Logger, targetFile string, err error) {
@@ -263,8 +489,8 @@ func renderWarningForProblemResults(problemResults []ecosystems.SCAResult, total
 //
 // Once `snyk monitor` is migrated to Go this whole branch can go away. `snyk test` (already
 // in Go) does not need it.
-func isMonitorJSONLBridgeInvocation(plugin ecosystems.SCAPlugin, forceIncludeWorkspacePackages bool, targetFile string) bool {
-	return plugin.GetName() == uv.PluginName && forceIncludeWorkspacePackages && targetFile != ""
+func isMonitorJSONLBridgeInvocation(pluginName string, forceIncludeWorkspacePackages bool, targetFile string) bool {
+	return pluginName == uv.PluginName && forceIncludeWorkspacePackages && targetFile != ""
 }
 
 // parseExcludeFlag parses a comma-separated exclude flag value into a slice of strings.
@@ -322,32 +548,6 @@ func combineWorkspaceResultsAsJSONL(logger *zerolog.Logger, results []ecosystems
 	return []gafworkflow.Data{workflowData}, problemResults, nil
 }
 
-func processResultsIndividually(logger *zerolog.Logger, results []ecosystems.SCAResult, allProjects bool) ([]gafworkflow.Data, []ecosystems.SCAResult, error) {
-	var workflowData []gafworkflow.Data
-	var problemResults []ecosystems.SCAResult
-	for i := range results {
-		result := &results[i]
-
-		if result.Error != nil {
-			logResultError(logger, result.ResolverMetadata.NormalisedTargetFile, result.Error)
-			problemResults = append(problemResults, *result)
-
-			if !allProjects {
-				return nil, problemResults, result.Error
-			}
-
-			continue
-		}
-
-		data, err := workflowDataFromDepGraph(result)
-		if err != nil {
-			return nil, problemResults, fmt.Errorf("failed to create workflow data: %w", err)
-		}
-		workflowData = append(workflowData, data)
-	}
-	return workflowData, problemResults, nil
-}
-
 type jsonlOutputLine struct {
 	DepGraph   *depgraph.DepGraph `json:"depGraph"`
 	TargetFile string             `json:"targetFile"`
@@ -373,10 +573,17 @@ func combineWorkspaceDepGraphsAsJSONL(results []ecosystems.SCAResult) ([]byte, e
 	return combined, nil
 }
 
+// workflowDataFromDepGraph converts a successful result to workflow data the
+// way legacycli.MapToWorkflowData converts a legacy CLI line, with its
+// dep-graph as the payload.
 func workflowDataFromDepGraph(result *ecosystems.SCAResult) (gafworkflow.Data, error) {
-	depGraphBytes, err := json.Marshal(result.DepGraph)
-	if err != nil {
-		return nil, fmt.Errorf("failed to marshal depgraph data: %w", err)
+	var depGraphBytes []byte
+	if result.DepGraph != nil {
+		var err error
+		depGraphBytes, err = json.Marshal(result.DepGraph)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal depgraph data: %w", err)
+		}
 	}
 
 	data := gafworkflow.NewData(workflow.DataTypeID, workflow.ContentTypeJSON, depGraphBytes)
@@ -391,3 +598,13 @@ func workflowDataFromDepGraph(result *ecosystems.SCAResult) (gafworkflow.Data, e
 
 	return data, nil
 }
+
+// catalogError returns err as an error catalog error, wrapping errors from
+// outside the catalog so consumers can read every result's error alike.
+func catalogError(err error) snyk_errors.Error {
+	var snykErr snyk_errors.Error
+	if errors.As(err, &snykErr) {
+		return snykErr
+	}
+	return snyk_errors.Error{Title: "Failed to resolve dependencies", Detail: err.Error(), Cause: err}
+}
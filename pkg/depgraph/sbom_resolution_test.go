@@ -7,8 +7,11 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
@@ -27,9 +30,12 @@ import (
 	"github.com/snyk/cli-extension-dep-graph/v2/internal/legacycli"
 	"github.com/snyk/cli-extension-dep-graph/v2/internal/mocks"
 	"github.com/snyk/cli-extension-dep-graph/v2/internal/workflow"
+	"github.com/snyk/cli-extension-dep-graph/v2/pkg/conversion"
+	"github.com/snyk/cli-extension-dep-graph/v2/pkg/depgraph/parsers"
 	"github.com/snyk/cli-extension-dep-graph/v2/pkg/ecosystems"
 	"github.com/snyk/cli-extension-dep-graph/v2/pkg/ecosystems/legacy"
 	"github.com/snyk/cli-extension-dep-graph/v2/pkg/ecosystems/logger"
+	"github.com/snyk/cli-extension-dep-graph/v2/pkg/ecosystems/orchestrator"
 	"github.com/snyk/cli-extension-dep-graph/v2/pkg/ecosystems/python/uv"
 	"github.com/snyk/cli-extension-dep-graph/v2/pkg/identity"
 )
@@ -2227,7 +2233,662 @@ func TestWorkflowDataFromDepGraph(t *testing.T) {
 	})
 }
 
+func Test_handleSBOMResolutionDI_attachesPluginRunReport(t *testing.T) {
+	ctx := setupTestContext(t, true)
+	legacyMock := NewLegacyHarness(ctx)
+
+	mockPlugin := &mockScaPlugin{
+		name: "mock",
+		results: []ecosystems.SCAResult{
+			withProcessedFiles(ecosystems.SCAResult{
+				DepGraph: createTestDepGraph(t, "pip", "test-project", "1.0.0"),
+				ResolverMetadata: &ecosystems.ResolverMetadata{
+					NormalisedTargetFile: "pyproject.toml",
+				},
+			}, "pyproject.toml"),
+		},
+	}
+
+	workflowData, err := handleSBOMResolutionDI(
+		ctx.invocationContext,
+		ctx.config,
+		&nopLogger,
+		[]ecosystems.SCAPlugin{mockPlugin, legacyMock.Plugin},
+	)
+	require.NoError(t, err)
+	require.Len(t, workflowData, 1)
+
+	rawReport, err := workflowData[0].GetMetaData(workflow.MetaKeyPluginRunReport)
+	require.NoError(t, err)
+
+	var report orchestrator.RunReport
+	require.NoError(t, json.Unmarshal([]byte(rawReport), &report))
+	require.Len(t, report.Plugins, 2)
+
+	assert.Equal(t, "mock", report.Plugins[0].Name)
+	assert.Equal(t, orchestrator.PluginStatusSucceeded, report.Plugins[0].Status)
+	assert.Equal(t, 1, report.Plugins[0].Results)
+	assert.Equal(t, []string{"pyproject.toml"}, report.Plugins[0].ProcessedFiles)
+
+	assert.Equal(t, legacy.PluginName, report.Plugins[1].Name)
+	assert.Equal(t, orchestrator.PluginStatusNotRun, report.Plugins[1].Status)
+}
+
+func Test_handleSBOMResolutionDI_attachesPluginRunReportOnce(t *testing.T) {
+	ctx := setupTestContext(t, true)
+	legacyMock := NewLegacyHarness(ctx)
+
+	mockPlugin := &mockScaPlugin{
+		name: "mock",
+		results: []ecosystems.SCAResult{
+			withProcessedFiles(ecosystems.SCAResult{
+				DepGraph:         createTestDepGraph(t, "pip", "project-a", "1.0.0"),
+				ResolverMetadata: &ecosystems.ResolverMetadata{NormalisedTargetFile: "a/pyproject.toml"},
+			}, "a/pyproject.toml"),
+			withProcessedFiles(ecosystems.SCAResult{
+				DepGraph:         createTestDepGraph(t, "pip", "project-b", "1.0.0"),
+				ResolverMetadata: &ecosystems.ResolverMetadata{NormalisedTargetFile: "b/pyproject.toml"},
+			}, "b/pyproject.toml"),
+		},
+	}
+
+	workflowData, err := handleSBOMResolutionDI(
+		ctx.invocationContext,
+		ctx.config,
+		&nopLogger,
+		[]ecosystems.SCAPlugin{mockPlugin, legacyMock.Plugin},
+	)
+	require.NoError(t, err)
+	require.Len(t, workflowData, 2)
+
+	_, err = workflowData[0].GetMetaData(workflow.MetaKeyPluginRunReport)
+	assert.NoError(t, err)
+	_, err = workflowData[1].GetMetaData(workflow.MetaKeyPluginRunReport)
+	assert.Error(t, err)
+}
+
 // stringPtr returns a pointer to the given string value.
 func stringPtr(s string) *string {
 	return &s
 }
+
+func Test_handleSBOMResolutionDI_detectOnlyReturnsDetectionReport(t *testing.T) {
+	ctx := setupTestContext(t, true)
+	ctx.config.Set(workflow.FlagDetectOnly, true)
+	legacyMock := NewLegacyHarness(ctx)
+
+	mockPlugin := &mockScaPlugin{
+		name: "mock",
+		results: []ecosystems.SCAResult{
+			{DepGraph: createTestDepGraph(t, "pip", "test-project", "1.0.0")},
+		},
+	}
+
+	workflowData, err := handleSBOMResolutionDI(
+		ctx.invocationContext,
+		ctx.config,
+		&nopLogger,
+		[]ecosystems.SCAPlugin{mockPlugin, legacyMock.Plugin},
+	)
+	require.NoError(t, err)
+	require.Len(t, workflowData, 1)
+	assert.Equal(t, DetectionDataTypeID, workflowData[0].GetIdentifier())
+	assert.Equal(t, workflow.ContentTypeJSON, workflowData[0].GetContentType())
+
+	var report orchestrator.DetectionReport
+	require.NoError(t, json.Unmarshal(workflowData[0].GetPayload().([]byte), &report))
+	require.Len(t, report.Plugins, 2)
+	assert.Equal(t, orchestrator.PluginDetection{Name: "mock"}, report.Plugins[0])
+	assert.Equal(t, legacy.PluginName, report.Plugins[1].Name)
+	assert.False(t, report.Plugins[1].Supported)
+	assert.False(t, legacyMock.invoked, "detection must not invoke the legacy CLI")
+}
+
+func Test_handleSBOMResolutionDI_sbomFormatReturnsCycloneDXDocument(t *testing.T) {
+	ctx := setupTestContext(t, true)
+	ctx.config.Set(workflow.FlagSBOMFormat, "cyclonedx1.6+json")
+	ctx.config.Set(workflow.FlagAllProjects, true)
+
+	mockPlugin := &mockScaPlugin{
+		name: "mock",
+		results: []ecosystems.SCAResult{
+			{
+				DepGraph:         createTestDepGraph(t, "pip", "project-a", "1.0.0"),
+				ResolverMetadata: &ecosystems.ResolverMetadata{NormalisedTargetFile: "a/requirements.txt"},
+			},
+			{
+				DepGraph:         createTestDepGraph(t, "pip", "project-b", "2.0.0"),
+				ResolverMetadata: &ecosystems.ResolverMetadata{NormalisedTargetFile: "b/requirements.txt"},
+			},
+		},
+	}
+
+	workflowData, err := handleSBOMResolutionDI(ctx.invocationContext, ctx.config, &nopLogger, []ecosystems.SCAPlugin{mockPlugin})
+	require.NoError(t, err)
+	require.Len(t, workflowData, 1)
+	assert.Equal(t, SBOMDataTypeID, workflowData[0].GetIdentifier())
+	assert.Equal(t, workflow.ContentTypeCycloneDXJSON, workflowData[0].GetContentType())
+
+	var bom struct {
+		SpecVersion string `json:"specVersion"`
+		Metadata    struct {
+			Component struct {
+				Components []struct {
+					Name string `json:"name"`
+				} `json:"components"`
+			} `json:"component"`
+		} `json:"metadata"`
+	}
+	require.NoError(t, json.Unmarshal(workflowData[0].GetPayload().([]byte), &bom))
+	assert.Equal(t, "1.6", bom.SpecVersion)
+	require.Len(t, bom.Metadata.Component.Components, 2)
+	assert.Equal(t, "project-a", bom.Metadata.Component.Components[0].Name)
+	assert.Equal(t, "project-b", bom.Metadata.Component.Components[1].Name)
+}
+
+func Test_handleSBOMResolutionDI_sbomFormatReturnsSPDXDocuments(t *testing.T) {
+	tests := map[string]struct {
+		allProjects     bool
+		wantContentType string
+		wantNames       []string
+	}{
+		"one document per project, as JSONL": {
+			allProjects:     false,
+			wantContentType: workflow.ContentTypeJSONL,
+			wantNames:       []string{"project-a", "project-b"},
+		},
+		"one combined document with --all-projects": {
+			allProjects:     true,
+			wantContentType: workflow.ContentTypeSPDXJSON,
+			wantNames:       []string{"combined"},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx := setupTestContext(t, true)
+			ctx.config.Set(workflow.FlagSBOMFormat, "spdx2.3+json")
+			ctx.config.Set(workflow.FlagAllProjects, tt.allProjects)
+			inputDir := filepath.Join(t.TempDir(), "combined")
+			require.NoError(t, os.Mkdir(inputDir, 0o755))
+			ctx.config.Set(configuration.INPUT_DIRECTORY, inputDir)
+
+			mockPlugin := &mockScaPlugin{
+				name: "mock",
+				results: []ecosystems.SCAResult{
+					{
+						DepGraph:         createTestDepGraph(t, "pip", "project-a", "1.0.0"),
+						ResolverMetadata: &ecosystems.ResolverMetadata{NormalisedTargetFile: "a/requirements.txt"},
+					},
+					{
+						DepGraph:         createTestDepGraph(t, "pip", "project-b", "2.0.0"),
+						ResolverMetadata: &ecosystems.ResolverMetadata{NormalisedTargetFile: "b/requirements.txt"},
+					},
+				},
+			}
+
+			workflowData, err := handleSBOMResolutionDI(ctx.invocationContext, ctx.config, &nopLogger, []ecosystems.SCAPlugin{mockPlugin})
+			require.NoError(t, err)
+			require.Len(t, workflowData, 1, "the CLI only prints the first workflow.Data")
+			data := workflowData[0]
+			assert.Equal(t, SBOMDataTypeID, data.GetIdentifier())
+			assert.Equal(t, tt.wantContentType, data.GetContentType())
+
+			documents := []string{string(data.GetPayload().([]byte))}
+			if tt.wantContentType == workflow.ContentTypeJSONL {
+				documents = strings.Split(strings.TrimSpace(documents[0]), "\n")
+			}
+			var names []string
+			for _, document := range documents {
+				var doc struct {
+					SPDXVersion string `json:"spdxVersion"`
+					Name        string `json:"name"`
+				}
+				require.NoError(t, json.Unmarshal([]byte(document), &doc))
+				assert.Equal(t, "SPDX-2.3", doc.SPDXVersion)
+				names = append(names, doc.Name)
+			}
+			assert.Equal(t, tt.wantNames, names)
+		})
+	}
+}
+
+func Test_handleSBOMResolutionDI_unsupportedSBOMFormat(t *testing.T) {
+	ctx := setupTestContext(t, true)
+	ctx.config.Set(workflow.FlagSBOMFormat, "cyclonedx1.2+xml")
+	mockPlugin := &mockScaPlugin{name: "mock"}
+
+	_, err := handleSBOMResolutionDI(ctx.invocationContext, ctx.config, &nopLogger, []ecosystems.SCAPlugin{mockPlugin})
+
+	require.ErrorContains(t, err, "unsupported --sbom-format")
+	assert.Nil(t, mockPlugin.options, "no plugin may run with an unsupported format")
+}
+
+// staticConverter converts every SBOM to the same dep-graph.
+type staticConverter struct {
+	depGraph *dg.DepGraph
+}
+
+func (c staticConverter) ConvertSBOM(context.Context, io.Reader, conversion.ConvertSBOMOptions) ([]*dg.DepGraph, []conversion.Warning, error) {
+	return []*dg.DepGraph{c.depGraph}, nil, nil
+}
+
+func Test_handleSBOMResolutionDI_sbomFormatIncludesLockfileChecksums(t *testing.T) {
+	const uvLock = `version = 1
+requires-python = ">=3.12"
+
+[[package]]
+name = "mock-project"
+version = "1.0.0"
+source = { virtual = "." }
+
+[[package]]
+name = "django"
+version = "3.1"
+source = { registry = "https://pypi.org/simple" }
+sdist = { url = "https://files.pythonhosted.org/django-3.1.tar.gz", hash = "sha256:aaa", size = 1 }
+`
+
+	tests := map[string]struct {
+		format string
+		// checksumsOf returns the checksums the document lists for django.
+		checksumsOf func(t *testing.T, document []byte) []string
+	}{
+		"CycloneDX hashes": {
+			format: "cyclonedx1.6+json",
+			checksumsOf: func(t *testing.T, document []byte) []string {
+				t.Helper()
+				var bom struct {
+					Components []struct {
+						Name   string `json:"name"`
+						Hashes []struct {
+							Alg     string `json:"alg"`
+							Content string `json:"content"`
+						} `json:"hashes"`
+					} `json:"components"`
+				}
+				require.NoError(t, json.Unmarshal(document, &bom))
+				var sums []string
+				for _, component := range bom.Components {
+					if component.Name == "django" {
+						for _, hash := range component.Hashes {
+							sums = append(sums, hash.Alg+":"+hash.Content)
+						}
+					}
+				}
+				return sums
+			},
+		},
+		"SPDX checksums": {
+			format: "spdx2.3+json",
+			checksumsOf: func(t *testing.T, document []byte) []string {
+				t.Helper()
+				var doc struct {
+					Packages []struct {
+						Name      string `json:"name"`
+						Checksums []struct {
+							Algorithm     string `json:"algorithm"`
+							ChecksumValue string `json:"checksumValue"`
+						} `json:"checksums"`
+					} `json:"packages"`
+				}
+				require.NoError(t, json.Unmarshal(document, &doc))
+				var sums []string
+				for _, pkg := range doc.Packages {
+					if pkg.Name == "django" {
+						for _, sum := range pkg.Checksums {
+							sums = append(sums, sum.Algorithm+":"+sum.ChecksumValue)
+						}
+					}
+				}
+				return sums
+			},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			dir := t.TempDir()
+			require.NoError(t, os.WriteFile(filepath.Join(dir, uv.LockFileName), []byte(uvLock), 0o600))
+			require.NoError(t, os.WriteFile(filepath.Join(dir, uv.PyprojectTomlFileName), []byte("[project]\nname = \"mock-project\"\n"), 0o600))
+
+			builder, err := dg.NewBuilder(&dg.PkgManager{Name: "pip"}, &dg.PkgInfo{Name: "mock-project", Version: "1.0.0"})
+			require.NoError(t, err)
+			builder.AddNode("django@3.1", &dg.PkgInfo{Name: "django", Version: "3.1", PackageURL: "pkg:pypi/django@3.1"})
+			require.NoError(t, builder.ConnectNodes(builder.GetRootNode().NodeID, "django@3.1"))
+
+			ctx := setupTestContext(t, true)
+			ctx.config.Set(workflow.FlagSBOMFormat, tt.format)
+			ctx.config.Set(configuration.INPUT_DIRECTORY, dir)
+			plugin := uv.NewPlugin(&uv.MockClient{}, staticConverter{depGraph: builder.Build()}, "")
+
+			workflowData, err := handleSBOMResolutionDI(ctx.invocationContext, ctx.config, &nopLogger, []ecosystems.SCAPlugin{plugin})
+			require.NoError(t, err)
+			require.Len(t, workflowData, 1)
+
+			document := bytes.TrimSpace(workflowData[0].GetPayload().([]byte))
+			assert.NotEmpty(t, tt.checksumsOf(t, document), "the uv.lock hashes are recorded without --include-provenance")
+		})
+	}
+}
+
+func Test_handleSBOMResolutionDI_graphFormatReturnsDiagrams(t *testing.T) {
+	ctx := setupTestContext(t, true)
+	ctx.config.Set(workflow.FlagGraphFormat, "mermaid")
+	ctx.config.Set(workflow.FlagAllProjects, true)
+
+	mockPlugin := &mockScaPlugin{
+		name: "mock",
+		results: []ecosystems.SCAResult{
+			{
+				DepGraph:         createTestDepGraph(t, "pip", "project-a", "1.0.0"),
+				ResolverMetadata: &ecosystems.ResolverMetadata{NormalisedTargetFile: "a/requirements.txt"},
+			},
+			{
+				DepGraph:         createTestDepGraph(t, "pip", "project-b", "2.0.0"),
+				ResolverMetadata: &ecosystems.ResolverMetadata{NormalisedTargetFile: "b/requirements.txt"},
+			},
+		},
+	}
+
+	workflowData, err := handleSBOMResolutionDI(ctx.invocationContext, ctx.config, &nopLogger, []ecosystems.SCAPlugin{mockPlugin})
+	require.NoError(t, err)
+	require.Len(t, workflowData, 2)
+	for i, project := range []string{"project-a", "project-b"} {
+		assert.Equal(t, GraphDataTypeID, workflowData[i].GetIdentifier())
+		assert.Equal(t, workflow.ContentTypeMermaid, workflowData[i].GetContentType())
+		diagram := string(workflowData[i].GetPayload().([]byte))
+		assert.True(t, strings.HasPrefix(diagram, "graph LR\n"), diagram)
+		assert.Contains(t, diagram, project)
+	}
+	targetFile, err := workflowData[1].GetMetaData(workflow.MetaKeyNormalisedTargetFile)
+	require.NoError(t, err)
+	assert.Equal(t, "b/requirements.txt", targetFile)
+}
+
+func Test_handleSBOMResolutionDI_graphFormatRejectsSBOMFormat(t *testing.T) {
+	ctx := setupTestContext(t, true)
+	ctx.config.Set(workflow.FlagGraphFormat, "dot")
+	ctx.config.Set(workflow.FlagSBOMFormat, "cyclonedx1.6+json")
+	mockPlugin := &mockScaPlugin{name: "mock"}
+
+	_, err := handleSBOMResolutionDI(ctx.invocationContext, ctx.config, &nopLogger, []ecosystems.SCAPlugin{mockPlugin})
+
+	require.ErrorContains(t, err, "--graph-format cannot be combined with --sbom-format")
+	assert.Nil(t, mockPlugin.options, "no plugin may run with conflicting output formats")
+}
+
+func Test_handleSBOMResolutionDI_streamOutputWritesEachResult(t *testing.T) {
+	ctx := setupTestContext(t, true)
+	ctx.config.Set(workflow.FlagAllProjects, true)
+	streamPath := filepath.Join(t.TempDir(), "graphs.jsonl")
+	ctx.config.Set(workflow.FlagStreamOutput, streamPath)
+
+	mockPlugin := &mockScaPlugin{
+		name: "mock",
+		results: []ecosystems.SCAResult{
+			{
+				DepGraph:          createTestDepGraph(t, "pip", "project-a", "1.0.0"),
+				ProjectDescriptor: identity.ProjectDescriptor{Identity: identity.ProjectIdentity{TargetFile: stringPtr("requirements.txt")}},
+				ResolverMetadata:  &ecosystems.ResolverMetadata{NormalisedTargetFile: "a/requirements.txt"},
+			},
+			{
+				ResolverMetadata: &ecosystems.ResolverMetadata{NormalisedTargetFile: "b/requirements.txt"},
+				Error:            snyk_errors.Error{Title: "Resolution failed", Detail: "python not found"},
+			},
+		},
+	}
+
+	workflowData, err := handleSBOMResolutionDI(ctx.invocationContext, ctx.config, &nopLogger, []ecosystems.SCAPlugin{mockPlugin})
+	require.NoError(t, err)
+	assert.Empty(t, workflowData, "streamed results are not also returned")
+
+	content, err := os.ReadFile(streamPath)
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(content)), "\n")
+	require.Len(t, lines, 2)
+
+	var resolved struct {
+		DepGraph             *dg.DepGraph `json:"depGraph"`
+		NormalisedTargetFile string       `json:"normalisedTargetFile"`
+		TargetFileFromPlugin string       `json:"targetFileFromPlugin"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(lines[0]), &resolved))
+	assert.Equal(t, "a/requirements.txt", resolved.NormalisedTargetFile)
+	assert.Equal(t, "requirements.txt", resolved.TargetFileFromPlugin)
+	require.NotNil(t, resolved.DepGraph)
+	assert.Equal(t, "pip", resolved.DepGraph.PkgManager.Name)
+
+	var failed struct {
+		NormalisedTargetFile string          `json:"normalisedTargetFile"`
+		Error                json.RawMessage `json:"error"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(lines[1]), &failed))
+	assert.Equal(t, "b/requirements.txt", failed.NormalisedTargetFile)
+	errs, err := snyk_errors.FromJSONAPIErrorBytes(failed.Error)
+	require.NoError(t, err)
+	require.Len(t, errs, 1)
+	assert.Equal(t, "python not found", errs[0].Detail)
+}
+
+func Test_handleSBOMResolutionDI_streamOutputToStdoutUsesUserInterface(t *testing.T) {
+	ctx := setupTestContext(t, true)
+	ctx.config.Set(workflow.FlagStreamOutput, "-")
+	var lines []string
+	ctx.userInterface.EXPECT().Output(gomock.Any()).DoAndReturn(func(output string) error {
+		lines = append(lines, output)
+		return nil
+	}).Times(2)
+
+	mockPlugin := &mockScaPlugin{
+		name: "mock",
+		results: []ecosystems.SCAResult{
+			{
+				DepGraph:         createTestDepGraph(t, "pip", "project-a", "1.0.0"),
+				ResolverMetadata: &ecosystems.ResolverMetadata{NormalisedTargetFile: "a/requirements.txt"},
+			},
+			{
+				DepGraph:         createTestDepGraph(t, "pip", "project-b", "1.0.0"),
+				ResolverMetadata: &ecosystems.ResolverMetadata{NormalisedTargetFile: "b/requirements.txt"},
+			},
+		},
+	}
+
+	workflowData, err := handleSBOMResolutionDI(ctx.invocationContext, ctx.config, &nopLogger, []ecosystems.SCAPlugin{mockPlugin})
+	require.NoError(t, err)
+	assert.Empty(t, workflowData)
+
+	require.Len(t, lines, 2)
+	for i, targetFile := range []string{"a/requirements.txt", "b/requirements.txt"} {
+		assert.NotContains(t, lines[i], "\n", "the user interface ends each line")
+		var line struct {
+			NormalisedTargetFile string `json:"normalisedTargetFile"`
+		}
+		require.NoError(t, json.Unmarshal([]byte(lines[i]), &line))
+		assert.Equal(t, targetFile, line.NormalisedTargetFile)
+	}
+}
+
+func Test_handleSBOMResolutionDI_streamOutputRejectsSBOMFormat(t *testing.T) {
+	ctx := setupTestContext(t, true)
+	ctx.config.Set(workflow.FlagStreamOutput, "-")
+	ctx.config.Set(workflow.FlagSBOMFormat, "cyclonedx1.6+json")
+	mockPlugin := &mockScaPlugin{name: "mock"}
+
+	_, err := handleSBOMResolutionDI(ctx.invocationContext, ctx.config, &nopLogger, []ecosystems.SCAPlugin{mockPlugin})
+
+	require.ErrorContains(t, err, "--stream-output cannot be combined with --sbom-format")
+}
+
+func Test_handleSBOMResolutionDI_withErrorsRejectsDocumentFormats(t *testing.T) {
+	for _, withErrorsFlag := range []string{workflow.FlagPrintOutputJsonlWithErrors, workflow.FlagPrintEffectiveGraphWithErrors} {
+		for format, value := range map[string]string{workflow.FlagSBOMFormat: "cyclonedx1.6+json", workflow.FlagGraphFormat: "dot"} {
+			t.Run(withErrorsFlag+" with "+format, func(t *testing.T) {
+				ctx := setupTestContext(t, true)
+				ctx.config.Set(withErrorsFlag, true)
+				ctx.config.Set(format, value)
+				mockPlugin := &mockScaPlugin{name: "mock"}
+
+				_, err := handleSBOMResolutionDI(ctx.invocationContext, ctx.config, &nopLogger, []ecosystems.SCAPlugin{mockPlugin})
+
+				require.ErrorContains(t, err, fmt.Sprintf("--%s cannot be combined with --%s", withErrorsFlag, format))
+				assert.Nil(t, mockPlugin.options, "no plugin may run when failed results would be dropped")
+			})
+		}
+	}
+}
+
+func Test_handleSBOMResolutionDI_problemsReportReplacesGraphsAndWarning(t *testing.T) {
+	// No OutputError expectation: the rendered warning must not be printed.
+	ctx := setupTestContext(t, false)
+	ctx.config.Set(workflow.FlagAllProjects, true)
+	ctx.config.Set(workflow.FlagProblemsReport, true)
+
+	mockPlugin := &mockScaPlugin{
+		name: "mock",
+		results: []ecosystems.SCAResult{
+			{
+				DepGraph:         createTestDepGraph(t, "pip", "project-a", "1.0.0"),
+				ResolverMetadata: &ecosystems.ResolverMetadata{PluginName: "pip", NormalisedTargetFile: "a/requirements.txt"},
+			},
+			{
+				ResolverMetadata: &ecosystems.ResolverMetadata{PluginName: "pip", NormalisedTargetFile: "b/requirements.txt"},
+				Error: snyk_errors.Error{
+					ErrorCode:      "SNYK-OS-PYTHON-0001",
+					Title:          "Python not found",
+					Detail:         "python3 is not on PATH",
+					Classification: "ACTIONABLE",
+					Cause:          fmt.Errorf("exec: %w", errors.New("executable file not found")),
+				},
+			},
+			{
+				ResolverMetadata: &ecosystems.ResolverMetadata{PluginName: "cargo", NormalisedTargetFile: "Cargo.lock"},
+				Error:            errors.New("failed to parse lockfile"),
+			},
+		},
+	}
+
+	workflowData, err := handleSBOMResolutionDI(ctx.invocationContext, ctx.config, &nopLogger, []ecosystems.SCAPlugin{mockPlugin})
+	require.NoError(t, err)
+
+	require.Len(t, workflowData, 1, "the CLI only prints the first workflow.Data")
+	problems := workflowData[0]
+	assert.Equal(t, ProblemsDataTypeID, problems.GetIdentifier())
+	assert.Equal(t, workflow.ContentTypeProblemsJSON, problems.GetContentType())
+
+	var report problemsReport
+	require.NoError(t, json.Unmarshal(problems.GetPayload().([]byte), &report))
+	assert.Equal(t, 3, report.Total)
+	assert.Equal(t, 2, report.Failed)
+	assert.Equal(t, []problem{
+		{
+			TargetFile:     "b/requirements.txt",
+			PluginName:     "pip",
+			Code:           "SNYK-OS-PYTHON-0001",
+			Title:          "Python not found",
+			Detail:         "python3 is not on PATH",
+			Classification: "ACTIONABLE",
+			Message:        "Python not found",
+			Causes:         []string{"exec: executable file not found", "executable file not found"},
+		},
+		{
+			TargetFile: "Cargo.lock",
+			PluginName: "cargo",
+			Message:    "failed to parse lockfile",
+		},
+	}, report.Problems)
+}
+
+func Test_handleSBOMResolutionDI_jsonlWithErrorsReturnsFailedResults(t *testing.T) {
+	// No OutputError expectation: errors travel with the data, not as a warning.
+	ctx := setupTestContext(t, false)
+	ctx.config.Set(workflow.FlagPrintOutputJsonlWithErrors, true)
+
+	mockPlugin := &mockScaPlugin{
+		name: "mock",
+		results: []ecosystems.SCAResult{
+			{
+				ResolverMetadata: &ecosystems.ResolverMetadata{NormalisedTargetFile: "a/uv.lock"},
+				Error:            snyk_errors.Error{ErrorCode: "SNYK-TEST-001", Title: "Out of sync", Detail: "uv.lock is out of sync"},
+			},
+			{
+				ProjectDescriptor: identity.ProjectDescriptor{Identity: identity.ProjectIdentity{TargetFile: stringPtr("Cargo.lock")}},
+				ResolverMetadata:  &ecosystems.ResolverMetadata{NormalisedTargetFile: "b/Cargo.lock"},
+				Error:             errors.New("cargo metadata failed"),
+			},
+			{
+				DepGraph:         createTestDepGraph(t, "pip", "project-c", "1.0.0"),
+				ResolverMetadata: &ecosystems.ResolverMetadata{NormalisedTargetFile: "c/requirements.txt"},
+			},
+		},
+	}
+
+	output, err := handleSBOMResolutionDI(ctx.invocationContext, ctx.config, &nopLogger, []ecosystems.SCAPlugin{mockPlugin})
+	require.NoError(t, err, "failed results do not fail the run, even without --all-projects")
+	require.Len(t, output, 1, "every result is one line of a single JSONL item")
+	assert.Equal(t, workflow.ContentTypeJSONL, output[0].GetContentType())
+
+	// The lines read back like the legacy CLI's JSONL-with-errors output.
+	lines, err := parsers.NewJSONL().ParseOutput(output[0].GetPayload().([]byte))
+	require.NoError(t, err)
+	workflowData := legacycli.MapToWorkflowData(lines, &nopLogger)
+	require.Len(t, workflowData, 3)
+
+	first := workflowData[0]
+	assert.Nil(t, first.GetPayload())
+	normalised, err := first.GetMetaData(workflow.MetaKeyNormalisedTargetFile)
+	require.NoError(t, err)
+	assert.Equal(t, "a/uv.lock", normalised)
+	require.Len(t, first.GetErrorList(), 1)
+	assert.Equal(t, "SNYK-TEST-001", first.GetErrorList()[0].ErrorCode)
+
+	second := workflowData[1]
+	fromPlugin, err := second.GetMetaData(workflow.MetaKeyTargetFileFromPlugin)
+	require.NoError(t, err)
+	assert.Equal(t, "Cargo.lock", fromPlugin)
+	require.Len(t, second.GetErrorList(), 1)
+	assert.Equal(t, "cargo metadata failed", second.GetErrorList()[0].Detail, "errors outside the catalog are wrapped")
+
+	assert.Empty(t, workflowData[2].GetErrorList())
+	assert.NotEmpty(t, workflowData[2].GetPayload())
+}
+
+func Test_handleSBOMResolutionDI_effectiveGraphPrunesRepeatedSubdependencies(t *testing.T) {
+	ctx := setupTestContext(t, true)
+	ctx.config.Set(workflow.FlagPrintEffectiveGraph, true)
+
+	builder, err := dg.NewBuilder(&dg.PkgManager{Name: "cargo"}, &dg.PkgInfo{Name: "app", Version: "1.0.0"})
+	require.NoError(t, err)
+	root := builder.GetRootNode().NodeID
+	for _, id := range []string{"a", "b", "shared", "leaf"} {
+		builder.AddNode(id, &dg.PkgInfo{Name: id, Version: "1.0.0"})
+	}
+	for _, edge := range [][2]string{{root, "a"}, {root, "b"}, {"a", "shared"}, {"b", "shared"}, {"shared", "leaf"}} {
+		require.NoError(t, builder.ConnectNodes(edge[0], edge[1]))
+	}
+
+	mockPlugin := &mockScaPlugin{
+		name: "mock",
+		results: []ecosystems.SCAResult{{
+			DepGraph:         builder.Build(),
+			ResolverMetadata: &ecosystems.ResolverMetadata{NormalisedTargetFile: "Cargo.lock"},
+		}},
+	}
+
+	workflowData, err := handleSBOMResolutionDI(ctx.invocationContext, ctx.config, &nopLogger, []ecosystems.SCAPlugin{mockPlugin})
+	require.NoError(t, err)
+	require.Len(t, workflowData, 1)
+
+	graph, err := dg.UnmarshalJSON(workflowData[0].GetPayload().([]byte))
+	require.NoError(t, err)
+	var prunedLabels []string
+	for _, node := range graph.Graph.Nodes {
+		if node.Info != nil && node.Info.Labels[ecosystems.PrunedLabel] == "true" {
+			prunedLabels = append(prunedLabels, node.PkgID)
+		}
+		if node.NodeID == "b" {
+			assert.Equal(t, []dg.Dependency{{NodeID: "shared@1.0.0:pruned"}}, node.Deps)
+		}
+	}
+	assert.Equal(t, []string{"shared@1.0.0"}, prunedLabels)
+}
@@ -0,0 +1,123 @@
+package depgraph
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/snyk/dep-graph/go/pkg/depgraph"
+	"github.com/snyk/go-application-framework/pkg/configuration"
+	"github.com/snyk/go-application-framework/pkg/ui"
+
+	"github.com/snyk/cli-extension-dep-graph/v2/internal/workflow"
+	"github.com/snyk/cli-extension-dep-graph/v2/pkg/ecosystems"
+)
+
+// streamStdout is the --stream-output value that writes to the invocation's
+// standard output, through its user interface.
+const streamStdout = "-"
+
+// resultLine is one result as --stream-output and
+// --print-output-jsonl-with-errors write it, in the line format of the legacy
+// CLI's JSONL output so the same parsers read all of them.
+type resultLine struct {
+	DepGraph             *depgraph.DepGraph `json:"depGraph,omitempty"`
+	NormalisedTargetFile string             `json:"normalisedTargetFile"`
+	TargetFileFromPlugin *string            `json:"targetFileFromPlugin,omitempty"`
+	Error                json.RawMessage    `json:"error,omitempty"`
+}
+
+// streamWriter writes each result as a JSONL line as soon as the registry
+// produces it, so dep-graphs are not held in memory until the run completes.
+type streamWriter struct {
+	// writeLine writes one line, given without its trailing newline.
+	writeLine func(line []byte) error
+	closer    io.Closer
+}
+
+// streamWriterFromConfig opens the destination --stream-output names, or
+// returns nil if it is unset and results should be returned as workflow data.
+func streamWriterFromConfig(config configuration.Configuration, userInterface ui.UserInterface) (*streamWriter, error) {
+	target := config.GetString(workflow.FlagStreamOutput)
+	if target == "" {
+		return nil, nil //nolint:nilnil // no destination selected is not an error
+	}
+	if config.GetString(workflow.FlagSBOMFormat) != "" {
+		return nil, fmt.Errorf("--%s cannot be combined with --%s", workflow.FlagStreamOutput, workflow.FlagSBOMFormat)
+	}
+	if target == streamStdout {
+		// Output ends every line itself.
+		return &streamWriter{writeLine: func(line []byte) error { return userInterface.Output(string(line)) }}, nil
+	}
+
+	file, err := os.Create(target)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create --%s file: %w", workflow.FlagStreamOutput, err)
+	}
+	writeLine := func(line []byte) error {
+		_, err := file.Write(append(line, '\n'))
+		return err //nolint:wrapcheck // wrapped by write
+	}
+	return &streamWriter{writeLine: writeLine, closer: file}, nil
+}
+
+// write writes result as one line.
+func (s *streamWriter) write(result *ecosystems.SCAResult) error {
+	lineBytes, err := marshalResultLine(result)
+	if err != nil {
+		return err
+	}
+	if err := s.writeLine(lineBytes); err != nil {
+		return fmt.Errorf("failed to write streamed result: %w", err)
+	}
+	return nil
+}
+
+// Close closes the destination file, if the writer opened one. It is safe to
+// call more than once.
+func (s *streamWriter) Close() error {
+	if s.closer == nil {
+		return nil
+	}
+	closer := s.closer
+	s.closer = nil
+	if err := closer.Close(); err != nil {
+		return fmt.Errorf("failed to close --%s file: %w", workflow.FlagStreamOutput, err)
+	}
+	return nil
+}
+
+// marshalResultLine serializes result as a resultLine, without the trailing
+// newline. A result that failed carries its error in the JSON:API format of
+// the error catalog instead of a dep-graph.
+func marshalResultLine(result *ecosystems.SCAResult) ([]byte, error) {
+	line := resultLine{
+		DepGraph:             result.DepGraph,
+		NormalisedTargetFile: result.ResolverMetadata.NormalisedTargetFile,
+		TargetFileFromPlugin: result.ProjectDescriptor.Identity.TargetFile,
+	}
+	if result.Error != nil {
+		errBytes, err := marshalResultError(result.Error)
+		if err != nil {
+			return nil, err
+		}
+		line.Error = errBytes
+	}
+
+	lineBytes, err := json.Marshal(line)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal result line: %w", err)
+	}
+	return lineBytes, nil
+}
+
+// marshalResultError serializes err as a JSON:API error document.
+func marshalResultError(err error) (json.RawMessage, error) {
+	var buf bytes.Buffer
+	if marshalErr := catalogError(err).MarshalToJSONAPIError(&buf, ""); marshalErr != nil {
+		return nil, fmt.Errorf("failed to marshal result error: %w", marshalErr)
+	}
+	return bytes.TrimSpace(buf.Bytes()), nil
+}
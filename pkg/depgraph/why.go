@@ -0,0 +1,60 @@
+package depgraph
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"github.com/snyk/go-application-framework/pkg/configuration"
+	gafworkflow "github.com/snyk/go-application-framework/pkg/workflow"
+
+	"github.com/snyk/cli-extension-dep-graph/v2/internal/workflow"
+	"github.com/snyk/cli-extension-dep-graph/v2/pkg/graphwhy"
+)
+
+// whyCallback scans the input directory and lists the paths from the root of
+// each resolved dep-graph to the package --package names.
+func whyCallback(ctx gafworkflow.InvocationContext, _ []gafworkflow.Data) ([]gafworkflow.Data, error) {
+	config := ctx.GetConfiguration()
+	logger := ctx.GetEnhancedLogger()
+
+	logger.Print("DepGraph why workflow start")
+
+	spec := config.GetString(workflow.FlagWhyPackage)
+	if spec == "" {
+		return nil, fmt.Errorf("--%s is required", workflow.FlagWhyPackage)
+	}
+	query, err := graphwhy.ParseQuery(spec)
+	if err != nil {
+		return nil, fmt.Errorf("invalid --%s %q: %w", workflow.FlagWhyPackage, spec, err)
+	}
+
+	inputDir := config.GetString(configuration.INPUT_DIRECTORY)
+	if inputDir == "" {
+		inputDir = "."
+	}
+	// Plugins add node labels explaining why packages are present, which the
+	// tree and JSON output list with each step.
+	scanConfig := config.Clone()
+	scanConfig.Set(workflow.FlagWhyLabels, true)
+	projects, err := scanProjects(ctx, scanConfig, inputDir)
+	if err != nil {
+		return nil, err
+	}
+
+	report := graphwhy.Explain(projects, query, config.GetInt(workflow.FlagMaxPaths))
+
+	if config.GetBool(workflow.FlagJSON) {
+		reportBytes, err := json.Marshal(report)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal why report: %w", err)
+		}
+		return []gafworkflow.Data{gafworkflow.NewData(WhyDataTypeID, workflow.ContentTypeJSON, reportBytes)}, nil
+	}
+
+	var buf bytes.Buffer
+	if err := graphwhy.WriteTree(&buf, report); err != nil {
+		return nil, fmt.Errorf("failed to render why report: %w", err)
+	}
+	return []gafworkflow.Data{gafworkflow.NewData(WhyDataTypeID, workflow.ContentTypeText, buf.Bytes())}, nil
+}
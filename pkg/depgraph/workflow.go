@@ -16,6 +16,39 @@ var (
 	// DataTypeID is the unique identifier for the data type that is being returned
 	// from this workflow.
 	DataTypeID = workflow.DataTypeID
+
+	// DetectionDataTypeID is the unique identifier for the project detection
+	// report returned instead of dep-graphs when --detect-only is set.
+	DetectionDataTypeID = workflow.DetectionDataTypeID
+
+	// SBOMDataTypeID is the unique identifier for the SBOM document returned
+	// instead of dep-graphs when --sbom-format is set.
+	SBOMDataTypeID = workflow.SBOMDataTypeID
+
+	// ProblemsDataTypeID is the unique identifier for the report of projects
+	// that failed to resolve, returned instead of dep-graphs when
+	// --problems-report is set.
+	ProblemsDataTypeID = workflow.ProblemsDataTypeID
+
+	// GraphDataTypeID is the unique identifier for the DOT or Mermaid diagrams
+	// returned instead of dep-graphs when --graph-format is set.
+	GraphDataTypeID = workflow.GraphDataTypeID
+
+	// DiffWorkflowID is the unique identifier for the `depgraph diff`
+	// subcommand, which compares the dep-graphs of two scans.
+	DiffWorkflowID = workflow.DiffWorkflowID
+
+	// DiffDataTypeID is the unique identifier for the diff report returned from
+	// the diff workflow.
+	DiffDataTypeID = workflow.DiffDataTypeID
+
+	// WhyWorkflowID is the unique identifier for the `depgraph why`
+	// subcommand, which lists the dependency paths to a package.
+	WhyWorkflowID = workflow.WhyWorkflowID
+
+	// WhyDataTypeID is the unique identifier for the path report returned from
+	// the why workflow.
+	WhyDataTypeID = workflow.WhyDataTypeID
 )
 
 // Init initializes the DepGraph workflow.
@@ -30,5 +63,21 @@ func Init(engine gafworkflow.Engine) error {
 		return fmt.Errorf("failed to register workflow: %w", err)
 	}
 
+	_, err = engine.Register(
+		DiffWorkflowID,
+		gafworkflow.ConfigurationOptionsFromFlagset(getDiffFlagSet()),
+		diffCallback)
+	if err != nil {
+		return fmt.Errorf("failed to register diff workflow: %w", err)
+	}
+
+	_, err = engine.Register(
+		WhyWorkflowID,
+		gafworkflow.ConfigurationOptionsFromFlagset(getWhyFlagSet()),
+		whyCallback)
+	if err != nil {
+		return fmt.Errorf("failed to register why workflow: %w", err)
+	}
+
 	return nil
 }
@@ -6,6 +6,7 @@ import (
 	"github.com/snyk/go-application-framework/pkg/configuration"
 	"github.com/snyk/go-application-framework/pkg/workflow"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func Test_Depgraph_Init(t *testing.T) {
@@ -24,3 +25,28 @@ func Test_Depgraph_Init(t *testing.T) {
 	useSBOMResolution := config.Get("use-sbom-resolution")
 	assert.Equal(t, false, useSBOMResolution)
 }
+
+func Test_Depgraph_Init_registersDiff(t *testing.T) {
+	config := configuration.New()
+	engine := workflow.NewWorkFlowEngine(config)
+
+	require.NoError(t, Init(engine))
+
+	_, ok := engine.GetWorkflow(DiffWorkflowID)
+	assert.True(t, ok)
+	assert.Equal(t, "", config.Get("from"))
+	assert.Equal(t, "", config.Get("to"))
+	assert.Equal(t, false, config.Get("json"))
+}
+
+func Test_Depgraph_Init_registersWhy(t *testing.T) {
+	config := configuration.New()
+	engine := workflow.NewWorkFlowEngine(config)
+
+	require.NoError(t, Init(engine))
+
+	_, ok := engine.GetWorkflow(WhyWorkflowID)
+	assert.True(t, ok)
+	assert.Equal(t, "", config.Get("package"))
+	assert.Equal(t, 100, config.Get("max-paths"))
+}
@@ -2,7 +2,9 @@ package bazel
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io/fs"
 	"os"
 	"path/filepath"
 	"strings"
@@ -35,7 +37,7 @@ type goResolver struct {
 }
 
 func newGoResolver(dir string) (bazelDependencyResolver, error) {
-	lookup, err := createGoLookup(filepath.Join(dir, goModFilename))
+	lookup, err := createGoLookup(os.DirFS(dir), goModFilename)
 	if err != nil {
 		return nil, err
 	}
@@ -52,10 +54,13 @@ func newGoResolver(dir string) (bazelDependencyResolver, error) {
 // Path-only replaces (e.g. "replace foo => ../local/foo") have no version and
 // can't be represented as a vuln-scannable coordinate; we keep the original
 // require entry in those cases.
-func createGoLookup(path string) (goLookup, error) {
-	data, err := os.ReadFile(path)
+//
+// The go.mod is read from fsys, so the lookup can be built from an archive or
+// an in-memory tree as well as from disk.
+func createGoLookup(fsys fs.FS, path string) (goLookup, error) {
+	data, err := fs.ReadFile(fsys, path)
 	if err != nil {
-		if os.IsNotExist(err) {
+		if errors.Is(err, fs.ErrNotExist) {
 			return nil, fmt.Errorf("required file does not exist: %s", path)
 		}
 		return nil, fmt.Errorf("read %s: %w", path, err)
@@ -121,6 +126,10 @@ func (r *goResolver) packageManagerName() string {
 
 // processedFiles reports go.mod and go.sum as consumed so that the legacy CLI does not
 // re-scan it after the Bazel resolver has already produced a dep-graph from it.
+func (r *goResolver) lockfile() string {
+	return goModFilename
+}
+
 func (r *goResolver) processedFiles() []string {
 	return []string{
 		filepath.Join(r.dir, goModFilename),
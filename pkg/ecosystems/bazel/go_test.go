@@ -1,9 +1,8 @@
 package bazel
 
 import (
-	"os"
-	"path/filepath"
 	"testing"
+	"testing/fstest"
 
 	"github.com/snyk/dep-graph/go/pkg/depgraph"
 	"github.com/stretchr/testify/require"
@@ -89,10 +88,9 @@ go 1.22
 		t.Run(tt.name, func(t *testing.T) {
 			t.Parallel()
 
-			path := filepath.Join(t.TempDir(), "go.mod")
-			require.NoError(t, os.WriteFile(path, []byte(tt.goMod), 0o600))
+			fsys := fstest.MapFS{"go.mod": {Data: []byte(tt.goMod)}}
 
-			actual, err := createGoLookup(path)
+			actual, err := createGoLookup(fsys, "go.mod")
 			require.NoError(t, err)
 			require.Equal(t, tt.expected, actual)
 		})
@@ -102,7 +100,7 @@ go 1.22
 func Test_createGoLookup_FileNotFound(t *testing.T) {
 	t.Parallel()
 
-	_, err := createGoLookup(filepath.Join(t.TempDir(), "missing.mod"))
+	_, err := createGoLookup(fstest.MapFS{}, "missing.mod")
 	require.Error(t, err)
 	require.Contains(t, err.Error(), "required file does not exist")
 }
@@ -110,10 +108,9 @@ func Test_createGoLookup_FileNotFound(t *testing.T) {
 func Test_createGoLookup_InvalidContent(t *testing.T) {
 	t.Parallel()
 
-	path := filepath.Join(t.TempDir(), "go.mod")
-	require.NoError(t, os.WriteFile(path, []byte("this is not a go.mod"), 0o600))
+	fsys := fstest.MapFS{"go.mod": {Data: []byte("this is not a go.mod")}}
 
-	_, err := createGoLookup(path)
+	_, err := createGoLookup(fsys, "go.mod")
 	require.Error(t, err)
 	require.Contains(t, err.Error(), "failed to parse")
 }
@@ -3,9 +3,10 @@ package bazel
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io/fs"
 	"os"
-	"path/filepath"
 	"strings"
 
 	"github.com/snyk/dep-graph/go/pkg/depgraph"
@@ -13,6 +14,9 @@ import (
 	"github.com/snyk/cli-extension-dep-graph/v2/pkg/ecosystems"
 )
 
+// mavenInstallFilename is the rules_jvm_external lockfile.
+const mavenInstallFilename = "maven_install.json"
+
 // label is an identifier for a Bazel target.
 // See https://bazel.build/concepts/labels
 type label string
@@ -37,18 +41,19 @@ type mavenInstallJSON struct {
 }
 
 func newJVMExternalResolver(dir string) (bazelDependencyResolver, error) {
-	path := filepath.Join(dir, "maven_install.json")
-	lookup, err := createMavenLookup(path)
+	lookup, err := createMavenLookup(os.DirFS(dir), mavenInstallFilename)
 	if err != nil {
 		return nil, err
 	}
 	return &jvmExternalResolver{dir, lookup}, nil
 }
 
-func createMavenLookup(path string) (mavenLookup, error) {
-	data, err := os.ReadFile(path)
+// createMavenLookup reads the rules_jvm_external lockfile at path in fsys and
+// builds the label → PkgInfo lookup.
+func createMavenLookup(fsys fs.FS, path string) (mavenLookup, error) {
+	data, err := fs.ReadFile(fsys, path)
 	if err != nil {
-		if os.IsNotExist(err) {
+		if errors.Is(err, fs.ErrNotExist) {
 			return nil, fmt.Errorf("required file does not exist: %s", path)
 		}
 		return nil, fmt.Errorf("read %s: %w", path, err)
@@ -126,6 +131,10 @@ func (r *jvmExternalResolver) packageManagerName() string {
 	return "maven"
 }
 
+func (r *jvmExternalResolver) lockfile() string {
+	return mavenInstallFilename
+}
+
 // processedFiles returns empty because rules_jvm_external projects have no
 // pom.xml or build.gradle files that the legacy CLI would otherwise re-scan.
 func (r *jvmExternalResolver) processedFiles() []string {
@@ -2,10 +2,37 @@ package bazel
 
 import (
 	"testing"
+	"testing/fstest"
 
+	"github.com/snyk/dep-graph/go/pkg/depgraph"
 	"github.com/stretchr/testify/require"
 )
 
+func Test_createMavenLookup(t *testing.T) {
+	t.Parallel()
+
+	fsys := fstest.MapFS{"maven_install.json": {Data: []byte(`{
+		"artifacts": {
+			"com.google.guava:guava": {"version": "32.1.2-jre"},
+			"": {"version": "1.0.0"}
+		}
+	}`)}}
+
+	actual, err := createMavenLookup(fsys, "maven_install.json")
+	require.NoError(t, err)
+	require.Equal(t, mavenLookup{
+		"com_google_guava_guava": depgraph.PkgInfo{Name: "com.google.guava:guava", Version: "32.1.2-jre"},
+	}, actual)
+}
+
+func Test_createMavenLookup_FileNotFound(t *testing.T) {
+	t.Parallel()
+
+	_, err := createMavenLookup(fstest.MapFS{}, "maven_install.json")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "required file does not exist")
+}
+
 func Test_parseArtifactName(t *testing.T) {
 	t.Parallel()
 
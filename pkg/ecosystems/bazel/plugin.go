@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"path/filepath"
 
 	"github.com/snyk/cli-extension-dep-graph/v2/pkg/ecosystems"
 	"github.com/snyk/cli-extension-dep-graph/v2/pkg/ecosystems/logger"
@@ -20,8 +21,11 @@ const (
 
 type Plugin struct{}
 
-// Ensures this Plugin satisfies the SCAPlugin interface.
-var _ ecosystems.SCAPlugin = new(Plugin)
+// Ensures this Plugin satisfies the SCAPlugin and ProjectDetector interfaces.
+var (
+	_ ecosystems.SCAPlugin       = new(Plugin)
+	_ ecosystems.ProjectDetector = new(Plugin)
+)
 
 func (p Plugin) GetName() string {
 	return pluginName
@@ -98,6 +102,40 @@ func (p Plugin) BuildDepGraphsFromDir(
 	return nil
 }
 
+// DetectProjects reports the Bazel workspace BuildDepGraphsFromDir would
+// resolve, without invoking Bazel. Its targets are only known from a Bazel
+// query, so the workspace is reported once, by the lockfile the selected
+// resolver reads versions from. Without --bazel-jvm or --bazel-go nothing is
+// detected, as nothing would be resolved.
+func (p Plugin) DetectProjects(
+	_ context.Context, _ logger.Logger, dir string, options *ecosystems.SCAPluginOptions,
+	onProject ecosystems.OnDetectFunc,
+) error {
+	resolver, err := newResolverFromOptions(dir, options)
+	if err != nil {
+		if errors.Is(err, errNoBazelOptionFound) {
+			return nil
+		}
+		return fmt.Errorf("failed to initialize bazel resolver: %w", err)
+	}
+
+	processed := resolver.processedFiles()
+	claimed := make([]string, 0, len(processed))
+	for _, file := range processed {
+		rel, err := filepath.Rel(dir, file)
+		if err != nil {
+			return fmt.Errorf("failed to resolve %s relative to %s: %w", file, dir, err)
+		}
+		claimed = append(claimed, rel)
+	}
+
+	return onProject(ecosystems.DetectedProject{
+		TargetFile:   resolver.lockfile(),
+		ProjectType:  resolver.packageManagerName(),
+		ClaimedFiles: claimed,
+	})
+}
+
 // checkTargetLimit returns an error when the number of discovered targets
 // exceeds the configured ceiling. The ceiling defaults to defaultMaxTargets;
 // an explicit --bazel-max-targets value overrides it, and a value of 0
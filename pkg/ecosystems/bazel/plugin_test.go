@@ -1,12 +1,16 @@
 package bazel
 
 import (
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
 	"github.com/snyk/cli-extension-dep-graph/v2/pkg/ecosystems"
+	"github.com/snyk/cli-extension-dep-graph/v2/pkg/ecosystems/logger"
+	"github.com/snyk/cli-extension-dep-graph/v2/pkg/ecosystems/scatest"
 )
 
 func Test_checkTargetLimit(t *testing.T) {
@@ -76,3 +80,46 @@ func Test_checkTargetLimit(t *testing.T) {
 		})
 	}
 }
+
+func TestPlugin_DetectProjects(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, goModFilename), []byte("module example.com/app\n\ngo 1.22\n"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, mavenInstallFilename), []byte(`{"artifacts": {}}`), 0o600))
+
+	t.Run("go resolver reports the workspace by go.mod", func(t *testing.T) {
+		t.Parallel()
+		got, err := scatest.Detect(t.Context(), Plugin{}, logger.Nop(), dir, ecosystems.NewPluginOptions().WithBazelGo(true))
+		require.NoError(t, err)
+		assert.Equal(t, []ecosystems.DetectedProject{{
+			TargetFile:   goModFilename,
+			ProjectType:  "gomodules",
+			ClaimedFiles: []string{goModFilename, goSumFilename},
+		}}, got)
+	})
+
+	t.Run("jvm resolver reports the workspace by maven_install.json", func(t *testing.T) {
+		t.Parallel()
+		got, err := scatest.Detect(t.Context(), Plugin{}, logger.Nop(), dir, ecosystems.NewPluginOptions().WithBazelJvm(true))
+		require.NoError(t, err)
+		assert.Equal(t, []ecosystems.DetectedProject{{
+			TargetFile:   mavenInstallFilename,
+			ProjectType:  "maven",
+			ClaimedFiles: []string{},
+		}}, got)
+	})
+
+	t.Run("no bazel flag detects nothing", func(t *testing.T) {
+		t.Parallel()
+		got, err := scatest.Detect(t.Context(), Plugin{}, logger.Nop(), dir, ecosystems.NewPluginOptions())
+		require.NoError(t, err)
+		assert.Empty(t, got)
+	})
+
+	t.Run("missing lockfile is an error", func(t *testing.T) {
+		t.Parallel()
+		_, err := scatest.Detect(t.Context(), Plugin{}, logger.Nop(), t.TempDir(), ecosystems.NewPluginOptions().WithBazelGo(true))
+		require.ErrorContains(t, err, "required file does not exist")
+	})
+}
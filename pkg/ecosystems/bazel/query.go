@@ -6,6 +6,8 @@ import (
 	"errors"
 	"fmt"
 	"os/exec"
+
+	"github.com/snyk/cli-extension-dep-graph/v2/pkg/ecosystems"
 )
 
 // TODO: support custom bazel command? like bazelisk or custom path to bazel binary.
@@ -27,9 +29,13 @@ type queryResults struct {
 }
 
 func bazelQuery(ctx context.Context, dir, query string) (*queryResults, error) {
-	cmd := exec.CommandContext(ctx, bazelCommand, "cquery", query, "--output=jsonproto")
+	cmdCtx, cancel := ecosystems.CommandContext(ctx, bazelCommand)
+	defer cancel()
+
+	cmd := exec.CommandContext(cmdCtx, bazelCommand, "cquery", query, "--output=jsonproto")
 	cmd.Dir = dir
 	out, err := cmd.Output()
+	err = ecosystems.CommandError(cmdCtx, err)
 	if err != nil {
 		var exitErr *exec.ExitError
 		if errors.As(err, &exitErr) {
@@ -15,6 +15,9 @@ type bazelDependencyResolver interface {
 	findTargets(ctx context.Context, options *ecosystems.SCAPluginOptions) ([]string, error)
 	buildDepGraph(ctx context.Context, targetName string) (*depgraph.DepGraph, error)
 	processedFiles() []string
+	// lockfile is the file, relative to the project directory, that the
+	// resolver reads its versions from.
+	lockfile() string
 }
 
 const (
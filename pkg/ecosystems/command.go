@@ -0,0 +1,56 @@
+package ecosystems
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// CommandTimeoutError is the context cause when --command-timeout expires
+// for one external command a plugin runs, such as a package manager or build
+// tool invocation.
+type CommandTimeoutError struct {
+	Command string
+	Timeout time.Duration
+}
+
+func (e *CommandTimeoutError) Error() string {
+	return fmt.Sprintf("%s did not finish within %s", e.Command, e.Timeout)
+}
+
+type commandTimeoutKey struct{}
+
+// ContextWithCommandTimeout returns ctx carrying the limit CommandContext puts
+// on each external command run with it. The plugin registry sets it from
+// GlobalOptions.CommandTimeout; a zero timeout adds no limit.
+func ContextWithCommandTimeout(ctx context.Context, timeout time.Duration) context.Context {
+	return context.WithValue(ctx, commandTimeoutKey{}, timeout)
+}
+
+// CommandContext derives the context to run one invocation of the external
+// command name with. Once the command timeout carried by ctx elapses it ends
+// with a *CommandTimeoutError cause, so exec.CommandContext kills the command.
+// Plugins call it for every subprocess they start, and pass the command's
+// error through CommandError.
+func CommandContext(ctx context.Context, name string) (context.Context, context.CancelFunc) {
+	timeout, _ := ctx.Value(commandTimeoutKey{}).(time.Duration)
+	if timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeoutCause(ctx, timeout, &CommandTimeoutError{Command: name, Timeout: timeout})
+}
+
+// CommandError returns err, the error of a command run with cmdCtx, joined
+// with the *CommandTimeoutError that ended cmdCtx, if any, so the registry can
+// report the project as timed out rather than as a plain failure.
+func CommandError(cmdCtx context.Context, err error) error {
+	if err == nil {
+		return nil
+	}
+	var timeout *CommandTimeoutError
+	if cmdCtx.Err() != nil && errors.As(context.Cause(cmdCtx), &timeout) && !errors.As(err, new(*CommandTimeoutError)) {
+		return fmt.Errorf("%w: %w", timeout, err)
+	}
+	return err
+}
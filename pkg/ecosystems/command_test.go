@@ -0,0 +1,50 @@
+package ecosystems
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCommandContext_NoTimeout(t *testing.T) {
+	cmdCtx, cancel := CommandContext(context.Background(), "gradle")
+	defer cancel()
+
+	_, hasDeadline := cmdCtx.Deadline()
+	assert.False(t, hasDeadline)
+	assert.Equal(t, errFailed, CommandError(cmdCtx, errFailed))
+}
+
+func TestCommandContext_Timeout(t *testing.T) {
+	ctx := ContextWithCommandTimeout(context.Background(), 10*time.Millisecond)
+	cmdCtx, cancel := CommandContext(ctx, "gradle")
+	defer cancel()
+
+	<-cmdCtx.Done()
+	err := CommandError(cmdCtx, errFailed)
+
+	require.ErrorIs(t, err, errFailed)
+	var timeout *CommandTimeoutError
+	require.ErrorAs(t, err, &timeout)
+	assert.Equal(t, "gradle", timeout.Command)
+	assert.Equal(t, 10*time.Millisecond, timeout.Timeout)
+	assert.Equal(t, "gradle did not finish within 10ms: command failed", err.Error())
+	assert.Equal(t, err, CommandError(cmdCtx, err), "an error is only joined with the timeout once")
+}
+
+func TestCommandError_IgnoresOtherCancellation(t *testing.T) {
+	ctx, cancelRun := context.WithCancel(ContextWithCommandTimeout(context.Background(), time.Hour))
+	cmdCtx, cancel := CommandContext(ctx, "gradle")
+	defer cancel()
+
+	cancelRun()
+
+	assert.Equal(t, errFailed, CommandError(cmdCtx, errFailed))
+	assert.NoError(t, CommandError(cmdCtx, nil))
+}
+
+var errFailed = errors.New("command failed")
@@ -6,7 +6,9 @@ import (
 	"io/fs"
 	"log/slog"
 	"os"
+	"path"
 	"path/filepath"
+	"strings"
 )
 
 const (
@@ -22,6 +24,14 @@ type findOptions struct {
 	targetFiles  []string
 	includeGlobs []string
 	excludeGlobs []string
+	gitignore    bool
+	maxDepth     int
+	index        *Index
+
+	// includes and excludes are compiled from includeGlobs and excludeGlobs
+	// once the options are validated.
+	includes *patternSet
+	excludes *patternSet
 }
 
 // FindOption is a functional option for configuring file discovery.
@@ -42,6 +52,9 @@ func WithTargetFiles(files ...string) FindOption {
 }
 
 // WithInclude adds a glob pattern for files to include (e.g., "requirements*.txt").
+// Patterns without a slash match file names at any depth; patterns with one,
+// such as "backend/**/requirements/*.txt", match paths from the root. A
+// pattern prefixed with "!" leaves out files an earlier pattern included.
 func WithInclude(pattern string) FindOption {
 	return func(o *findOptions) {
 		o.includeGlobs = append(o.includeGlobs, pattern)
@@ -56,6 +69,10 @@ func WithIncludes(patterns ...string) FindOption {
 }
 
 // WithExclude adds a glob pattern for files/directories to exclude (e.g., "node_modules").
+// Patterns follow the rules of WithInclude, so "services/**/fixtures"
+// excludes those directories only, and "!services/api/fixtures" brings one
+// back. The last matching exclude pattern wins, and the contents of excluded
+// directories are never walked, so they cannot be brought back individually.
 func WithExclude(pattern string) FindOption {
 	return func(o *findOptions) {
 		o.excludeGlobs = append(o.excludeGlobs, pattern)
@@ -69,14 +86,47 @@ func WithExcludes(patterns ...string) FindOption {
 	}
 }
 
+// WithExcludePaths adds paths or path patterns to exclude, in the operating
+// system's path syntax. They follow the rules of WithExclude, so an entry
+// without a slash, such as "fixtures", matches that name at any depth, and a
+// leading slash, as in "/bun.lock", anchors it to the root. The ordering of
+// patterns is shared with WithExclude.
+func WithExcludePaths(paths ...string) FindOption {
+	return func(o *findOptions) {
+		for _, p := range paths {
+			o.excludeGlobs = append(o.excludeGlobs, filepath.ToSlash(p))
+		}
+	}
+}
+
 // WithCommonExcludes adds common exclude patterns for files/directories.
 func WithCommonExcludes() FindOption {
 	return WithExcludes(commonExcludes...)
 }
 
+// WithGitignore skips the files and directories git ignores while walking:
+// those matched by .git/info/exclude and by the .gitignore files of the
+// repository, from its root down to each walked directory. The .git
+// directory itself is skipped too. Target files are found even if ignored.
+func WithGitignore() FindOption {
+	return func(o *findOptions) {
+		o.gitignore = true
+	}
+}
+
+// WithMaxDepth limits the walk to depth directory levels, counting the root
+// directory as the first: 1 finds files in the root only, 2 also in its
+// immediate subdirectories, and so on, as the CLI's --detection-depth does.
+// Zero or less means no limit. Target files are found at any depth.
+func WithMaxDepth(depth int) FindOption {
+	return func(o *findOptions) {
+		o.maxDepth = depth
+	}
+}
+
 // FindResult represents a discovered file.
 type FindResult struct {
-	Path    string // Absolute path to the file
+	Path    string // Absolute path to the file, or its name in the fs.FS searched by FindFilesFS
 	RelPath string // Path relative to the root directory
 }
 
@@ -89,7 +139,40 @@ type FindResult struct {
 //
 // The search can be canceled via the context.
 func FindFiles(ctx context.Context, rootDir string, options ...FindOption) ([]FindResult, error) {
-	// Apply options
+	opts, err := newFindOptions(rootDir, options)
+	if err != nil {
+		return nil, err
+	}
+
+	absRoot, err := filepath.Abs(rootDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve absolute path for %s: %w", rootDir, err)
+	}
+
+	return find(ctx, newDiskTree(absRoot, opts.index), opts)
+}
+
+// FindFilesFS discovers files in fsys below root, a slash-separated path in
+// fsys such as "." for all of it, as FindFiles does on disk. This lets an
+// archive, a git tree or an in-memory fstest.MapFS be searched directly.
+//
+// The Path of each result is the file's name in fsys. Ignore files are read
+// from fsys too, from the closest directory at or above root holding a .git
+// entry. WithIndex has no effect.
+func FindFilesFS(ctx context.Context, fsys fs.FS, root string, options ...FindOption) ([]FindResult, error) {
+	opts, err := newFindOptions(root, options)
+	if err != nil {
+		return nil, err
+	}
+	if !fs.ValidPath(root) {
+		return nil, fmt.Errorf("invalid root %s: %w", root, fs.ErrInvalid)
+	}
+
+	return find(ctx, &searchTree{fsys: fsys, root: root, toPath: func(name string) string { return name }}, opts)
+}
+
+// newFindOptions applies and validates options, compiling their patterns.
+func newFindOptions(rootDir string, options []FindOption) (*findOptions, error) {
 	opts := &findOptions{
 		targetFiles:  []string{},
 		includeGlobs: []string{},
@@ -102,24 +185,37 @@ func FindFiles(ctx context.Context, rootDir string, options ...FindOption) ([]Fi
 	if err := validateInputs(rootDir, opts); err != nil {
 		return nil, err
 	}
-
-	absRoot, err := filepath.Abs(rootDir)
-	if err != nil {
-		return nil, fmt.Errorf("failed to resolve absolute path for %s: %w", rootDir, err)
+	var err error
+	if opts.includes, err = compilePatterns("include", opts.includeGlobs); err != nil {
+		return nil, err
 	}
+	// Exclude patterns come from users, through --exclude and --exclude-paths,
+	// so a malformed one is skipped rather than failing the whole discovery.
+	if opts.excludes, err = compilePatterns("exclude", opts.excludeGlobs); err != nil {
+		slog.Warn("Skipping invalid exclude patterns", slog.Any(logKeyError, err))
+	}
+	return opts, nil
+}
 
+// find returns the target files and the files matching the include patterns
+// in tree.
+func find(ctx context.Context, tree *searchTree, opts *findOptions) ([]FindResult, error) {
+	rootDir := tree.toPath(tree.root)
 	slog.Debug("Starting file discovery",
-		slog.String("root_dir", absRoot),
+		slog.String("root_dir", rootDir),
 		slog.Any("target_files", opts.targetFiles),
 		slog.Any("include_globs", opts.includeGlobs),
-		slog.Any("exclude_globs", opts.excludeGlobs))
+		slog.Any("exclude_globs", opts.excludeGlobs),
+		slog.Bool("gitignore", opts.gitignore),
+		slog.Int("max_depth", opts.maxDepth),
+		slog.Bool("indexed", opts.index != nil))
 
-	// Use a map to deduplicate results by absolute path
+	// Use a map to deduplicate results by path
 	resultMap := make(map[string]FindResult)
 
 	// Find all target files
 	for _, targetFile := range opts.targetFiles {
-		result, err := findTargetFile(absRoot, targetFile, opts.excludeGlobs)
+		result, err := findTargetFile(tree, targetFile, opts.excludes)
 		if err != nil {
 			return nil, err
 		}
@@ -131,7 +227,7 @@ func FindFiles(ctx context.Context, rootDir string, options ...FindOption) ([]Fi
 
 	// Walk directory for pattern matching if any globs specified
 	if len(opts.includeGlobs) > 0 {
-		globResults, err := walkDirectory(ctx, absRoot, opts)
+		globResults, err := walkDirectory(ctx, tree, opts)
 		if err != nil {
 			return nil, err
 		}
@@ -147,7 +243,7 @@ func FindFiles(ctx context.Context, rootDir string, options ...FindOption) ([]Fi
 	}
 
 	slog.Info("File discovery completed",
-		slog.String("root_dir", absRoot),
+		slog.String("root_dir", rootDir),
 		slog.Int("files_found", len(results)))
 
 	return results, nil
@@ -165,27 +261,18 @@ func validateInputs(rootDir string, opts *findOptions) error {
 		return fmt.Errorf("at least one target file or include pattern must be specified")
 	}
 
-	// Validate include patterns
-	for _, pattern := range opts.includeGlobs {
-		if _, err := filepath.Match(pattern, "test"); err != nil {
-			return fmt.Errorf("invalid include pattern %s: %w", pattern, err)
-		}
-	}
-
-	// Validate exclude patterns
-	for _, pattern := range opts.excludeGlobs {
-		if _, err := filepath.Match(pattern, "test"); err != nil {
-			return fmt.Errorf("invalid exclude pattern %s: %w", pattern, err)
-		}
-	}
-
 	return nil
 }
 
 // findTargetFile attempts to find a specific file by path.
 // Returns an error if the file is not found or is a directory.
 // Returns nil error with empty result if the file is excluded.
-func findTargetFile(absRoot, targetFile string, excludePatterns []string) (FindResult, error) {
+func findTargetFile(tree *searchTree, targetFile string, excludes *patternSet) (FindResult, error) {
+	if tree.absRoot == "" {
+		return findTargetFileFS(tree, targetFile, excludes)
+	}
+	absRoot := tree.absRoot
+
 	targetPath := targetFile
 	if !filepath.IsAbs(targetPath) {
 		targetPath = filepath.Join(absRoot, targetPath)
@@ -209,7 +296,7 @@ func findTargetFile(absRoot, targetFile string, excludePatterns []string) (FindR
 	}
 
 	// Check if excluded - return empty result but no error
-	if isExcluded(filepath.Base(relPath), relPath, excludePatterns) {
+	if isExcluded(filepath.Base(relPath), relPath, excludes) {
 		slog.Debug("Target file excluded by pattern", slog.String(logKeyFile, targetFile))
 		return FindResult{}, nil
 	}
@@ -221,12 +308,46 @@ func findTargetFile(absRoot, targetFile string, excludePatterns []string) (FindR
 	}, nil
 }
 
+// findTargetFileFS is findTargetFile for a tree in an fs.FS, where target
+// files are always relative to the root.
+func findTargetFileFS(tree *searchTree, targetFile string, excludes *patternSet) (FindResult, error) {
+	name := path.Join(tree.root, filepath.ToSlash(targetFile))
+
+	info, err := fs.Stat(tree.fsys, name)
+	if err != nil {
+		return FindResult{}, fmt.Errorf("target file %s not found: %w", targetFile, err)
+	}
+	if info.IsDir() {
+		return FindResult{}, fmt.Errorf("target file %s is a directory", targetFile)
+	}
+
+	relPath := filepath.FromSlash(relName(tree.root, name))
+	if isExcluded(path.Base(name), relPath, excludes) {
+		slog.Debug("Target file excluded by pattern", slog.String(logKeyFile, targetFile))
+		return FindResult{}, nil
+	}
+
+	slog.Debug("Found target file", slog.String(logKeyFile, name))
+	return FindResult{
+		Path:    name,
+		RelPath: relPath,
+	}, nil
+}
+
 // walkDirectory traverses the directory tree and finds files matching the include pattern.
-func walkDirectory(ctx context.Context, absRoot string, opts *findOptions) ([]FindResult, error) {
+func walkDirectory(ctx context.Context, tree *searchTree, opts *findOptions) ([]FindResult, error) {
 	// Pre-allocate with reasonable capacity to reduce allocations
 	results := make([]FindResult, 0, 16)
 
-	err := filepath.WalkDir(absRoot, func(path string, d fs.DirEntry, err error) error {
+	var ignore *gitignore
+	if opts.gitignore {
+		var err error
+		if ignore, err = tree.gitignore(); err != nil {
+			return nil, err
+		}
+	}
+
+	err := fs.WalkDir(tree.fsys, tree.root, func(name string, d fs.DirEntry, err error) error {
 		// Check for cancellation
 		select {
 		case <-ctx.Done():
@@ -234,20 +355,29 @@ func walkDirectory(ctx context.Context, absRoot string, opts *findOptions) ([]Fi
 		default:
 		}
 
+		path := tree.toPath(name)
 		if err != nil {
 			slog.Warn("Error accessing path", slog.String(logKeyPath, path), slog.Any(logKeyError, err))
 			return nil // Continue walking despite errors
 		}
 
-		relPath, err := filepath.Rel(absRoot, path)
-		if err != nil {
-			slog.Warn("Failed to compute relative path", slog.String(logKeyPath, path), slog.Any(logKeyError, err))
-			return nil
-		}
+		relPath := filepath.FromSlash(relName(tree.root, name))
 
 		// Handle directories
 		if d.IsDir() {
-			return handleDirectory(d, relPath, opts.excludeGlobs)
+			if exceedsMaxDepth(relPath, opts.maxDepth) {
+				slog.Debug("Skipping directory beyond max depth", slog.String(logKeyDir, relPath))
+				return fs.SkipDir
+			}
+			if err := handleDirectory(d, relPath, opts.excludes); err != nil || ignore == nil {
+				return err
+			}
+			return handleIgnoredDirectory(ignore, relPath, d.Name())
+		}
+
+		if ignore != nil && ignore.ignored(relPath, false) {
+			slog.Debug("Skipping ignored file", slog.String(logKeyFile, relPath))
+			return nil
 		}
 
 		// Check exclusions and pattern match for files
@@ -262,105 +392,72 @@ func walkDirectory(ctx context.Context, absRoot string, opts *findOptions) ([]Fi
 		return nil
 	})
 	if err != nil {
-		return nil, fmt.Errorf("error walking directory %s: %w", absRoot, err)
+		return nil, fmt.Errorf("error walking directory %s: %w", tree.toPath(tree.root), err)
 	}
 
 	return results, nil
 }
 
-// handleDirectory checks if a directory should be excluded and returns fs.SkipDir if so.
-func handleDirectory(d fs.DirEntry, relPath string, excludePatterns []string) error {
-	if len(excludePatterns) == 0 {
-		return nil
+// exceedsMaxDepth reports whether the directory relPath lies below the
+// levels maxDepth allows. See WithMaxDepth.
+func exceedsMaxDepth(relPath string, maxDepth int) bool {
+	if maxDepth <= 0 || relPath == "." {
+		return false
 	}
+	return strings.Count(filepath.ToSlash(relPath), "/")+1 >= maxDepth
+}
 
+// handleDirectory checks if a directory should be excluded and returns fs.SkipDir if so.
+func handleDirectory(d fs.DirEntry, relPath string, excludes *patternSet) error {
 	// Never exclude the root directory
 	if relPath == "." {
 		return nil
 	}
 
-	name := d.Name()
-	for _, pattern := range excludePatterns {
-		// Check relative path first (more specific)
-		matched, err := filepath.Match(pattern, relPath)
-		if err != nil {
-			slog.Warn("Invalid exclude pattern for directory", slog.String(logKeyPattern, pattern), slog.Any(logKeyError, err))
-			continue
-		}
-		if matched {
-			slog.Debug("Excluding directory by path", slog.String(logKeyDir, relPath), slog.String(logKeyPattern, pattern))
-			return fs.SkipDir
-		}
-
-		// Check directory name (matches anywhere in tree)
-		matched, err = filepath.Match(pattern, name)
-		if err != nil {
-			slog.Warn("Invalid exclude pattern for directory", slog.String(logKeyPattern, pattern), slog.Any(logKeyError, err))
-			continue
-		}
-		if matched {
-			slog.Debug("Excluding directory by name", slog.String(logKeyDir, name), slog.String(logKeyPattern, pattern))
-			return fs.SkipDir
-		}
+	if isExcluded(d.Name(), relPath, excludes) {
+		slog.Debug("Excluding directory", slog.String(logKeyDir, relPath))
+		return fs.SkipDir
 	}
 
 	return nil
 }
 
+// handleIgnoredDirectory returns fs.SkipDir for the .git directory and for
+// directories git ignores, and otherwise loads the directory's ignore file.
+// An unreadable ignore file is logged and walked past.
+func handleIgnoredDirectory(ignore *gitignore, relPath, name string) error {
+	if relPath == "." {
+		return nil
+	}
+	if name == gitDir || ignore.ignored(relPath, true) {
+		slog.Debug("Skipping ignored directory", slog.String(logKeyDir, relPath))
+		return fs.SkipDir
+	}
+	if err := ignore.enterDir(relPath); err != nil {
+		slog.Warn("Failed to load ignore file", slog.String(logKeyDir, relPath), slog.Any(logKeyError, err))
+	}
+	return nil
+}
+
 // shouldIncludeFile determines if a file should be included in results.
-// Returns true if the file matches any of the include globs and is not excluded.
+// Returns true if the file matches the include patterns and is not excluded.
 func shouldIncludeFile(d fs.DirEntry, relPath string, opts *findOptions) bool {
 	name := d.Name()
 
 	// Check exclusions first (most likely to filter out files)
-	if isExcluded(name, relPath, opts.excludeGlobs) {
+	if isExcluded(name, relPath, opts.excludes) {
 		slog.Debug("Excluding file", slog.String(logKeyFile, relPath))
 		return false
 	}
 
-	// Match against any include pattern (already validated in validateInputs)
-	for _, pattern := range opts.includeGlobs {
-		matched, err := filepath.Match(pattern, name)
-		if err != nil {
-			slog.Warn("Invalid include pattern for file", slog.String(logKeyPattern, pattern), slog.Any(logKeyError, err))
-			continue
-		}
-		if matched {
-			return true
-		}
-	}
-
-	return false
+	return opts.includes.match(filepath.ToSlash(relPath), name)
 }
 
-// isExcluded checks if a file/directory matches any of the exclude patterns.
-// Checks both the name (for matching anywhere in tree) and relPath (for specific paths).
-func isExcluded(name, relPath string, excludePatterns []string) bool {
-	if len(excludePatterns) == 0 {
+// isExcluded checks if a file/directory matches the exclude patterns.
+// Name patterns are checked against name, path patterns against relPath.
+func isExcluded(name, relPath string, excludes *patternSet) bool {
+	if excludes.empty() {
 		return false
 	}
-
-	for _, pattern := range excludePatterns {
-		// Check by name
-		matched, err := filepath.Match(pattern, name)
-		if err != nil {
-			slog.Warn("Invalid exclude pattern", slog.String(logKeyPattern, pattern), slog.Any(logKeyError, err))
-			continue
-		}
-		if matched {
-			return true
-		}
-
-		// Check by relative path
-		matched, err = filepath.Match(pattern, relPath)
-		if err != nil {
-			slog.Warn("Invalid exclude pattern", slog.String(logKeyPattern, pattern), slog.Any(logKeyError, err))
-			continue
-		}
-		if matched {
-			return true
-		}
-	}
-
-	return false
+	return excludes.match(filepath.ToSlash(relPath), name)
 }
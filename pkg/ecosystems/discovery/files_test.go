@@ -5,6 +5,7 @@ package discovery
 
 import (
 	"context"
+	"fmt"
 	"os"
 	"path/filepath"
 	"testing"
@@ -313,3 +314,37 @@ func TestFindFiles_EdgeCases(t *testing.T) {
 		assert.True(t, filepath.IsAbs(results[0].Path), "path should be absolute")
 	})
 }
+
+func TestFindFiles_MaxDepth(t *testing.T) {
+	tmpDir := t.TempDir()
+	setupFiles(t, tmpDir, map[string]string{
+		"package.json":         "",
+		"a/package.json":       "",
+		"a/b/package.json":     "",
+		"a/b/c/package.json":   "",
+		"d/e/f/g/package.json": "",
+	})
+
+	tests := []struct {
+		depth int
+		want  []string
+	}{
+		{depth: 0, want: []string{"a/b/c/package.json", "a/b/package.json", "a/package.json", "d/e/f/g/package.json", "package.json"}},
+		{depth: 1, want: []string{"package.json"}},
+		{depth: 2, want: []string{"a/package.json", "package.json"}},
+		{depth: 4, want: []string{"a/b/c/package.json", "a/b/package.json", "a/package.json", "package.json"}},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("depth %d", tt.depth), func(t *testing.T) {
+			results, err := FindFiles(context.Background(), tmpDir, WithInclude("package.json"), WithMaxDepth(tt.depth))
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, relPaths(results))
+		})
+	}
+
+	t.Run("target files ignore the limit", func(t *testing.T) {
+		results, err := FindFiles(context.Background(), tmpDir, WithTargetFile("a/b/c/package.json"), WithMaxDepth(1))
+		require.NoError(t, err)
+		assert.Equal(t, []string{"a/b/c/package.json"}, relPaths(results))
+	})
+}
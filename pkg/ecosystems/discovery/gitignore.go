@@ -0,0 +1,239 @@
+package discovery
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	gitDir        = ".git"
+	gitignoreFile = ".gitignore"
+)
+
+// gitignoreRule is one pattern line of an ignore file.
+type gitignoreRule struct {
+	// base is the slash-separated directory of the ignore file, relative to
+	// the repository root, or "" for the root itself.
+	base     string
+	segments []string
+	negate   bool
+	dirOnly  bool
+	// anchored rules match the path relative to base; the others match the
+	// last path element at any depth below it.
+	anchored bool
+}
+
+// gitignore matches paths against the ignore files of a git repository:
+// .git/info/exclude, then every .gitignore from the repository root down.
+// As in git, the last matching rule wins, and rules of deeper ignore files
+// come after those of their ancestors.
+type gitignore struct {
+	fsys fs.FS
+	// repo is the name of the repository root in fsys.
+	repo string
+	// prefix is the slash-separated path of the walked root relative to the
+	// repository root, or "" when they are the same.
+	prefix string
+	rules  []gitignoreRule
+}
+
+// newGitignore loads the ignore files in fsys that apply to root: those of the
+// git repository containing it, from the repository root down to root. If
+// root is not in a repository it is treated as the root of one.
+func newGitignore(fsys fs.FS, root string) (*gitignore, error) {
+	repo := findRepoRootFS(fsys, root)
+	g := &gitignore{fsys: fsys, repo: repo}
+	if prefix := relName(repo, root); prefix != "." {
+		g.prefix = prefix
+	}
+
+	if err := g.load(path.Join(repo, gitDir, "info", "exclude"), ""); err != nil {
+		return nil, err
+	}
+	dir := ""
+	if err := g.load(path.Join(repo, gitignoreFile), dir); err != nil {
+		return nil, err
+	}
+	if g.prefix != "" {
+		for _, name := range strings.Split(g.prefix, "/") {
+			dir = path.Join(dir, name)
+			if err := g.load(path.Join(repo, dir, gitignoreFile), dir); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return g, nil
+}
+
+// findRepoRoot returns the closest directory at or above dir containing a
+// .git entry, or dir if there is none.
+func findRepoRoot(dir string) string {
+	for current := dir; ; {
+		if _, err := os.Lstat(filepath.Join(current, gitDir)); err == nil {
+			return current
+		}
+		parent := filepath.Dir(current)
+		if parent == current {
+			return dir
+		}
+		current = parent
+	}
+}
+
+// findRepoRootFS is findRepoRoot for the slash-separated name dir in fsys.
+func findRepoRootFS(fsys fs.FS, dir string) string {
+	for current := dir; ; current = path.Dir(current) {
+		if _, err := fs.Stat(fsys, path.Join(current, gitDir)); err == nil {
+			return current
+		}
+		if current == "." {
+			return dir
+		}
+	}
+}
+
+// enterDir loads the .gitignore of the walked directory relPath, if any.
+// The walked root's own ignore file is loaded by newGitignore.
+func (g *gitignore) enterDir(relPath string) error {
+	if relPath == "." {
+		return nil
+	}
+	dir := g.repoPath(relPath)
+	return g.load(path.Join(g.repo, dir, gitignoreFile), dir)
+}
+
+// load appends the rules of the ignore file named file in the repository's
+// fs.FS, whose directory is base. A missing file has no rules.
+func (g *gitignore) load(file, base string) error {
+	f, err := g.fsys.Open(file)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to open ignore file %s: %w", file, err)
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if rule, ok := parseGitignoreLine(scanner.Text(), base); ok {
+			g.rules = append(g.rules, rule)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read ignore file %s: %w", file, err)
+	}
+	slog.Debug("Loaded ignore file", slog.String(logKeyFile, file))
+	return nil
+}
+
+// parseGitignoreLine parses one line of an ignore file, reporting false for
+// blank lines and comments.
+func parseGitignoreLine(line, base string) (gitignoreRule, bool) {
+	line = trimTrailingSpaces(line)
+	if line == "" || strings.HasPrefix(line, "#") {
+		return gitignoreRule{}, false
+	}
+
+	rule := gitignoreRule{base: base}
+	switch {
+	case strings.HasPrefix(line, "!"):
+		rule.negate = true
+		line = line[1:]
+	case strings.HasPrefix(line, `\!`), strings.HasPrefix(line, `\#`):
+		line = line[1:]
+	}
+	if strings.HasSuffix(line, "/") {
+		rule.dirOnly = true
+		line = strings.TrimRight(line, "/")
+	}
+	// A slash anywhere but at the end anchors the pattern to base.
+	if strings.Contains(line, "/") {
+		rule.anchored = true
+		line = strings.TrimPrefix(line, "/")
+	}
+	if line == "" {
+		return gitignoreRule{}, false
+	}
+	rule.segments = strings.Split(line, "/")
+	return rule, true
+}
+
+// trimTrailingSpaces removes the trailing spaces of line that are not
+// escaped with a backslash.
+func trimTrailingSpaces(line string) string {
+	for strings.HasSuffix(line, " ") && !strings.HasSuffix(line, `\ `) {
+		line = line[:len(line)-1]
+	}
+	if strings.HasSuffix(line, `\ `) {
+		line = line[:len(line)-2] + " "
+	}
+	return line
+}
+
+// ignored reports whether relPath, relative to the walked root, is ignored.
+func (g *gitignore) ignored(relPath string, isDir bool) bool {
+	repoPath := g.repoPath(relPath)
+	ignored := false
+	for i := range g.rules {
+		rule := &g.rules[i]
+		if rule.matches(repoPath, isDir) {
+			ignored = !rule.negate
+		}
+	}
+	return ignored
+}
+
+func (g *gitignore) repoPath(relPath string) string {
+	return path.Join(g.prefix, filepath.ToSlash(relPath))
+}
+
+func (r *gitignoreRule) matches(repoPath string, isDir bool) bool {
+	if r.dirOnly && !isDir {
+		return false
+	}
+	rel := repoPath
+	if r.base != "" {
+		if !strings.HasPrefix(repoPath, r.base+"/") {
+			return false
+		}
+		rel = repoPath[len(r.base)+1:]
+	}
+	if !r.anchored {
+		matched, err := path.Match(r.segments[0], path.Base(rel))
+		return err == nil && matched
+	}
+	return matchSegments(r.segments, strings.Split(rel, "/"))
+}
+
+// matchSegments matches path elements against pattern elements, where "**"
+// stands for any number of elements, and at the end of a pattern for at
+// least one, so "dir/**" matches what is inside dir but not dir itself.
+func matchSegments(pattern, elems []string) bool {
+	if len(pattern) == 0 {
+		return len(elems) == 0
+	}
+	if pattern[0] == "**" {
+		if len(pattern) == 1 {
+			return len(elems) > 0
+		}
+		for i := 0; i <= len(elems); i++ {
+			if matchSegments(pattern[1:], elems[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+	if len(elems) == 0 {
+		return false
+	}
+	matched, err := path.Match(pattern[0], elems[0])
+	return err == nil && matched && matchSegments(pattern[1:], elems[1:])
+}
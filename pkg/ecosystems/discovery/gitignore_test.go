@@ -0,0 +1,154 @@
+//go:build !integration
+// +build !integration
+
+package discovery
+
+import (
+	"context"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func relPaths(results []FindResult) []string {
+	paths := make([]string, 0, len(results))
+	for _, result := range results {
+		paths = append(paths, filepath.ToSlash(result.RelPath))
+	}
+	sort.Strings(paths)
+	return paths
+}
+
+func TestFindFiles_Gitignore(t *testing.T) {
+	tmpDir := t.TempDir()
+	setupFiles(t, tmpDir, map[string]string{
+		".git/info/exclude":                 "local/\n",
+		".git/package-lock.json":            "",
+		".gitignore":                        "# build output\ndist/\n/vendor\n*.generated.json\nfixtures/*\n!fixtures/keep/\n",
+		"package-lock.json":                 "",
+		"dist/package-lock.json":            "",
+		"vendor/package-lock.json":          "",
+		"app/vendor/package-lock.json":      "",
+		"local/package-lock.json":           "",
+		"fixtures/a/package-lock.json":      "",
+		"fixtures/keep/package-lock.json":   "",
+		"lock.generated.json":               "",
+		"app/package-lock.json":             "",
+		"app/.gitignore":                    "generated/\n!dist/\n",
+		"app/generated/package-lock.json":   "",
+		"app/dist/package-lock.json":        "",
+		"other/generated/package-lock.json": "",
+	})
+
+	results, err := FindFiles(context.Background(), tmpDir, WithIncludes("package-lock.json", "*.generated.json"), WithGitignore())
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{
+		"app/dist/package-lock.json", // re-included by app/.gitignore
+		"app/package-lock.json",
+		"app/vendor/package-lock.json",    // /vendor is anchored to the root
+		"fixtures/keep/package-lock.json", // re-included after fixtures/*
+		"other/generated/package-lock.json",
+		"package-lock.json",
+	}, relPaths(results))
+}
+
+func TestFindFiles_GitignoreDisabledByDefault(t *testing.T) {
+	tmpDir := t.TempDir()
+	setupFiles(t, tmpDir, map[string]string{
+		".gitignore":             "dist/\n",
+		"dist/package-lock.json": "",
+	})
+
+	results, err := FindFiles(context.Background(), tmpDir, WithInclude("package-lock.json"))
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"dist/package-lock.json"}, relPaths(results))
+}
+
+func TestFindFiles_GitignoreAppliesRulesAboveRoot(t *testing.T) {
+	repo := t.TempDir()
+	setupFiles(t, repo, map[string]string{
+		".git/HEAD":                            "",
+		".gitignore":                           "/services/api/build/\n",
+		"services/.gitignore":                  "*.tmp\n",
+		"services/api/package-lock.json":       "",
+		"services/api/build/package-lock.json": "",
+		"services/api/cache.tmp":               "",
+	})
+
+	results, err := FindFiles(context.Background(), filepath.Join(repo, "services", "api"),
+		WithIncludes("package-lock.json", "*.tmp"), WithGitignore())
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"package-lock.json"}, relPaths(results))
+}
+
+func TestFindFiles_GitignoreKeepsTargetFiles(t *testing.T) {
+	tmpDir := t.TempDir()
+	setupFiles(t, tmpDir, map[string]string{
+		".gitignore":             "dist/\n",
+		"dist/package-lock.json": "",
+	})
+
+	results, err := FindFiles(context.Background(), tmpDir, WithTargetFile("dist/package-lock.json"), WithGitignore())
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"dist/package-lock.json"}, relPaths(results))
+}
+
+func TestParseGitignoreLine(t *testing.T) {
+	tests := []struct {
+		line   string
+		want   gitignoreRule
+		wantOK bool
+	}{
+		{line: "", wantOK: false},
+		{line: "# comment", wantOK: false},
+		{line: "/", wantOK: false},
+		{line: "node_modules", want: gitignoreRule{segments: []string{"node_modules"}}, wantOK: true},
+		{line: "build/", want: gitignoreRule{segments: []string{"build"}, dirOnly: true}, wantOK: true},
+		{line: "/target", want: gitignoreRule{segments: []string{"target"}, anchored: true}, wantOK: true},
+		{line: "docs/*.md", want: gitignoreRule{segments: []string{"docs", "*.md"}, anchored: true}, wantOK: true},
+		{line: "**/out/", want: gitignoreRule{segments: []string{"**", "out"}, anchored: true, dirOnly: true}, wantOK: true},
+		{line: "!keep.lock", want: gitignoreRule{segments: []string{"keep.lock"}, negate: true}, wantOK: true},
+		{line: `\!bang`, want: gitignoreRule{segments: []string{"!bang"}}, wantOK: true},
+		{line: `\#hash`, want: gitignoreRule{segments: []string{"#hash"}}, wantOK: true},
+		{line: "trailing   ", want: gitignoreRule{segments: []string{"trailing"}}, wantOK: true},
+		{line: `space\ `, want: gitignoreRule{segments: []string{"space "}}, wantOK: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.line, func(t *testing.T) {
+			rule, ok := parseGitignoreLine(tt.line, "")
+			assert.Equal(t, tt.wantOK, ok)
+			assert.Equal(t, tt.want, rule)
+		})
+	}
+}
+
+func TestMatchSegments(t *testing.T) {
+	tests := []struct {
+		pattern string
+		path    string
+		want    bool
+	}{
+		{"a/b", "a/b", true},
+		{"a/b", "a/b/c", false},
+		{"a/*", "a/b", true},
+		{"**/b", "b", true},
+		{"**/b", "x/y/b", true},
+		{"a/**/b", "a/b", true},
+		{"a/**/b", "a/x/y/b", true},
+		{"a/**", "a", false},
+		{"a/**", "a/x/y", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.pattern+" "+tt.path, func(t *testing.T) {
+			assert.Equal(t, tt.want, matchSegments(strings.Split(tt.pattern, "/"), strings.Split(tt.path, "/")))
+		})
+	}
+}
@@ -0,0 +1,88 @@
+package discovery
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// Index shares the directory listings of one tree between FindFiles calls, so
+// plugins that each search the same scan directory read every directory from
+// disk only once. Listings are read lazily, the first time a walk enters a
+// directory, which keeps directories that every search excludes unread.
+//
+// Searches through an Index match exactly what they would without one: each
+// still applies its own includes, excludes and other options while walking the
+// cached listings. An Index is safe for concurrent use. It does not notice
+// files created or removed after their directory was listed, so it should live
+// no longer than one scan.
+type Index struct {
+	root string
+	fsys fs.FS
+
+	mu   sync.Mutex
+	dirs map[string]*dirListing
+}
+
+type dirListing struct {
+	once    sync.Once
+	entries []fs.DirEntry
+	err     error
+}
+
+// NewIndex returns an empty Index of the tree at rootDir.
+func NewIndex(rootDir string) (*Index, error) {
+	absRoot, err := filepath.Abs(rootDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve absolute path for %s: %w", rootDir, err)
+	}
+	return &Index{
+		root: absRoot,
+		fsys: os.DirFS(absRoot),
+		dirs: make(map[string]*dirListing),
+	}, nil
+}
+
+// WithIndex makes FindFiles walk the listings cached in idx when searching
+// below its root. A nil idx is ignored.
+func WithIndex(idx *Index) FindOption {
+	return func(o *findOptions) {
+		o.index = idx
+	}
+}
+
+// Open implements fs.FS.
+func (idx *Index) Open(name string) (fs.File, error) {
+	return idx.fsys.Open(name) //nolint:wrapcheck // fs.FS errors are *fs.PathError already
+}
+
+// ReadDir implements fs.ReadDirFS, reading each directory at most once.
+func (idx *Index) ReadDir(name string) ([]fs.DirEntry, error) {
+	idx.mu.Lock()
+	listing, ok := idx.dirs[name]
+	if !ok {
+		listing = &dirListing{}
+		idx.dirs[name] = listing
+	}
+	idx.mu.Unlock()
+
+	listing.once.Do(func() {
+		listing.entries, listing.err = fs.ReadDir(idx.fsys, name)
+	})
+	// Callers may reorder the slice they get, so each gets its own.
+	return slices.Clone(listing.entries), listing.err
+}
+
+// subtree returns the name in the index of the tree at absRoot, and whether
+// the index covers it at all.
+func (idx *Index) subtree(absRoot string) (string, bool) {
+	rel, err := filepath.Rel(idx.root, absRoot)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", false
+	}
+	return filepath.ToSlash(rel), true
+}
@@ -0,0 +1,128 @@
+//go:build !integration
+// +build !integration
+
+package discovery
+
+import (
+	"context"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// countingFS counts the directory reads that reach the underlying tree.
+type countingFS struct {
+	fs.FS
+	reads atomic.Int32
+}
+
+func (c *countingFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	c.reads.Add(1)
+	return fs.ReadDir(c.FS, name)
+}
+
+func newMonorepo(t *testing.T) string {
+	t.Helper()
+	tmpDir := t.TempDir()
+	setupFiles(t, tmpDir, map[string]string{
+		".gitignore":                          "generated/\n",
+		"bun.lock":                            "",
+		"Cargo.lock":                          "",
+		"apps/web/bun.lock":                   "",
+		"apps/web/node_modules/bun.lock":      "",
+		"crates/core/Cargo.lock":              "",
+		"generated/Cargo.lock":                "",
+		"services/api/requirements.txt":       "",
+		"services/api/.venv/requirements.txt": "",
+	})
+	return tmpDir
+}
+
+func TestFindFiles_IndexMatchesWalk(t *testing.T) {
+	root := newMonorepo(t)
+	idx, err := NewIndex(root)
+	require.NoError(t, err)
+
+	queries := map[string][]FindOption{
+		"bun":          {WithInclude("bun.lock"), WithCommonExcludes()},
+		"cargo":        {WithInclude("Cargo.lock"), WithCommonExcludes(), WithGitignore()},
+		"pip":          {WithInclude("requirements.txt"), WithExcludes(".*", "venv")},
+		"shallow":      {WithIncludes("bun.lock", "Cargo.lock"), WithMaxDepth(2)},
+		"target files": {WithTargetFile("apps/web/bun.lock")},
+	}
+	for name, opts := range queries {
+		t.Run(name, func(t *testing.T) {
+			want, err := FindFiles(context.Background(), root, opts...)
+			require.NoError(t, err)
+
+			got, err := FindFiles(context.Background(), root, append(opts, WithIndex(idx))...)
+			require.NoError(t, err)
+
+			assert.ElementsMatch(t, want, got)
+		})
+	}
+}
+
+func TestIndex_ReadsEachDirectoryOnce(t *testing.T) {
+	root := newMonorepo(t)
+	idx, err := NewIndex(root)
+	require.NoError(t, err)
+	counting := &countingFS{FS: os.DirFS(root)}
+	idx.fsys = counting
+
+	var wg sync.WaitGroup
+	for _, include := range []string{"bun.lock", "Cargo.lock", "requirements.txt"} {
+		wg.Go(func() {
+			_, err := FindFiles(context.Background(), root, WithInclude(include), WithIndex(idx))
+			assert.NoError(t, err)
+		})
+	}
+	wg.Wait()
+
+	// ., apps, apps/web, apps/web/node_modules, crates, crates/core,
+	// generated, services, services/api and services/api/.venv.
+	assert.Equal(t, int32(10), counting.reads.Load())
+}
+
+func TestIndex_LeavesExcludedDirectoriesUnread(t *testing.T) {
+	root := newMonorepo(t)
+	idx, err := NewIndex(root)
+	require.NoError(t, err)
+
+	_, err = FindFiles(context.Background(), root, WithInclude("bun.lock"), WithCommonExcludes(), WithIndex(idx))
+	require.NoError(t, err)
+
+	assert.NotContains(t, idx.dirs, "apps/web/node_modules")
+	assert.Contains(t, idx.dirs, "apps/web")
+}
+
+func TestFindFiles_IndexWalksSubdirectories(t *testing.T) {
+	root := newMonorepo(t)
+	idx, err := NewIndex(root)
+	require.NoError(t, err)
+
+	results, err := FindFiles(context.Background(), filepath.Join(root, "apps"), WithInclude("bun.lock"), WithCommonExcludes(), WithIndex(idx))
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"web/bun.lock"}, relPaths(results))
+	assert.Equal(t, filepath.Join(root, "apps", "web", "bun.lock"), results[0].Path)
+}
+
+func TestFindFiles_IndexIgnoresOtherTrees(t *testing.T) {
+	idx, err := NewIndex(newMonorepo(t))
+	require.NoError(t, err)
+	other := t.TempDir()
+	setupFiles(t, other, map[string]string{"pkg/bun.lock": ""})
+
+	results, err := FindFiles(context.Background(), other, WithInclude("bun.lock"), WithIndex(idx))
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"pkg/bun.lock"}, relPaths(results))
+	assert.Empty(t, idx.dirs)
+}
@@ -0,0 +1,125 @@
+package discovery
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+)
+
+// patternSet matches paths against an ordered list of include or exclude
+// patterns. Each pattern is one of:
+//
+//   - a name pattern, without a slash, such as "node_modules" or "*.lock",
+//     which matches the last element of a path at any depth;
+//   - a path pattern, containing a slash, such as "services/**/fixtures" or
+//     "/vendor", which matches the whole path relative to the scan root. "**"
+//     stands for any number of path elements;
+//   - either of the above prefixed with "!", which negates it.
+//
+// As in .gitignore files, the last pattern matching a path decides whether it
+// is matched, so a negated pattern only undoes the patterns before it.
+//
+// Patterns without wildcards are looked up in maps rather than matched one
+// by one, which keeps long lists of exact paths, such as the files other
+// plugins have already processed, cheap to check.
+type patternSet struct {
+	names map[string]literalPattern
+	paths map[string]literalPattern
+	globs []globPattern
+}
+
+type literalPattern struct {
+	index  int
+	negate bool
+}
+
+type globPattern struct {
+	index    int
+	negate   bool
+	anchored bool
+	segments []string
+}
+
+// compilePatterns parses the include or exclude patterns, as kind says, into
+// a patternSet. Patterns with malformed wildcards are left out of the set and
+// reported together in the returned error, so callers may warn about them and
+// go on with the rest.
+func compilePatterns(kind string, patterns []string) (*patternSet, error) {
+	set := &patternSet{names: map[string]literalPattern{}, paths: map[string]literalPattern{}}
+	var invalid []error
+	for i, raw := range patterns {
+		pattern, negate := strings.CutPrefix(raw, "!")
+		// A trailing slash, as in "build/", does not make a path pattern.
+		pattern = strings.TrimRight(pattern, "/")
+		anchored := strings.Contains(pattern, "/")
+		if anchored {
+			pattern = strings.TrimPrefix(path.Clean(pattern), "/")
+		}
+		if pattern == "" || pattern == "." {
+			continue
+		}
+
+		segments := strings.Split(pattern, "/")
+		if err := validateSegments(segments); err != nil {
+			invalid = append(invalid, fmt.Errorf("invalid %s pattern %s: %w", kind, raw, err))
+			continue
+		}
+		if !hasWildcards(pattern) {
+			literals := set.names
+			if anchored {
+				literals = set.paths
+			}
+			literals[pattern] = literalPattern{index: i, negate: negate}
+			continue
+		}
+		set.globs = append(set.globs, globPattern{index: i, negate: negate, anchored: anchored, segments: segments})
+	}
+	return set, errors.Join(invalid...)
+}
+
+func validateSegments(segments []string) error {
+	for _, segment := range segments {
+		if _, err := path.Match(segment, "test"); err != nil {
+			return err //nolint:wrapcheck // wrapped with the pattern by compilePatterns
+		}
+	}
+	return nil
+}
+
+func hasWildcards(pattern string) bool {
+	return strings.ContainsAny(pattern, `*?[\`)
+}
+
+// empty reports whether the set has no patterns, and so matches nothing.
+func (s *patternSet) empty() bool {
+	return len(s.names) == 0 && len(s.paths) == 0 && len(s.globs) == 0
+}
+
+// match reports whether the last pattern matching relPath, a slash-separated
+// path relative to the scan root whose last element is name, is a positive
+// one.
+func (s *patternSet) match(relPath, name string) bool {
+	last := literalPattern{index: -1}
+	if lit, ok := s.paths[relPath]; ok {
+		last = lit
+	}
+	if lit, ok := s.names[name]; ok && lit.index > last.index {
+		last = lit
+	}
+	// Only globs after the last matching literal can change the outcome.
+	for i := len(s.globs) - 1; i >= 0 && s.globs[i].index > last.index; i-- {
+		if s.globs[i].matches(relPath, name) {
+			return !s.globs[i].negate
+		}
+	}
+	return last.index >= 0 && !last.negate
+}
+
+func (g *globPattern) matches(relPath, name string) bool {
+	if !g.anchored {
+		matched, err := path.Match(g.segments[0], name)
+		return err == nil && matched
+	}
+	return matchSegments(g.segments, strings.Split(relPath, "/"))
+}
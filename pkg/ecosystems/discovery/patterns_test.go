@@ -0,0 +1,154 @@
+//go:build !integration
+// +build !integration
+
+package discovery
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPatternSet_Match(t *testing.T) {
+	tests := []struct {
+		name     string
+		patterns []string
+		path     string
+		want     bool
+	}{
+		{"name literal at any depth", []string{"node_modules"}, "a/b/node_modules", true},
+		{"name glob", []string{"*.lock"}, "a/bun.lock", true},
+		{"trailing slash is still a name", []string{"build/"}, "a/build", true},
+		{"path literal", []string{"a/bun.lock"}, "a/bun.lock", true},
+		{"path literal is anchored", []string{"a/bun.lock"}, "x/a/bun.lock", false},
+		{"leading slash anchors a name", []string{"/vendor"}, "a/vendor", false},
+		{"leading slash matches at the root", []string{"/vendor"}, "vendor", true},
+		{"dot slash is ignored", []string{"./a/bun.lock"}, "a/bun.lock", true},
+		{"double star matches nothing", []string{"services/**/fixtures"}, "services/fixtures", true},
+		{"double star matches many", []string{"services/**/fixtures"}, "services/a/b/fixtures", true},
+		{"double star stays anchored", []string{"services/**/fixtures"}, "x/services/a/fixtures", false},
+		{"leading double star", []string{"**/testdata/*.json"}, "a/b/testdata/x.json", true},
+		{"single star is one element", []string{"backend/*/requirements.txt"}, "backend/a/b/requirements.txt", false},
+		{"negation undoes earlier match", []string{"*.lock", "!keep.lock"}, "a/keep.lock", false},
+		{"later match wins over negation", []string{"!keep.lock", "*.lock"}, "a/keep.lock", true},
+		{"negated path literal", []string{"services/**", "!services/api"}, "services/api", false},
+		{"glob after literal wins", []string{"a/bun.lock", "!a/*.lock"}, "a/bun.lock", false},
+		{"literal after glob wins", []string{"!a/*.lock", "a/bun.lock"}, "a/bun.lock", true},
+		{"no match", []string{"*.lock"}, "package.json", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			set, err := compilePatterns("exclude", tt.patterns)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, set.match(tt.path, path.Base(tt.path)))
+		})
+	}
+}
+
+func TestCompilePatterns_SkipsMalformedPatterns(t *testing.T) {
+	set, err := compilePatterns("exclude", []string{"services/[a/x", "*.lock", "[b"})
+	assert.ErrorContains(t, err, "invalid exclude pattern services/[a/x")
+	assert.ErrorContains(t, err, "invalid exclude pattern [b")
+
+	require.NotNil(t, set)
+	assert.True(t, set.match("a/bun.lock", "bun.lock"), "valid patterns are kept")
+	assert.False(t, set.match("services/[a/x", "x"))
+}
+
+func TestFindFiles_PathPatterns(t *testing.T) {
+	tmpDir := t.TempDir()
+	setupFiles(t, tmpDir, map[string]string{
+		"bun.lock":                                 "",
+		"requirements/base.txt":                    "",
+		"backend/requirements/base.txt":            "",
+		"backend/api/requirements/dev.txt":         "",
+		"services/api/bun.lock":                    "",
+		"services/api/fixtures/bun.lock":           "",
+		"services/web/test/fixtures/bun.lock":      "",
+		"services/web/test/fixtures/keep/bun.lock": "",
+		"fixtures/bun.lock":                        "",
+	})
+
+	t.Run("includes anchored double star patterns", func(t *testing.T) {
+		results, err := FindFiles(context.Background(), tmpDir, WithInclude("backend/**/requirements/*.txt"))
+		require.NoError(t, err)
+		assert.Equal(t, []string{"backend/api/requirements/dev.txt", "backend/requirements/base.txt"}, relPaths(results))
+	})
+
+	t.Run("excludes anchored double star patterns", func(t *testing.T) {
+		results, err := FindFiles(context.Background(), tmpDir, WithInclude("bun.lock"), WithExclude("services/**/fixtures"))
+		require.NoError(t, err)
+		assert.Equal(t, []string{"bun.lock", "fixtures/bun.lock", "services/api/bun.lock"}, relPaths(results))
+	})
+
+	t.Run("negated exclude brings a directory back", func(t *testing.T) {
+		results, err := FindFiles(context.Background(), tmpDir, WithInclude("bun.lock"),
+			WithExcludes("fixtures", "!services/web/test/fixtures"))
+		require.NoError(t, err)
+		assert.Equal(t, []string{
+			"bun.lock",
+			"services/api/bun.lock",
+			"services/web/test/fixtures/bun.lock",
+			"services/web/test/fixtures/keep/bun.lock",
+		}, relPaths(results))
+	})
+
+	t.Run("negated include leaves files out", func(t *testing.T) {
+		results, err := FindFiles(context.Background(), tmpDir, WithIncludes("bun.lock", "!**/fixtures/**"))
+		require.NoError(t, err)
+		assert.Equal(t, []string{"bun.lock", "services/api/bun.lock"}, relPaths(results))
+	})
+
+	t.Run("malformed exclude patterns are skipped", func(t *testing.T) {
+		results, err := FindFiles(context.Background(), tmpDir, WithInclude("bun.lock"), WithExcludes("[fixtures", "services"))
+		require.NoError(t, err)
+		assert.Equal(t, []string{"bun.lock", "fixtures/bun.lock"}, relPaths(results))
+	})
+
+	t.Run("exclude paths without a slash match names", func(t *testing.T) {
+		results, err := FindFiles(context.Background(), tmpDir, WithInclude("*.txt"), WithExcludePaths("requirements"))
+		require.NoError(t, err)
+		assert.Empty(t, relPaths(results))
+	})
+
+	t.Run("exclude paths with a slash are anchored", func(t *testing.T) {
+		results, err := FindFiles(context.Background(), tmpDir, WithInclude("bun.lock"), WithExcludePaths("/bun.lock", "services/web"))
+		require.NoError(t, err)
+		assert.Equal(t, []string{"fixtures/bun.lock", "services/api/bun.lock", "services/api/fixtures/bun.lock"}, relPaths(results))
+	})
+
+	t.Run("exclude paths can undo excludes", func(t *testing.T) {
+		results, err := FindFiles(context.Background(), tmpDir, WithInclude("bun.lock"),
+			WithExclude("fixtures"), WithExcludePaths("!/fixtures"))
+		require.NoError(t, err)
+		assert.Equal(t, []string{"bun.lock", "fixtures/bun.lock", "services/api/bun.lock"}, relPaths(results))
+	})
+}
+
+func TestFindFiles_ManyExcludePaths(t *testing.T) {
+	tmpDir := t.TempDir()
+	files := map[string]string{}
+	var excludePaths, want []string
+	for i := range 50 {
+		file := fmt.Sprintf("pkg%02d/bun.lock", i)
+		files[file] = ""
+		if i%2 == 0 {
+			excludePaths = append(excludePaths, file)
+		} else {
+			want = append(want, file)
+		}
+	}
+	for i := range 5000 {
+		excludePaths = append(excludePaths, fmt.Sprintf("processed/%d/bun.lock", i))
+	}
+	setupFiles(t, tmpDir, files)
+
+	results, err := FindFiles(context.Background(), tmpDir, WithInclude("bun.lock"), WithExcludePaths(excludePaths...))
+	require.NoError(t, err)
+
+	assert.Equal(t, want, relPaths(results))
+}
@@ -0,0 +1,64 @@
+package discovery
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// searchTree is the tree a search walks: the directory root, a slash-separated
+// name in fsys, and everything below it.
+type searchTree struct {
+	fsys fs.FS
+	root string
+	// toPath turns a name in fsys into the Path reported for it.
+	toPath func(name string) string
+	// absRoot is the directory searched on disk, or empty for FindFilesFS.
+	absRoot string
+}
+
+// newDiskTree returns the tree of the directory absRoot, read through idx
+// when it covers absRoot.
+func newDiskTree(absRoot string, idx *Index) *searchTree {
+	tree := &searchTree{fsys: os.DirFS(absRoot), root: ".", absRoot: absRoot}
+	base := absRoot
+	if idx != nil {
+		if name, ok := idx.subtree(absRoot); ok {
+			tree.fsys, tree.root, base = idx, name, idx.root
+		}
+	}
+	tree.toPath = func(name string) string {
+		return filepath.Join(base, filepath.FromSlash(name))
+	}
+	return tree
+}
+
+// gitignore returns the ignore rules that apply below the tree's root.
+func (t *searchTree) gitignore() (*gitignore, error) {
+	if t.absRoot == "" {
+		return newGitignore(t.fsys, t.root)
+	}
+	// The repository may start above the directory being searched, outside
+	// the tree itself.
+	repoRoot := findRepoRoot(t.absRoot)
+	rel, err := filepath.Rel(repoRoot, t.absRoot)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve %s within %s: %w", t.absRoot, repoRoot, err)
+	}
+	return newGitignore(os.DirFS(repoRoot), filepath.ToSlash(rel))
+}
+
+// relName returns name, a slash-separated name at or below root, relative to
+// root.
+func relName(root, name string) string {
+	switch {
+	case name == root:
+		return "."
+	case root == ".":
+		return name
+	default:
+		return strings.TrimPrefix(name, root+"/")
+	}
+}
@@ -0,0 +1,124 @@
+//go:build !integration
+// +build !integration
+
+package discovery
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newMapFS(files map[string]string) fstest.MapFS {
+	fsys := fstest.MapFS{}
+	for name, content := range files {
+		fsys[name] = &fstest.MapFile{Data: []byte(content)}
+	}
+	return fsys
+}
+
+func TestFindFilesFS(t *testing.T) {
+	fsys := newMapFS(map[string]string{
+		".git/HEAD":                      "",
+		".gitignore":                     "generated/\n",
+		"bun.lock":                       "",
+		"apps/web/bun.lock":              "",
+		"apps/web/node_modules/bun.lock": "",
+		"apps/web/.gitignore":            "legacy/\n",
+		"apps/web/legacy/bun.lock":       "",
+		"generated/bun.lock":             "",
+		"services/api/fixtures/bun.lock": "",
+	})
+
+	tests := []struct {
+		name    string
+		root    string
+		options []FindOption
+		want    []string
+	}{
+		{
+			name:    "includes",
+			root:    ".",
+			options: []FindOption{WithInclude("bun.lock")},
+			want: []string{
+				"apps/web/bun.lock",
+				"apps/web/legacy/bun.lock",
+				"apps/web/node_modules/bun.lock",
+				"bun.lock",
+				"generated/bun.lock",
+				"services/api/fixtures/bun.lock",
+			},
+		},
+		{
+			name:    "excludes",
+			root:    ".",
+			options: []FindOption{WithInclude("bun.lock"), WithCommonExcludes(), WithExclude("services/**/fixtures")},
+			want:    []string{"apps/web/bun.lock", "apps/web/legacy/bun.lock", "bun.lock", "generated/bun.lock"},
+		},
+		{
+			name:    "gitignore",
+			root:    ".",
+			options: []FindOption{WithInclude("bun.lock"), WithCommonExcludes(), WithGitignore()},
+			want:    []string{"apps/web/bun.lock", "bun.lock", "services/api/fixtures/bun.lock"},
+		},
+		{
+			name:    "gitignore above root",
+			root:    "apps/web",
+			options: []FindOption{WithInclude("bun.lock"), WithCommonExcludes(), WithGitignore()},
+			want:    []string{"bun.lock"},
+		},
+		{
+			name:    "max depth",
+			root:    ".",
+			options: []FindOption{WithInclude("bun.lock"), WithMaxDepth(2)},
+			want:    []string{"bun.lock", "generated/bun.lock"},
+		},
+		{
+			name:    "target file",
+			root:    "apps",
+			options: []FindOption{WithTargetFile("web/legacy/bun.lock"), WithGitignore()},
+			want:    []string{"web/legacy/bun.lock"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			results, err := FindFilesFS(context.Background(), fsys, tt.root, tt.options...)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, relPaths(results))
+		})
+	}
+}
+
+func TestFindFilesFS_ReportsNamesInFS(t *testing.T) {
+	fsys := newMapFS(map[string]string{"apps/web/bun.lock": ""})
+
+	results, err := FindFilesFS(context.Background(), fsys, "apps", WithInclude("bun.lock"))
+	require.NoError(t, err)
+
+	require.Len(t, results, 1)
+	assert.Equal(t, "apps/web/bun.lock", results[0].Path)
+	assert.Equal(t, filepath.Join("web", "bun.lock"), results[0].RelPath)
+}
+
+func TestFindFilesFS_Errors(t *testing.T) {
+	fsys := newMapFS(map[string]string{"apps/web/bun.lock": ""})
+
+	t.Run("invalid root", func(t *testing.T) {
+		_, err := FindFilesFS(context.Background(), fsys, "/apps", WithInclude("bun.lock"))
+		assert.ErrorContains(t, err, "invalid root /apps")
+	})
+
+	t.Run("missing target file", func(t *testing.T) {
+		_, err := FindFilesFS(context.Background(), fsys, ".", WithTargetFile("bun.lock"))
+		assert.ErrorContains(t, err, "target file bun.lock not found")
+	})
+
+	t.Run("target file is a directory", func(t *testing.T) {
+		_, err := FindFilesFS(context.Background(), fsys, ".", WithTargetFile("apps/web"))
+		assert.ErrorContains(t, err, "target file apps/web is a directory")
+	})
+}
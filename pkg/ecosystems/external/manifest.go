@@ -0,0 +1,81 @@
+package external
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// manifestExt is the extension LoadPlugins looks for in the plugins directory.
+const manifestExt = ".json"
+
+// Manifest declares one external plugin. Manifests live as <anything>.json
+// files in the configured plugins directory.
+type Manifest struct {
+	// Name is the plugin name used in run reports, --only-plugins and
+	// --skip-plugins, and by other plugins' dependency declarations.
+	Name string `json:"name"`
+	// Executable is the program to run. A relative path is resolved against
+	// the directory holding the manifest.
+	Executable string `json:"executable"`
+	// Args are passed to Executable before any protocol input.
+	Args []string `json:"args,omitempty"`
+	// Dependencies names the plugins that must finish before this one runs.
+	// The plugin then sees their ProcessedFiles on ExcludePaths.
+	Dependencies []string `json:"dependencies,omitempty"`
+}
+
+var (
+	errManifestNoName       = errors.New("manifest has no name")
+	errManifestNoExecutable = errors.New("manifest has no executable")
+)
+
+// LoadPlugins reads every manifest in dir, in file name order, and returns a
+// Plugin for each. A malformed manifest fails the whole load, since silently
+// dropping a resolver would change which plugin handles a project.
+func LoadPlugins(dir string) ([]*Plugin, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("reading external plugins directory: %w", err)
+	}
+
+	var plugins []*Plugin
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.EqualFold(filepath.Ext(entry.Name()), manifestExt) {
+			continue
+		}
+
+		path := filepath.Join(dir, entry.Name())
+		manifest, err := readManifest(path)
+		if err != nil {
+			return nil, fmt.Errorf("loading external plugin manifest %s: %w", path, err)
+		}
+		if !filepath.IsAbs(manifest.Executable) {
+			manifest.Executable = filepath.Join(dir, manifest.Executable)
+		}
+		plugins = append(plugins, NewPlugin(manifest))
+	}
+	return plugins, nil
+}
+
+func readManifest(path string) (Manifest, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Manifest{}, fmt.Errorf("reading manifest: %w", err)
+	}
+
+	var manifest Manifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return Manifest{}, fmt.Errorf("parsing manifest: %w", err)
+	}
+	if manifest.Name == "" {
+		return Manifest{}, errManifestNoName
+	}
+	if manifest.Executable == "" {
+		return Manifest{}, errManifestNoExecutable
+	}
+	return manifest, nil
+}
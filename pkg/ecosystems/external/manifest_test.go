@@ -0,0 +1,61 @@
+package external
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadPlugins(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "b-buck.json"),
+		[]byte(`{"name": "buck", "executable": "bin/buck-resolver", "dependencies": ["bazel"]}`), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "a-pants.json"),
+		[]byte(`{"name": "pants", "executable": "/opt/pants-resolver", "args": ["--json"]}`), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "README.md"), []byte("not a manifest"), 0o600))
+
+	plugins, err := LoadPlugins(dir)
+	require.NoError(t, err)
+
+	require.Len(t, plugins, 2)
+	assert.Equal(t, "pants", plugins[0].GetName())
+	assert.Equal(t, "/opt/pants-resolver", plugins[0].executable)
+	assert.Equal(t, []string{"--json"}, plugins[0].args)
+	assert.Empty(t, plugins[0].Dependencies())
+
+	assert.Equal(t, "buck", plugins[1].GetName())
+	assert.Equal(t, filepath.Join(dir, "bin", "buck-resolver"), plugins[1].executable)
+	assert.Equal(t, []string{"bazel"}, plugins[1].Dependencies())
+}
+
+func TestLoadPlugins_InvalidManifest(t *testing.T) {
+	tests := map[string]struct {
+		manifest string
+		wantErr  error
+	}{
+		"missing name":       {manifest: `{"executable": "resolver"}`, wantErr: errManifestNoName},
+		"missing executable": {manifest: `{"name": "buck"}`, wantErr: errManifestNoExecutable},
+		"not json":           {manifest: `name: buck`},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			dir := t.TempDir()
+			require.NoError(t, os.WriteFile(filepath.Join(dir, "plugin.json"), []byte(tc.manifest), 0o600))
+
+			_, err := LoadPlugins(dir)
+			require.Error(t, err)
+			if tc.wantErr != nil {
+				assert.ErrorIs(t, err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestLoadPlugins_MissingDirectory(t *testing.T) {
+	_, err := LoadPlugins(filepath.Join(t.TempDir(), "missing"))
+	assert.Error(t, err)
+}
@@ -0,0 +1,115 @@
+package external
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+
+	"github.com/snyk/cli-extension-dep-graph/v2/pkg/ecosystems"
+	"github.com/snyk/cli-extension-dep-graph/v2/pkg/ecosystems/logger"
+)
+
+// Plugin runs an out-of-process resolver as an ecosystems.SCAPlugin. The
+// executable receives a request as JSON on stdin and streams one result per
+// line as JSON on stdout; see request and resultLine. Anything it writes to
+// stderr is included in the error when it exits non-zero.
+type Plugin struct {
+	name         string
+	executable   string
+	args         []string
+	dependencies []string
+}
+
+var _ ecosystems.SCAPlugin = (*Plugin)(nil)
+
+func NewPlugin(manifest Manifest) *Plugin {
+	return &Plugin{
+		name:         manifest.Name,
+		executable:   manifest.Executable,
+		args:         manifest.Args,
+		dependencies: manifest.Dependencies,
+	}
+}
+
+func (p *Plugin) GetName() string {
+	return p.name
+}
+
+// Dependencies returns the plugins this one declared it must run after.
+func (p *Plugin) Dependencies() []string {
+	return p.dependencies
+}
+
+// BuildDepGraphsFromDir runs the executable in dir and emits each result it
+// writes. A non-zero exit or a line that is not a valid result fails the
+// run; results already emitted stand. If onGraph returns an error the
+// process is killed and that error is returned unchanged.
+func (p *Plugin) BuildDepGraphsFromDir(
+	ctx context.Context,
+	log logger.Logger,
+	dir string,
+	options *ecosystems.SCAPluginOptions,
+	onGraph ecosystems.OnGraphFunc,
+) error {
+	input, err := json.Marshal(newRequest(dir, options))
+	if err != nil {
+		return fmt.Errorf("encoding %s plugin request: %w", p.name, err)
+	}
+
+	cmdCtx, cancel := ecosystems.CommandContext(ctx, p.name+" plugin")
+	defer cancel()
+
+	cmd := exec.CommandContext(cmdCtx, p.executable, p.args...)
+	cmd.Dir = dir
+	cmd.Stdin = bytes.NewReader(input)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return fmt.Errorf("connecting to %s plugin output: %w", p.name, err)
+	}
+
+	log.Debug(ctx, "Starting external plugin", logger.Attr("plugin", p.name), logger.Attr("executable", p.executable))
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("starting %s plugin: %w", p.name, err)
+	}
+
+	streamErr := p.stream(stdout, onGraph)
+	if streamErr != nil {
+		// Stop the plugin rather than wait for it to finish work we will discard.
+		cancel()
+	}
+	waitErr := cmd.Wait()
+
+	if streamErr != nil {
+		return streamErr
+	}
+	if waitErr != nil {
+		return fmt.Errorf("%s plugin failed: %w\nstderr: %s", p.name, ecosystems.CommandError(cmdCtx, waitErr), stderr.String())
+	}
+	if stderr.Len() > 0 {
+		log.Debug(ctx, "External plugin wrote to stderr", logger.Attr("plugin", p.name), logger.Attr("stderr", stderr.String()))
+	}
+	return nil
+}
+
+// stream decodes results from r until EOF, passing each to onGraph.
+func (p *Plugin) stream(r io.Reader, onGraph ecosystems.OnGraphFunc) error {
+	dec := json.NewDecoder(r)
+	for {
+		var line resultLine
+		if err := dec.Decode(&line); err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return fmt.Errorf("decoding %s plugin output: %w", p.name, err)
+		}
+		if err := onGraph(line.toResult()); err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,109 @@
+package external
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/snyk/dep-graph/go/pkg/depgraph"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/snyk/cli-extension-dep-graph/v2/pkg/ecosystems"
+	"github.com/snyk/cli-extension-dep-graph/v2/pkg/ecosystems/logger"
+	"github.com/snyk/cli-extension-dep-graph/v2/pkg/ecosystems/scatest"
+)
+
+// writeScript writes an executable shell script to dir and returns its path.
+func writeScript(t *testing.T, dir, body string) string {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("external plugin tests use shell scripts")
+	}
+	path := filepath.Join(dir, "resolver.sh")
+	require.NoError(t, os.WriteFile(path, []byte("#!/bin/sh\n"+body), 0o755))
+	return path
+}
+
+func TestPlugin_StreamsResults(t *testing.T) {
+	toolDir := t.TempDir()
+	scanDir := t.TempDir()
+
+	builder, err := depgraph.NewBuilder(&depgraph.PkgManager{Name: "buck"}, &depgraph.PkgInfo{Name: "app", Version: "1.0.0"})
+	require.NoError(t, err)
+	graphLine, err := json.Marshal(resultLine{
+		DepGraph:       builder.Build(),
+		ProcessedFiles: []string{"BUCK"},
+	})
+	require.NoError(t, err)
+	output := string(graphLine) + "\n" + `{"projectDescriptor":{"identity":{"type":"buck"}},"error":"lib/BUCK: target not found"}` + "\n"
+	outputFile := filepath.Join(toolDir, "output.jsonl")
+	require.NoError(t, os.WriteFile(outputFile, []byte(output), 0o600))
+
+	script := writeScript(t, toolDir, `cat > "$PWD/request.json"; cat "$1"`)
+	plugin := NewPlugin(Manifest{Name: "buck", Executable: script, Args: []string{outputFile}})
+
+	opts := ecosystems.NewPluginOptions().WithAllProjects(true).WithExcludePaths([]string{"vendor/BUCK"})
+	results, err := scatest.Run(context.Background(), plugin, logger.Nop(), scanDir, opts)
+	require.NoError(t, err)
+
+	require.Len(t, results, 2)
+	require.NotNil(t, results[0].DepGraph)
+	assert.Equal(t, "app", results[0].DepGraph.GetRootPkg().Info.Name)
+	assert.Equal(t, []string{"BUCK"}, results[0].ProcessedFiles)
+	require.Error(t, results[1].Error)
+	assert.Equal(t, "lib/BUCK: target not found", results[1].Error.Error())
+	assert.Equal(t, "buck", results[1].ProjectDescriptor.Identity.ProjectType)
+
+	rawRequest, err := os.ReadFile(filepath.Join(scanDir, "request.json"))
+	require.NoError(t, err)
+	// Decode generically, as a plugin written in another language would.
+	var req struct {
+		ProtocolVersion int    `json:"protocolVersion"`
+		Dir             string `json:"dir"`
+		Options         struct {
+			Global struct {
+				AllProjects  bool     `json:"allProjects"`
+				ExcludePaths []string `json:"excludePaths"`
+			} `json:"global"`
+		} `json:"options"`
+	}
+	require.NoError(t, json.Unmarshal(rawRequest, &req))
+	assert.Equal(t, 1, req.ProtocolVersion)
+	assert.Equal(t, scanDir, req.Dir)
+	assert.True(t, req.Options.Global.AllProjects)
+	assert.Equal(t, []string{"vendor/BUCK"}, req.Options.Global.ExcludePaths)
+}
+
+func TestPlugin_NonZeroExitIncludesStderr(t *testing.T) {
+	script := writeScript(t, t.TempDir(), `echo "bad workspace" >&2; exit 2`)
+	plugin := NewPlugin(Manifest{Name: "buck", Executable: script})
+
+	_, err := scatest.Run(context.Background(), plugin, logger.Nop(), t.TempDir(), ecosystems.NewPluginOptions())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "buck plugin failed")
+	assert.Contains(t, err.Error(), "bad workspace")
+}
+
+func TestPlugin_MalformedOutput(t *testing.T) {
+	script := writeScript(t, t.TempDir(), `echo "not json"`)
+	plugin := NewPlugin(Manifest{Name: "buck", Executable: script})
+
+	_, err := scatest.Run(context.Background(), plugin, logger.Nop(), t.TempDir(), ecosystems.NewPluginOptions())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "decoding buck plugin output")
+}
+
+func TestPlugin_OnGraphErrorIsReturnedUnchanged(t *testing.T) {
+	script := writeScript(t, t.TempDir(), `while true; do echo '{"projectDescriptor":{"identity":{}}}'; done`)
+	plugin := NewPlugin(Manifest{Name: "buck", Executable: script})
+
+	stop := errors.New("stop")
+	err := plugin.BuildDepGraphsFromDir(context.Background(), logger.Nop(), t.TempDir(), ecosystems.NewPluginOptions(),
+		func(ecosystems.SCAResult) error { return stop })
+	assert.Same(t, stop, err)
+}
@@ -0,0 +1,162 @@
+package external
+
+import (
+	"errors"
+
+	"github.com/snyk/dep-graph/go/pkg/depgraph"
+
+	"github.com/snyk/cli-extension-dep-graph/v2/pkg/ecosystems"
+	"github.com/snyk/cli-extension-dep-graph/v2/pkg/identity"
+)
+
+// protocolVersion is the version of the request format written to an external
+// plugin's stdin. It is bumped whenever a field is renamed or removed, or
+// changes meaning; adding a field does not change it.
+const protocolVersion = 1
+
+// request is the single JSON document written to an external plugin's stdin.
+// Its keys are part of the protocol and are spelled out in json tags, rather
+// than derived from the Go field names of ecosystems.SCAPluginOptions, so
+// that renaming an option in Go does not break plugins.
+type request struct {
+	ProtocolVersion int `json:"protocolVersion"`
+	// Dir is the absolute or caller-relative directory to scan. The plugin
+	// also runs with Dir as its working directory.
+	Dir     string         `json:"dir"`
+	Options requestOptions `json:"options"`
+}
+
+// requestOptions carries the ecosystems.SCAPluginOptions that concern a
+// plugin. Registry-level settings, such as plugin selection and timeouts,
+// are left out.
+type requestOptions struct {
+	Global requestGlobalOptions `json:"global"`
+	Python requestPythonOptions `json:"python"`
+	Gradle requestGradleOptions `json:"gradle"`
+	Bazel  requestBazelOptions  `json:"bazel"`
+}
+
+type requestGlobalOptions struct {
+	TargetFile                    *string  `json:"targetFile,omitempty"`
+	AllProjects                   bool     `json:"allProjects"`
+	IncludeDev                    bool     `json:"includeDev"`
+	Exclude                       []string `json:"exclude"`
+	ExcludePaths                  []string `json:"excludePaths"`
+	FailFast                      bool     `json:"failFast"`
+	AllowOutOfSync                bool     `json:"allowOutOfSync"`
+	ForceSingleGraph              bool     `json:"forceSingleGraph"`
+	ForceIncludeWorkspacePackages bool     `json:"forceIncludeWorkspacePackages"`
+	ProjectName                   *string  `json:"projectName,omitempty"`
+	IncludeProvenance             bool     `json:"includeProvenance"`
+	PruneRepeatedSubdependencies  bool     `json:"pruneRepeatedSubdependencies"`
+	StrictGraphValidation         bool     `json:"strictGraphValidation"`
+	RespectGitignore              bool     `json:"respectGitignore"`
+	DetectionDepth                *int     `json:"detectionDepth,omitempty"`
+	WorkspacePackage              *string  `json:"workspacePackage,omitempty"`
+	RawFlags                      []string `json:"rawFlags"`
+}
+
+type requestPythonOptions struct {
+	NoBuildIsolation bool `json:"noBuildIsolation"`
+}
+
+type requestGradleOptions struct {
+	ConfigurationMatching   string `json:"configurationMatching,omitempty"`
+	ConfigurationAttributes string `json:"configurationAttributes,omitempty"`
+	SubProject              string `json:"subProject,omitempty"`
+	AllSubProjects          bool   `json:"allSubProjects"`
+	InitScript              string `json:"initScript,omitempty"`
+	SkipWrapper             bool   `json:"skipWrapper"`
+	NormalizeDeps           bool   `json:"normalizeDeps"`
+}
+
+type requestBazelOptions struct {
+	TargetQuery string `json:"targetQuery,omitempty"`
+	MaxTargets  *int   `json:"maxTargets,omitempty"`
+	Jvm         bool   `json:"jvm"`
+	Go          bool   `json:"go"`
+}
+
+// newRequest builds the request for scanning dir with options, which may be
+// nil.
+func newRequest(dir string, options *ecosystems.SCAPluginOptions) request {
+	if options == nil {
+		options = ecosystems.NewPluginOptions()
+	}
+	global, gradle, bazel := &options.Global, &options.Gradle, &options.Bazel
+	return request{
+		ProtocolVersion: protocolVersion,
+		Dir:             dir,
+		Options: requestOptions{
+			Global: requestGlobalOptions{
+				TargetFile:                    global.TargetFile,
+				AllProjects:                   global.AllProjects,
+				IncludeDev:                    global.IncludeDev,
+				Exclude:                       nonNil(global.Exclude),
+				ExcludePaths:                  nonNil(global.ExcludePaths),
+				FailFast:                      global.FailFast,
+				AllowOutOfSync:                global.AllowOutOfSync,
+				ForceSingleGraph:              global.ForceSingleGraph,
+				ForceIncludeWorkspacePackages: global.ForceIncludeWorkspacePackages,
+				ProjectName:                   global.ProjectName,
+				IncludeProvenance:             global.IncludeProvenance,
+				PruneRepeatedSubdependencies:  global.PruneRepeatedSubdependencies,
+				StrictGraphValidation:         global.StrictGraphValidation,
+				RespectGitignore:              global.RespectGitignore,
+				DetectionDepth:                global.DetectionDepth,
+				WorkspacePackage:              global.WorkspacePackage,
+				RawFlags:                      nonNil(global.RawFlags),
+			},
+			Python: requestPythonOptions{NoBuildIsolation: options.Python.NoBuildIsolation},
+			Gradle: requestGradleOptions{
+				ConfigurationMatching:   gradle.ConfigurationMatching,
+				ConfigurationAttributes: gradle.ConfigurationAttributes,
+				SubProject:              gradle.SubProject,
+				AllSubProjects:          gradle.AllSubProjects,
+				InitScript:              gradle.InitScript,
+				SkipWrapper:             gradle.SkipWrapper,
+				NormalizeDeps:           gradle.NormalizeDeps,
+			},
+			Bazel: requestBazelOptions{
+				TargetQuery: bazel.TargetQuery,
+				MaxTargets:  bazel.MaxTargets,
+				Jvm:         bazel.Jvm,
+				Go:          bazel.Go,
+			},
+		},
+	}
+}
+
+// nonNil returns list, or an empty list for nil, so that lists are always
+// encoded as JSON arrays rather than null.
+func nonNil[S ~[]string](list S) []string {
+	if list == nil {
+		return []string{}
+	}
+	return list
+}
+
+// resultLine is one line of an external plugin's stdout. It mirrors
+// ecosystems.SCAResult, except that the error is a message: a line with Error
+// set is a per-project failure and may still carry a partial DepGraph.
+type resultLine struct {
+	DepGraph          *depgraph.DepGraph           `json:"depGraph,omitempty"`
+	ProjectDescriptor identity.ProjectDescriptor   `json:"projectDescriptor"`
+	ResolverMetadata  *ecosystems.ResolverMetadata `json:"meta,omitempty"`
+	// ProcessedFiles are relative to Dir, like those of the built-in plugins.
+	ProcessedFiles []string `json:"processedFiles,omitempty"`
+	Error          string   `json:"error,omitempty"`
+}
+
+func (l resultLine) toResult() ecosystems.SCAResult {
+	result := ecosystems.SCAResult{
+		DepGraph:          l.DepGraph,
+		ProjectDescriptor: l.ProjectDescriptor,
+		ResolverMetadata:  l.ResolverMetadata,
+		ProcessedFiles:    l.ProcessedFiles,
+	}
+	if l.Error != "" {
+		result.Error = errors.New(l.Error)
+	}
+	return result
+}
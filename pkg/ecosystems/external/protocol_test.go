@@ -0,0 +1,60 @@
+package external
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/snyk/cli-extension-dep-graph/v2/pkg/ecosystems"
+)
+
+// TestNewRequest_WireFormat locks in the keys of the request document. They
+// are part of the external plugin protocol: changing one breaks plugins, and
+// must come with a protocolVersion bump.
+func TestNewRequest_WireFormat(t *testing.T) {
+	opts := ecosystems.NewPluginOptions().
+		WithAllProjects(true).
+		WithTargetFile("BUCK").
+		WithExcludePaths([]string{"vendor/BUCK"}).
+		WithBazelGo(true)
+	opts.Global.OnlyPlugins = []string{"buck"}
+
+	got, err := json.Marshal(newRequest("/scan", opts))
+	require.NoError(t, err)
+
+	assert.JSONEq(t, `{
+		"protocolVersion": 1,
+		"dir": "/scan",
+		"options": {
+			"global": {
+				"targetFile": "BUCK",
+				"allProjects": true,
+				"includeDev": false,
+				"exclude": [],
+				"excludePaths": ["vendor/BUCK"],
+				"failFast": false,
+				"allowOutOfSync": false,
+				"forceSingleGraph": false,
+				"forceIncludeWorkspacePackages": false,
+				"includeProvenance": false,
+				"pruneRepeatedSubdependencies": false,
+				"strictGraphValidation": false,
+				"respectGitignore": false,
+				"rawFlags": []
+			},
+			"python": {"noBuildIsolation": false},
+			"gradle": {"allSubProjects": false, "skipWrapper": false, "normalizeDeps": false},
+			"bazel": {"jvm": false, "go": true}
+		}
+	}`, string(got))
+}
+
+func TestNewRequest_NilOptions(t *testing.T) {
+	req := newRequest("/scan", nil)
+
+	assert.Equal(t, protocolVersion, req.ProtocolVersion)
+	assert.Empty(t, req.Options.Global.ExcludePaths)
+	assert.NotNil(t, req.Options.Global.ExcludePaths)
+}
@@ -13,6 +13,11 @@ import (
 
 const pkgManagerName = "gradle"
 
+// reasonLabel is the node label holding why Gradle failed to resolve an
+// unresolved dependency. It is only set for the why workflow, as the messages
+// can hold repository URLs.
+const reasonLabel = "reason"
+
 // buildDepGraph converts a single gradleProject into a *depgraph.DepGraph by
 // merging the resolved dependencies from all available configurations.
 // If options.Gradle.ConfigurationMatching is set, only configurations matching
@@ -62,6 +67,12 @@ func buildDepGraph(proj *gradleProject, options *ecosystems.SCAPluginOptions) (*
 		connectOnce:   connectOnce,
 		provenanceMap: provenanceMap,
 	}
+	if options != nil && options.Global.PruneRepeatedSubdependencies {
+		ctx.expandedWithDeps = make(map[string]bool)
+	}
+	if options != nil {
+		ctx.whyLabels = options.Global.WhyLabels
+	}
 
 	// Merge all configurations into a single graph.
 	// Iterate configurations in Gradle declaration order (preserved by the array
@@ -91,6 +102,12 @@ type depGraphContext struct {
 	builder       *depgraph.Builder
 	connectOnce   func(parentID, childID string) error
 	provenanceMap map[string]*allDepEntry
+	// expandedWithDeps, set when repeated sub-dependencies are pruned, records
+	// the components expanded with at least one child. Later references to
+	// them become pruned leaves instead of edges to the expanded node.
+	expandedWithDeps map[string]bool
+	// whyLabels adds the reason label to unresolved dependencies.
+	whyLabels bool
 }
 
 // addDep recursively adds a resolved dependency node and its children to the
@@ -107,7 +124,9 @@ type depGraphContext struct {
 //     to maintain the DAG property and avoid infinite recursion.
 //   - Components flagged with `pruned: "visited"` (or already in `processed`)
 //     are connected to their existing node instead of creating pruned nodes,
-//     allowing multiple parents while avoiding infinite recursion.
+//     allowing multiple parents while avoiding infinite recursion. When
+//     repeated sub-dependencies are pruned, those with children become
+//     labeled `pruned` leaves instead.
 //   - Components flagged with `constraint: true` (from platform BOMs, dependency
 //     locking, or constraints {} blocks) become labeled `constraint` leaves
 //     with a `:constraint` node-ID suffix. They do not affect `processed`, so a
@@ -121,10 +140,12 @@ func (ctx *depGraphContext) addDep(dep *gradleDep, parentID string, processed ma
 		// ":unresolved" suffix avoids colliding with a resolved node for the same coordinate in another configuration.
 		nodeID, name, version := depNodeParts(dep.ID)
 		unresolvedID := nodeID + ":unresolved"
+		labels := map[string]string{"unresolved": "true"}
+		if ctx.whyLabels && dep.Reason != "" {
+			labels[reasonLabel] = dep.Reason
+		}
 		ctx.builder.AddNode(unresolvedID, createPkgInfo(name, version, nil, ctx.provenanceMap != nil),
-			depgraph.WithNodeInfo(&depgraph.NodeInfo{
-				Labels: map[string]string{"unresolved": "true"},
-			}),
+			depgraph.WithNodeInfo(&depgraph.NodeInfo{Labels: labels}),
 		)
 		return ctx.connectOnce(parentID, unresolvedID)
 	}
@@ -153,19 +174,11 @@ func (ctx *depGraphContext) addDep(dep *gradleDep, parentID string, processed ma
 	if dep.Pruned == pruneCycle {
 		// Record a pruned leaf for cycles to maintain DAG property and avoid
 		// infinite recursion.
-		prunedID := nodeID + ":pruned"
-		var provenanceEntry *allDepEntry
-		if ctx.provenanceMap != nil {
-			provenanceEntry = ctx.provenanceMap[dep.ID]
-		}
-		pkgInfo := createPkgInfo(name, version, provenanceEntry, ctx.provenanceMap != nil)
-		ctx.builder.AddNode(prunedID, pkgInfo,
-			depgraph.WithNodeInfo(&depgraph.NodeInfo{
-				Labels: map[string]string{"pruned": "true"},
-			}),
-		)
-		return ctx.connectOnce(parentID, prunedID)
+		return ctx.addPrunedLeaf(dep, parentID)
 	} else if dep.Pruned == pruneVisited || processed[nodeID] {
+		if ctx.expandedWithDeps[nodeID] {
+			return ctx.addPrunedLeaf(dep, parentID)
+		}
 		// For visited nodes, connect to the existing node instead of creating
 		// a pruned version. This produces a DAG where nodes can have multiple
 		// parents but no cycles.
@@ -183,6 +196,9 @@ func (ctx *depGraphContext) addDep(dep *gradleDep, parentID string, processed ma
 	}
 
 	processed[nodeID] = true
+	if ctx.expandedWithDeps != nil && len(dep.Dependencies) > 0 {
+		ctx.expandedWithDeps[nodeID] = true
+	}
 	for _, child := range dep.Dependencies {
 		if err := ctx.addDep(&child, nodeID, processed); err != nil {
 			return err
@@ -192,6 +208,24 @@ func (ctx *depGraphContext) addDep(dep *gradleDep, parentID string, processed ma
 	return nil
 }
 
+// addPrunedLeaf connects parentID to a leaf labeled `pruned` standing in for
+// dep, whose children are listed elsewhere or would form a cycle.
+func (ctx *depGraphContext) addPrunedLeaf(dep *gradleDep, parentID string) error {
+	nodeID, name, version := depNodeParts(dep.ID)
+	prunedID := nodeID + ":pruned"
+	var provenanceEntry *allDepEntry
+	if ctx.provenanceMap != nil {
+		provenanceEntry = ctx.provenanceMap[dep.ID]
+	}
+	pkgInfo := createPkgInfo(name, version, provenanceEntry, ctx.provenanceMap != nil)
+	ctx.builder.AddNode(prunedID, pkgInfo,
+		depgraph.WithNodeInfo(&depgraph.NodeInfo{
+			Labels: map[string]string{ecosystems.PrunedLabel: "true"},
+		}),
+	)
+	return ctx.connectOnce(parentID, prunedID)
+}
+
 // depNodeParts splits a GAV-style dependency ID into a stable node ID, name and version.
 // For standard "group:artifact:version" IDs the node ID uses "@" as separator
 // (consistent with other Snyk dep-graph implementations).
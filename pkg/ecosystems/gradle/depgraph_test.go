@@ -154,6 +154,33 @@ func TestBuildDepGraph(t *testing.T) {
 		assert.Equal(t, 1, sharedEdgesFromB, "b should reference the same shared node")
 	})
 
+	t.Run("prunes visited dependencies with children when repeated sub-dependencies are pruned", func(t *testing.T) {
+		proj := makeProject("com.example", "app", "1.0.0", []gradleConfig{
+			makeConfig("runtimeClasspath", "com.example:app:1.0.0", []gradleDep{
+				makeDep("com.example:a:1.0.0",
+					makeDep("com.example:shared:1.0.0", makeDep("com.example:leaf:1.0.0")),
+					makeDep("com.example:leaf-only:1.0.0"),
+				),
+				makeDep("com.example:b:1.0.0",
+					gradleDep{ID: "com.example:shared:1.0.0", Pruned: pruneVisited},
+					gradleDep{ID: "com.example:leaf-only:1.0.0", Pruned: pruneVisited},
+				),
+			}),
+		})
+
+		dg, err := buildDepGraph(&proj, ecosystems.NewPluginOptions().WithPruneRepeatedSubdependencies(true))
+		require.NoError(t, err)
+
+		bNode := findNodeByID(t, dg, "com.example:b@1.0.0")
+		assert.Equal(t, 1, countEdgesTo(bNode, "com.example:shared@1.0.0:pruned"), "a repeated subtree becomes a pruned leaf")
+		assert.Equal(t, 1, countEdgesTo(bNode, "com.example:leaf-only@1.0.0"), "a repeated leaf has nothing to prune")
+
+		prunedNode := findNodeByID(t, dg, "com.example:shared@1.0.0:pruned")
+		require.NotNil(t, prunedNode.Info)
+		assert.Equal(t, "true", prunedNode.Info.Labels[ecosystems.PrunedLabel])
+		assert.Empty(t, prunedNode.Deps)
+	})
+
 	t.Run("emits unresolved dependencies as labeled leaf nodes", func(t *testing.T) {
 		proj := makeProject("com.example", "app", "1.0.0", []gradleConfig{
 			makeConfig("runtimeClasspath", "com.example:app:1.0.0", []gradleDep{
@@ -177,6 +204,25 @@ func TestBuildDepGraph(t *testing.T) {
 		assert.False(t, hasReason, "Gradle failure messages must not be copied onto dep-graph labels")
 	})
 
+	t.Run("labels unresolved dependencies with the failure reason for why", func(t *testing.T) {
+		proj := makeProject("com.example", "app", "1.0.0", []gradleConfig{
+			makeConfig("runtimeClasspath", "com.example:app:1.0.0", []gradleDep{
+				{ID: "com.example:missing:1.0.0", Unresolved: true, Reason: "Could not resolve com.example:missing:1.0.0"},
+				makeDep("com.google.guava:guava:32.1.2-jre"),
+			}),
+		})
+
+		dg, err := buildDepGraph(&proj, ecosystems.NewPluginOptions().WithWhyLabels(true))
+		require.NoError(t, err)
+
+		unresolvedNode := findNodeByID(t, dg, "com.example:missing@1.0.0:unresolved")
+		require.NotNil(t, unresolvedNode.Info)
+		assert.Equal(t, "Could not resolve com.example:missing:1.0.0", unresolvedNode.Info.Labels[reasonLabel])
+		if info := findNodeByID(t, dg, "com.google.guava:guava@32.1.2-jre").Info; info != nil {
+			assert.NotContains(t, info.Labels, reasonLabel, "resolved dependencies have no reason")
+		}
+	})
+
 	t.Run("unresolved dep node ID does not collide with a successfully-resolved node", func(t *testing.T) {
 		// Under different configurations the same coordinate could resolve in one
 		// and fail in another. Both nodes must coexist in the merged graph.
@@ -9,6 +9,8 @@ import (
 	"os"
 	"os/exec"
 	"strings"
+
+	"github.com/snyk/cli-extension-dep-graph/v2/pkg/ecosystems"
 )
 
 const (
@@ -43,7 +45,10 @@ func runInitScript(ctx context.Context, projectDir, gradleBinary, initScriptPath
 		":snykDependencyGraph",
 	}, extraArgs...)
 
-	cmd := exec.CommandContext(ctx, gradleBinary, args...)
+	cmdCtx, cancel := ecosystems.CommandContext(ctx, "gradle")
+	defer cancel()
+
+	cmd := exec.CommandContext(cmdCtx, gradleBinary, args...)
 	cmd.Dir = projectDir
 
 	// Use streaming for stdout to handle potentially large output
@@ -71,7 +76,7 @@ func runInitScript(ctx context.Context, projectDir, gradleBinary, initScriptPath
 	if err := cmd.Wait(); err != nil {
 		return nil, fmt.Errorf(
 			"gradle execution failed in %s: %w\nstderr:\n%s",
-			projectDir, err, stderr.String(),
+			projectDir, ecosystems.CommandError(cmdCtx, err), stderr.String(),
 		)
 	}
 
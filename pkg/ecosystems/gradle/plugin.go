@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 	"sort"
 	"strings"
 
@@ -31,8 +32,12 @@ type Plugin struct {
 	normalizeDepsPostHook NormalizeDepsPostHook
 }
 
-// Compile-time assertion that Plugin satisfies the SCAPlugin interface.
-var _ ecosystems.SCAPlugin = (*Plugin)(nil)
+// Compile-time assertion that Plugin satisfies the SCAPlugin and
+// ProjectDetector interfaces.
+var (
+	_ ecosystems.SCAPlugin       = (*Plugin)(nil)
+	_ ecosystems.ProjectDetector = (*Plugin)(nil)
+)
 
 func (p Plugin) GetName() string {
 	return PluginName
@@ -106,6 +111,93 @@ func (p Plugin) BuildDepGraphsFromDir(
 	return p.processGradleFiles(ctx, log, files, dir, options, onGraph)
 }
 
+// DetectProjects reports the Gradle projects BuildDepGraphsFromDir would
+// run, without invoking Gradle. Only Gradle knows the real sub-project
+// layout, so this approximates the runtime deduplication: a directory with a
+// settings file is treated as a multi-project root that claims every Gradle
+// file beneath it, and any other directory is a project of its own.
+func (p Plugin) DetectProjects(
+	ctx context.Context, _ logger.Logger, dir string, options *ecosystems.SCAPluginOptions,
+	onProject ecosystems.OnDetectFunc,
+) error {
+	if err := ValidateOptions(dir, options); err != nil {
+		return fmt.Errorf("gradle: invalid options: %w", err)
+	}
+
+	files, err := p.discoverGradleFiles(ctx, dir, options)
+	if err != nil {
+		return fmt.Errorf("gradle: failed to discover files: %w", err)
+	}
+
+	for _, project := range groupGradleProjects(files) {
+		if err := onProject(project); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// groupGradleProjects groups discovered Gradle files by project directory,
+// shallowest first, folding everything under a settings file's directory
+// into that directory's project.
+func groupGradleProjects(files []discovery.FindResult) []ecosystems.DetectedProject {
+	byDir := make(map[string][]string)
+	dirs := make([]string, 0, len(files))
+	for _, f := range files {
+		d := filepath.Dir(f.RelPath)
+		if _, ok := byDir[d]; !ok {
+			dirs = append(dirs, d)
+		}
+		byDir[d] = append(byDir[d], f.RelPath)
+	}
+	sort.SliceStable(dirs, func(i, j int) bool {
+		depthI := strings.Count(dirs[i], string(filepath.Separator))
+		depthJ := strings.Count(dirs[j], string(filepath.Separator))
+		if depthI != depthJ {
+			return depthI < depthJ
+		}
+		return dirs[i] < dirs[j]
+	})
+
+	var projects []ecosystems.DetectedProject
+	covered := make(map[string]bool)
+	for _, d := range dirs {
+		if covered[d] {
+			continue
+		}
+		covered[d] = true
+
+		claimed := append([]string(nil), byDir[d]...)
+		if slices.ContainsFunc(byDir[d], isSettingsFile) {
+			for _, sub := range dirs {
+				if !covered[sub] && isUnderDir(sub, d) {
+					covered[sub] = true
+					claimed = append(claimed, byDir[sub]...)
+				}
+			}
+		}
+
+		targetFile := byDir[d][0]
+		if i := slices.IndexFunc(byDir[d], isBuildFile); i >= 0 {
+			targetFile = byDir[d][i]
+		}
+		projects = append(projects, ecosystems.DetectedProject{
+			TargetFile:   targetFile,
+			ProjectType:  pkgManagerName,
+			ClaimedFiles: claimed,
+		})
+	}
+	return projects
+}
+
+// isUnderDir reports whether the relative path sub lies beneath dir.
+func isUnderDir(sub, dir string) bool {
+	if dir == "." {
+		return sub != "."
+	}
+	return strings.HasPrefix(sub, dir+string(filepath.Separator))
+}
+
 // discoverGradleFiles discovers Gradle build files based on the provided options.
 func (p Plugin) discoverGradleFiles(ctx context.Context, dir string, options *ecosystems.SCAPluginOptions) ([]discovery.FindResult, error) {
 	switch {
@@ -277,13 +369,7 @@ func (p Plugin) discoverAllGradleProjects(ctx context.Context, dir string, optio
 		discovery.WithInclude("settings.gradle.kts"),
 		discovery.WithCommonExcludes(),
 	}
-
-	if len(options.Global.Exclude) > 0 {
-		findOpts = append(findOpts, discovery.WithExcludes(options.Global.Exclude...))
-	}
-	if len(options.Global.ExcludePaths) > 0 {
-		findOpts = append(findOpts, discovery.WithExcludes(options.Global.ExcludePaths...))
-	}
+	findOpts = append(findOpts, options.DiscoveryOptions()...)
 
 	files, err := discovery.FindFiles(ctx, dir, findOpts...)
 	if err != nil {
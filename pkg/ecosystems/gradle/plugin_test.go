@@ -12,6 +12,7 @@ import (
 	"testing"
 
 	"github.com/snyk/cli-extension-dep-graph/v2/pkg/ecosystems"
+	"github.com/snyk/cli-extension-dep-graph/v2/pkg/ecosystems/discovery"
 	"github.com/snyk/cli-extension-dep-graph/v2/pkg/ecosystems/logger"
 	"github.com/snyk/cli-extension-dep-graph/v2/pkg/ecosystems/metadata"
 	"github.com/stretchr/testify/assert"
@@ -273,6 +274,48 @@ func TestDiscoverGradleFiles(t *testing.T) {
 
 // ── isSettingsFile ──────────────────────────────────────────────────────────
 
+func TestGroupGradleProjects(t *testing.T) {
+	file := func(rel string) discovery.FindResult {
+		return discovery.FindResult{Path: filepath.Join("/project", rel), RelPath: rel}
+	}
+
+	t.Run("settings file claims every Gradle file beneath it", func(t *testing.T) {
+		got := groupGradleProjects([]discovery.FindResult{
+			file(filepath.Join("app", "build.gradle")),
+			file("settings.gradle"),
+			file("build.gradle"),
+		})
+		require.Len(t, got, 1)
+		assert.Equal(t, "build.gradle", got[0].TargetFile)
+		assert.Equal(t, pkgManagerName, got[0].ProjectType)
+		assert.ElementsMatch(t, []string{"settings.gradle", "build.gradle", filepath.Join("app", "build.gradle")}, got[0].ClaimedFiles)
+	})
+
+	t.Run("build files without a settings file are separate projects", func(t *testing.T) {
+		got := groupGradleProjects([]discovery.FindResult{
+			file(filepath.Join("b", "build.gradle.kts")),
+			file(filepath.Join("a", "build.gradle")),
+		})
+		require.Len(t, got, 2)
+		assert.Equal(t, filepath.Join("a", "build.gradle"), got[0].TargetFile)
+		assert.Equal(t, filepath.Join("b", "build.gradle.kts"), got[1].TargetFile)
+	})
+
+	t.Run("nested settings root is its own project", func(t *testing.T) {
+		got := groupGradleProjects([]discovery.FindResult{
+			file("build.gradle"),
+			file(filepath.Join("tools", "settings.gradle.kts")),
+			file(filepath.Join("tools", "cli", "build.gradle.kts")),
+		})
+		require.Len(t, got, 2)
+		assert.Equal(t, []string{"build.gradle"}, got[0].ClaimedFiles)
+		assert.Equal(t, filepath.Join("tools", "settings.gradle.kts"), got[1].TargetFile)
+		assert.ElementsMatch(t,
+			[]string{filepath.Join("tools", "settings.gradle.kts"), filepath.Join("tools", "cli", "build.gradle.kts")},
+			got[1].ClaimedFiles)
+	})
+}
+
 func TestIsSettingsFile(t *testing.T) {
 	settingsFiles := []string{"settings.gradle", "settings.gradle.kts"}
 	buildFiles := []string{"build.gradle", "build.gradle.kts"}
@@ -634,3 +677,28 @@ func TestPlugin_DiscoverAllGradleProjects_HonorsExcludePaths(t *testing.T) {
 	assert.Contains(t, rels, "build.gradle")
 	assert.Contains(t, rels, "b/build.gradle")
 }
+
+// TestPlugin_DiscoverAllGradleProjects_HonorsDetectionDepth locks in that the gradle plugin passes
+// `opts.Global.DetectionDepth` to discovery, so --detection-depth finds the same
+// projects as it does in the legacy CLI.
+func TestPlugin_DiscoverAllGradleProjects_HonorsDetectionDepth(t *testing.T) {
+	tmpDir := t.TempDir()
+	for _, rel := range []string{"build.gradle", "a/build.gradle", "a/b/build.gradle"} {
+		full := filepath.Join(tmpDir, rel)
+		require.NoError(t, os.MkdirAll(filepath.Dir(full), 0o755))
+		require.NoError(t, os.WriteFile(full, []byte(""), 0o644))
+	}
+
+	opts := ecosystems.NewPluginOptions().
+		WithAllProjects(true).
+		WithDetectionDepth(2)
+
+	got, err := Plugin{}.discoverAllGradleProjects(t.Context(), tmpDir, opts)
+	require.NoError(t, err)
+
+	rels := make([]string, len(got))
+	for i, r := range got {
+		rels[i] = filepath.ToSlash(r.RelPath)
+	}
+	assert.ElementsMatch(t, []string{"build.gradle", "a/build.gradle"}, rels)
+}
@@ -2,7 +2,9 @@ package bun
 
 import (
 	"fmt"
+	"maps"
 	"path/filepath"
+	"slices"
 	"strings"
 
 	godepgraph "github.com/snyk/dep-graph/go/pkg/depgraph"
@@ -20,6 +22,11 @@ type depGraphResult struct {
 
 const pkgManager = "bun"
 
+// dependentsLabel is the node label listing, from `bun why`, the packages that
+// directly depend on a package, across every workspace. It is only set for
+// the why workflow.
+const dependentsLabel = "dependents"
+
 // buildDepGraphs produces one dep graph per workspace package, plus one for the
 // root project. Workspace packages terminate the DFS in every graph they appear
 // in as a dependency: their subtrees are walked only in their own dep graph,
@@ -32,9 +39,16 @@ const pkgManager = "bun"
 //
 // If the project contains no workspace packages, a single-element slice is
 // returned containing the root dep graph.
-func buildDepGraphs(rootName, rootVersion string, out *whyOutput) ([]depGraphResult, error) {
+//
+// With whyLabels, each node is labelled with its dependents from the reverse
+// graph, including those in other workspaces' dep graphs.
+func buildDepGraphs(rootName, rootVersion string, out *whyOutput, whyLabels bool) ([]depGraphResult, error) {
 	wsPkgs := workspacePkgs(out.Graph)
 	forward := buildForward(out.Graph)
+	var dependents reverseGraph
+	if whyLabels {
+		dependents = out.Graph
+	}
 
 	// Root dep graph — seeds from all direct root deps; workspace packages are leaves.
 	seeds := make(map[string]struct{}, len(out.ProdDeps)+len(out.DevDeps))
@@ -45,7 +59,7 @@ func buildDepGraphs(rootName, rootVersion string, out *whyOutput) ([]depGraphRes
 		seeds[id] = struct{}{}
 	}
 
-	rootGraph, err := buildSingleDepGraph(rootName, rootVersion, seeds, forward, wsPkgs)
+	rootGraph, err := buildSingleDepGraph(rootName, rootVersion, seeds, forward, wsPkgs, dependents)
 	if err != nil {
 		return nil, fmt.Errorf("building root dep graph: %w", err)
 	}
@@ -75,7 +89,7 @@ func buildDepGraphs(rootName, rootVersion string, out *whyOutput) ([]depGraphRes
 			}
 		}
 
-		wsGraph, err := buildSingleDepGraph(name, version, wsSeeds, forward, otherWS)
+		wsGraph, err := buildSingleDepGraph(name, version, wsSeeds, forward, otherWS, dependents)
 		if err != nil {
 			return nil, fmt.Errorf("building dep graph for %s: %w", wsID, err)
 		}
@@ -88,12 +102,14 @@ func buildDepGraphs(rootName, rootVersion string, out *whyOutput) ([]depGraphRes
 
 // buildSingleDepGraph constructs one dep graph rooted at rootName/rootVersion.
 // It DFS-walks from seeds through forward adjacency, stopping at any node in
-// stopAt (adding it as a leaf but not recursing into its dependencies).
+// stopAt (adding it as a leaf but not recursing into its dependencies). Nodes
+// with an entry in dependents are labelled with it; dependents may be nil.
 func buildSingleDepGraph(
 	rootName, rootVersion string,
 	seeds map[string]struct{},
 	forward map[string]map[string]struct{},
 	stopAt map[string]struct{},
+	dependents reverseGraph,
 ) (*godepgraph.DepGraph, error) {
 	builder, err := godepgraph.NewBuilder(
 		&godepgraph.PkgManager{Name: pkgManager},
@@ -107,7 +123,7 @@ func buildSingleDepGraph(
 	visited := make(map[string]bool)
 
 	for id := range seeds {
-		if err := addNode(builder, rootNodeID, id, forward, stopAt, visited); err != nil {
+		if err := addNode(builder, rootNodeID, id, forward, stopAt, dependents, visited); err != nil {
 			return nil, err
 		}
 	}
@@ -123,6 +139,7 @@ func addNode(
 	parentID, id string,
 	forward map[string]map[string]struct{},
 	stopAt map[string]struct{},
+	dependents reverseGraph,
 	visited map[string]bool,
 ) error {
 	connectNode := func() error {
@@ -136,7 +153,14 @@ func addNode(
 		visited[id] = true
 
 		name, version := splitPkgID(id)
-		builder.AddNode(id, &godepgraph.PkgInfo{Name: name, Version: version})
+		pkgInfo := &godepgraph.PkgInfo{Name: name, Version: version}
+		if ids := dependents[id]; len(ids) > 0 {
+			builder.AddNode(id, pkgInfo, godepgraph.WithNodeInfo(&godepgraph.NodeInfo{
+				Labels: map[string]string{dependentsLabel: strings.Join(slices.Sorted(maps.Keys(ids)), ",")},
+			}))
+		} else {
+			builder.AddNode(id, pkgInfo)
+		}
 
 		if _, stop := stopAt[id]; stop {
 			// Don't walk deps of stop-set nodes; they are roots of their own dep graphs.
@@ -144,7 +168,7 @@ func addNode(
 			return connectNode()
 		}
 		for dep := range forward[id] {
-			if err := addNode(builder, id, dep, forward, stopAt, visited); err != nil {
+			if err := addNode(builder, id, dep, forward, stopAt, dependents, visited); err != nil {
 				return err
 			}
 		}
@@ -33,7 +33,7 @@ func TestBuildDepGraphs_Simple(t *testing.T) {
 		ProdDeps: []string{"debug@4.4.3"},
 	}
 
-	results, err := buildDepGraphs("my-app", "1.0.0", out)
+	results, err := buildDepGraphs("my-app", "1.0.0", out, false)
 	require.NoError(t, err)
 	require.Len(t, results, 1, "non-workspace project produces exactly one dep graph")
 
@@ -66,7 +66,7 @@ func TestBuildDepGraphs_WithWorkspaces(t *testing.T) {
 		},
 	}
 
-	results, err := buildDepGraphs("my-workspace", "1.0.0", out)
+	results, err := buildDepGraphs("my-workspace", "1.0.0", out, false)
 	require.NoError(t, err)
 	require.Len(t, results, 2, "root graph + one per workspace package")
 
@@ -100,7 +100,7 @@ func TestBuildDepGraphs_WorkspaceCycle(t *testing.T) {
 		ProdDeps: []string{"wsA@workspace:packages/a", "wsB@workspace:packages/b"},
 	}
 
-	results, err := buildDepGraphs("root", "0.0.0", out)
+	results, err := buildDepGraphs("root", "0.0.0", out, false)
 	require.NoError(t, err)
 	require.Len(t, results, 3, "root + wsA + wsB")
 
@@ -139,7 +139,7 @@ func TestBuildDepGraphs_HandlesCircularDeps(t *testing.T) {
 	}
 
 	// Must not infinite-loop.
-	results, err := buildDepGraphs("root", "0.0.0", out)
+	results, err := buildDepGraphs("root", "0.0.0", out, false)
 	require.NoError(t, err)
 	require.Len(t, results, 1)
 
@@ -150,6 +150,35 @@ func TestBuildDepGraphs_HandlesCircularDeps(t *testing.T) {
 	assert.Contains(t, nodeDeps(r.graph, "pkg-b@2.0.0"), "pkg-a@1.0.0", "pkg-b → pkg-a (cycle edge present)")
 }
 
+func TestBuildDepGraphs_WhyLabelsListDependents(t *testing.T) {
+	// debug → ms in the root graph; the logger workspace's axios also uses ms.
+	out := &whyOutput{
+		Graph: reverseGraph{
+			"@workspace/logger@workspace:packages/logger": {},
+			"debug@4.4.3":  {},
+			"axios@1.14.0": {"@workspace/logger@workspace:packages/logger": {}},
+			"ms@2.1.3":     {"debug@4.4.3": {}, "axios@1.14.0": {}},
+		},
+		ProdDeps: []string{"@workspace/logger@workspace:packages/logger", "debug@4.4.3"},
+	}
+
+	results, err := buildDepGraphs("my-workspace", "1.0.0", out, true)
+	require.NoError(t, err)
+
+	rootResult := findResultByRoot(t, results, "my-workspace")
+	loggerResult := findResultByRoot(t, results, "@workspace/logger")
+
+	// Dependents come from bun's reverse graph, so ms lists axios in the root
+	// graph even though axios is only walked in the logger's graph.
+	assert.Equal(t, "axios@1.14.0,debug@4.4.3", nodeLabels(rootResult.graph, "ms@2.1.3")[dependentsLabel])
+	assert.Equal(t, "axios@1.14.0,debug@4.4.3", nodeLabels(loggerResult.graph, "ms@2.1.3")[dependentsLabel])
+	assert.Empty(t, nodeLabels(rootResult.graph, "debug@4.4.3"), "root-direct deps have no versioned dependents")
+
+	results, err = buildDepGraphs("my-workspace", "1.0.0", out, false)
+	require.NoError(t, err)
+	assert.Empty(t, nodeLabels(findResultByRoot(t, results, "my-workspace").graph, "ms@2.1.3"), "labels are only added for why")
+}
+
 // nodeDeps returns the NodeIDs of nodeID's direct dependencies in dg.
 func nodeDeps(dg *godepgraph.DepGraph, nodeID string) []string {
 	for _, n := range dg.Graph.Nodes {
@@ -163,3 +192,13 @@ func nodeDeps(dg *godepgraph.DepGraph, nodeID string) []string {
 	}
 	return nil
 }
+
+// nodeLabels returns the labels of nodeID in dg.
+func nodeLabels(dg *godepgraph.DepGraph, nodeID string) map[string]string {
+	for _, n := range dg.Graph.Nodes {
+		if n.NodeID == nodeID && n.Info != nil {
+			return n.Info.Labels
+		}
+	}
+	return nil
+}
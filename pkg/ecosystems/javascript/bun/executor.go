@@ -11,6 +11,8 @@ import (
 	"strings"
 
 	"golang.org/x/mod/semver"
+
+	"github.com/snyk/cli-extension-dep-graph/v2/pkg/ecosystems"
 )
 
 // errBunNotFound is returned when the bun binary is not in PATH.
@@ -54,7 +56,9 @@ func (e *bunCmdExecutor) Run(ctx context.Context, dir string) (io.ReadCloser, er
 		return nil, err
 	}
 
-	cmd := exec.CommandContext(ctx, resolved, "--no-env-file", "why", "*", "--top")
+	cmdCtx, cancel := ecosystems.CommandContext(ctx, pkgManager)
+
+	cmd := exec.CommandContext(cmdCtx, resolved, "--no-env-file", "why", "*", "--top")
 	cmd.Dir = dir
 	cmd.Env = append(cmd.Environ(), "NO_COLOR=1")
 
@@ -67,13 +71,15 @@ func (e *bunCmdExecutor) Run(ctx context.Context, dir string) (io.ReadCloser, er
 	cmd.Stdout = pw
 
 	if err := cmd.Start(); err != nil {
+		cancel()
 		pr.Close()
 		pw.Close()
 		return nil, fmt.Errorf("starting bun why: %w", err)
 	}
 
 	go func() {
-		waitErr := cmd.Wait()
+		defer cancel()
+		waitErr := ecosystems.CommandError(cmdCtx, cmd.Wait())
 		if waitErr != nil {
 			pw.CloseWithError(fmt.Errorf("bun why failed: %w\nstderr: %s", waitErr, stderr.String()))
 		} else {
@@ -85,9 +91,12 @@ func (e *bunCmdExecutor) Run(ctx context.Context, dir string) (io.ReadCloser, er
 }
 
 func checkBunVersion(ctx context.Context, binary string) error {
-	out, err := exec.CommandContext(ctx, binary, "--version").Output()
+	cmdCtx, cancel := ecosystems.CommandContext(ctx, pkgManager)
+	defer cancel()
+
+	out, err := exec.CommandContext(cmdCtx, binary, "--version").Output()
 	if err != nil {
-		return fmt.Errorf("failed to get bun version: %w", err)
+		return fmt.Errorf("failed to get bun version: %w", ecosystems.CommandError(cmdCtx, err))
 	}
 
 	ver, err := parseBunVersion(strings.TrimSpace(string(out)))
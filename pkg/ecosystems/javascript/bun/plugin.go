@@ -25,8 +25,12 @@ type Plugin struct {
 	executor bunWhyRunner
 }
 
-// Compile-time check that Plugin implements the SCAPlugin interface.
-var _ ecosystems.SCAPlugin = (*Plugin)(nil)
+// Compile-time check that Plugin implements the SCAPlugin and
+// ProjectDetector interfaces.
+var (
+	_ ecosystems.SCAPlugin       = (*Plugin)(nil)
+	_ ecosystems.ProjectDetector = (*Plugin)(nil)
+)
 
 func (p Plugin) GetName() string {
 	return PluginName
@@ -62,11 +66,12 @@ func (p Plugin) BuildDepGraphsFromDir(
 	log.Debug(ctx, "Discovered bun.lock files", logger.Attr("count", len(files)))
 
 	exec := p.getExecutor()
+	whyLabels := options != nil && options.Global.WhyLabels
 
 	for _, file := range files {
 		lockFileAbsDir := filepath.Dir(file.Path)
 
-		fileResults := p.buildResults(ctx, log, file.RelPath, lockFileAbsDir, exec)
+		fileResults := p.buildResults(ctx, log, file.RelPath, lockFileAbsDir, exec, whyLabels)
 		// All results from one lockfile share the lockfile in their
 		// ProcessedFiles list; per-result entries also include their
 		// own target file when known.
@@ -91,11 +96,40 @@ func (p Plugin) BuildDepGraphsFromDir(
 	return nil
 }
 
+// DetectProjects reports one project per bun.lock that BuildDepGraphsFromDir
+// would resolve, rooted at the package.json next to it. Workspace packages
+// are only known after running `bun why`, so they are not listed.
+func (p Plugin) DetectProjects(
+	ctx context.Context,
+	_ logger.Logger,
+	dir string,
+	options *ecosystems.SCAPluginOptions,
+	onProject ecosystems.OnDetectFunc,
+) error {
+	files, err := p.discoverLockFiles(ctx, dir, options)
+	if err != nil {
+		return err
+	}
+
+	for _, file := range files {
+		targetFile := filepath.Join(filepath.Dir(file.RelPath), packageJSONFile)
+		if err := onProject(ecosystems.DetectedProject{
+			TargetFile:   targetFile,
+			ProjectType:  pkgManager,
+			ClaimedFiles: []string{file.RelPath, targetFile},
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (p Plugin) buildResults(
 	ctx context.Context,
 	log logger.Logger,
 	lockFileRelPath, lockFileAbsDir string,
 	exec bunWhyRunner,
+	whyLabels bool,
 ) []ecosystems.SCAResult {
 	// TargetFile for error results: the root package.json alongside bun.lock.
 	lockFileDir := filepath.Dir(lockFileRelPath)
@@ -141,7 +175,7 @@ func (p Plugin) buildResults(
 
 	log.Debug(ctx, "Parsed bun why output", logger.Attr(logFieldLockFile, lockFileRelPath), logger.Attr("packages", len(out.Graph)))
 
-	graphResults, err := buildDepGraphs(pkgJSON.Name, pkgJSON.Version, out)
+	graphResults, err := buildDepGraphs(pkgJSON.Name, pkgJSON.Version, out, whyLabels)
 	if err != nil {
 		return errResult(fmt.Errorf("building dep graphs: %w", err))
 	}
@@ -218,13 +252,7 @@ func (p Plugin) discoverLockFiles(
 			discovery.WithInclude(bunLockFile),
 			discovery.WithCommonExcludes(),
 		}
-
-		if len(options.Global.Exclude) > 0 {
-			findOpts = append(findOpts, discovery.WithExcludes(options.Global.Exclude...))
-		}
-		if len(options.Global.ExcludePaths) > 0 {
-			findOpts = append(findOpts, discovery.WithExcludes(options.Global.ExcludePaths...))
-		}
+		findOpts = append(findOpts, options.DiscoveryOptions()...)
 
 		files, err := discovery.FindFiles(ctx, dir, findOpts...)
 		if err != nil {
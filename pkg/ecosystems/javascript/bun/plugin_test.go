@@ -392,3 +392,28 @@ func TestPlugin_DiscoverLockFiles_HonorsExcludePaths(t *testing.T) {
 	assert.Contains(t, rels, "bun.lock")
 	assert.Contains(t, rels, "b/bun.lock")
 }
+
+// TestPlugin_DiscoverLockFiles_HonorsDetectionDepth locks in that the bun plugin passes
+// `opts.Global.DetectionDepth` to discovery, so --detection-depth finds the same
+// projects as it does in the legacy CLI.
+func TestPlugin_DiscoverLockFiles_HonorsDetectionDepth(t *testing.T) {
+	tmpDir := t.TempDir()
+	for _, rel := range []string{"bun.lock", "a/bun.lock", "a/b/bun.lock"} {
+		full := filepath.Join(tmpDir, rel)
+		require.NoError(t, os.MkdirAll(filepath.Dir(full), 0o755))
+		require.NoError(t, os.WriteFile(full, []byte(""), 0o600))
+	}
+
+	opts := ecosystems.NewPluginOptions().
+		WithAllProjects(true).
+		WithDetectionDepth(2)
+
+	got, err := Plugin{}.discoverLockFiles(t.Context(), tmpDir, opts)
+	require.NoError(t, err)
+
+	rels := make([]string, len(got))
+	for i, r := range got {
+		rels[i] = filepath.ToSlash(r.RelPath)
+	}
+	assert.ElementsMatch(t, []string{"bun.lock", "a/bun.lock"}, rels)
+}
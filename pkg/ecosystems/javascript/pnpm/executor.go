@@ -11,6 +11,8 @@ import (
 	"strings"
 
 	"golang.org/x/mod/semver"
+
+	"github.com/snyk/cli-extension-dep-graph/v2/pkg/ecosystems"
 )
 
 // errPnpmNotFound is returned when the pnpm binary is not in PATH.
@@ -53,7 +55,9 @@ func (e *pnpmCmdExecutor) Run(ctx context.Context, dir string) (io.ReadCloser, e
 		return nil, err
 	}
 
-	cmd := exec.CommandContext(ctx, resolved, "-r", "list", "--lockfile-only", "--json", "--depth", "Infinity")
+	cmdCtx, cancel := ecosystems.CommandContext(ctx, "pnpm")
+
+	cmd := exec.CommandContext(cmdCtx, resolved, "-r", "list", "--lockfile-only", "--json", "--depth", "Infinity")
 	cmd.Dir = dir
 	cmd.Env = append(cmd.Environ(), "NO_COLOR=1")
 
@@ -64,13 +68,15 @@ func (e *pnpmCmdExecutor) Run(ctx context.Context, dir string) (io.ReadCloser, e
 	cmd.Stdout = pw
 
 	if err := cmd.Start(); err != nil {
+		cancel()
 		pr.Close()
 		pw.Close()
 		return nil, fmt.Errorf("starting pnpm list: %w", err)
 	}
 
 	go func() {
-		if waitErr := cmd.Wait(); waitErr != nil {
+		defer cancel()
+		if waitErr := ecosystems.CommandError(cmdCtx, cmd.Wait()); waitErr != nil {
 			pw.CloseWithError(fmt.Errorf("pnpm list failed: %w\nstderr: %s", waitErr, stderr.String()))
 		} else {
 			pw.Close()
@@ -81,9 +87,12 @@ func (e *pnpmCmdExecutor) Run(ctx context.Context, dir string) (io.ReadCloser, e
 }
 
 func checkPnpmVersion(ctx context.Context, binary string) error {
-	out, err := exec.CommandContext(ctx, binary, "--version").Output()
+	cmdCtx, cancel := ecosystems.CommandContext(ctx, "pnpm")
+	defer cancel()
+
+	out, err := exec.CommandContext(cmdCtx, binary, "--version").Output()
 	if err != nil {
-		return fmt.Errorf("failed to get pnpm version: %w", err)
+		return fmt.Errorf("failed to get pnpm version: %w", ecosystems.CommandError(cmdCtx, err))
 	}
 
 	ver, err := parsePnpmVersion(strings.TrimSpace(string(out)))
@@ -31,7 +31,10 @@ type Plugin struct {
 	executor pnpmListRunner
 }
 
-var _ ecosystems.SCAPlugin = (*Plugin)(nil)
+var (
+	_ ecosystems.SCAPlugin       = (*Plugin)(nil)
+	_ ecosystems.ProjectDetector = (*Plugin)(nil)
+)
 
 func (p Plugin) GetName() string {
 	return PluginName
@@ -87,6 +90,44 @@ func (p Plugin) BuildDepGraphsFromDir(
 	return nil
 }
 
+// DetectProjects reports the projects BuildDepGraphsFromDir would resolve
+// without staging a Rush workspace or running pnpm: the Rush monorepo as a
+// whole, or one project per discovered pnpm-lock.yaml rooted at the
+// package.json next to it.
+func (p Plugin) DetectProjects(
+	ctx context.Context,
+	_ logger.Logger,
+	dir string,
+	options *ecosystems.SCAPluginOptions,
+	onProject ecosystems.OnDetectFunc,
+) error {
+	if fsys := os.DirFS(dir); isRushRoot(fsys) {
+		if _, err := rushProjectFolders(fsys); errors.Is(err, errRushNotPnpm) || errors.Is(err, errRushSubspaces) {
+			return nil
+		}
+		return onProject(ecosystems.DetectedProject{
+			TargetFile:   rushJSONFile,
+			ProjectType:  pkgManager,
+			ClaimedFiles: []string{rushJSONFile, filepath.FromSlash(rushLockfilePath)},
+		})
+	}
+
+	files, err := discoverLockFiles(ctx, dir, options)
+	if err != nil {
+		return err
+	}
+	for _, file := range files {
+		if err := onProject(ecosystems.DetectedProject{
+			TargetFile:   filepath.Join(filepath.Dir(file.RelPath), packageJSONFile),
+			ProjectType:  pkgManager,
+			ClaimedFiles: []string{file.RelPath},
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // collectTargets dispatches to one adapter per scan root. Rush is checked first
 // because a Rush root has no scannable pnpm-lock.yaml at the top.
 func collectTargets(
@@ -95,7 +136,7 @@ func collectTargets(
 	dir string,
 	options *ecosystems.SCAPluginOptions,
 ) ([]scanTarget, error) {
-	if isRushRoot(dir) {
+	if isRushRoot(os.DirFS(dir)) {
 		return rushTargets(ctx, log, dir)
 	}
 	return pnpmTargets(ctx, log, dir, options)
@@ -271,12 +312,7 @@ func discoverLockFiles(
 			discovery.WithInclude(pnpmLockFile),
 			discovery.WithCommonExcludes(),
 		}
-		if len(options.Global.Exclude) > 0 {
-			findOpts = append(findOpts, discovery.WithExcludes(options.Global.Exclude...))
-		}
-		if len(options.Global.ExcludePaths) > 0 {
-			findOpts = append(findOpts, discovery.WithExcludes(options.Global.ExcludePaths...))
-		}
+		findOpts = append(findOpts, options.DiscoveryOptions()...)
 		files, err := discovery.FindFiles(ctx, dir, findOpts...)
 		if err != nil {
 			return nil, fmt.Errorf("discovering pnpm-lock.yaml files: %w", err)
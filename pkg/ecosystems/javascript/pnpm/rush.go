@@ -5,7 +5,9 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io/fs"
 	"os"
+	"path"
 	"path/filepath"
 	"regexp"
 	"strings"
@@ -62,7 +64,8 @@ var (
 func rushTargets(ctx context.Context, log logger.Logger, rushDir string) ([]scanTarget, error) {
 	log.Info(ctx, "Building Rush + pnpm dependency graphs", logger.Attr(logFieldDir, rushDir))
 
-	folders, err := rushProjectFolders(rushDir)
+	fsys := os.DirFS(rushDir)
+	folders, err := rushProjectFolders(fsys)
 	if err != nil {
 		if errors.Is(err, errRushNotPnpm) || errors.Is(err, errRushSubspaces) {
 			log.Info(ctx, "Skipping Rush workspace", logger.Attr("reason", err.Error()))
@@ -71,7 +74,7 @@ func rushTargets(ctx context.Context, log logger.Logger, rushDir string) ([]scan
 		return []scanTarget{errTarget(rushJSONFile, fmt.Errorf("reading rush.json: %w", err))}, nil
 	}
 
-	runDir, scanRoot, skipped, cleanup, err := stageRushWorkspace(rushDir, folders)
+	runDir, scanRoot, skipped, cleanup, err := stageRushWorkspace(fsys, folders)
 	if err != nil {
 		return []scanTarget{errTarget(rushJSONFile, err)}, nil
 	}
@@ -94,11 +97,11 @@ func rushTargets(ctx context.Context, log logger.Logger, rushDir string) ([]scan
 	}}, nil
 }
 
-// rushSubspacesEnabled reports whether the subspaces.json file at path exists
-// and contains "subspacesEnabled": true. If the file is absent, unreadable, or
+// rushSubspacesEnabled reports whether the repo's subspaces.json exists and
+// contains "subspacesEnabled": true. If the file is absent, unreadable, or
 // does not contain the field, it returns false (the Rush default).
-func rushSubspacesEnabled(path string) bool {
-	data, err := os.ReadFile(path)
+func rushSubspacesEnabled(fsys fs.FS) bool {
+	data, err := fs.ReadFile(fsys, rushSubspacesConfig)
 	if err != nil {
 		return false
 	}
@@ -117,15 +120,16 @@ func stripJSONComments(data []byte) []byte {
 	return rushLineCommentRe.ReplaceAll(data, nil)
 }
 
-// isRushRoot reports whether dir contains a rush.json.
-func isRushRoot(dir string) bool {
-	return fileExists(filepath.Join(dir, rushJSONFile))
+// isRushRoot reports whether the root of fsys contains a rush.json.
+func isRushRoot(fsys fs.FS) bool {
+	info, err := fs.Stat(fsys, rushJSONFile)
+	return err == nil && !info.IsDir()
 }
 
 // rushProjectFolders parses the project folders out of rush.json, and validates
-// the repo is pnpm-backed and not using subspaces.
-func rushProjectFolders(rushDir string) ([]string, error) {
-	data, err := os.ReadFile(filepath.Join(rushDir, rushJSONFile))
+// the repo is pnpm-backed and not using subspaces. fsys is the Rush repo root.
+func rushProjectFolders(fsys fs.FS) ([]string, error) {
+	data, err := fs.ReadFile(fsys, rushJSONFile)
 	if err != nil {
 		return nil, fmt.Errorf("reading rush.json: %w", err)
 	}
@@ -133,7 +137,7 @@ func rushProjectFolders(rushDir string) ([]string, error) {
 	if !rushPnpmVersionRe.Match(data) {
 		return nil, errRushNotPnpm //nolint:wrapcheck // sentinel matched with errors.Is by the caller
 	}
-	if rushSubspacesEnabled(filepath.Join(rushDir, filepath.FromSlash(rushSubspacesConfig))) {
+	if rushSubspacesEnabled(fsys) {
 		return nil, errRushSubspaces //nolint:wrapcheck // sentinel matched with errors.Is by the caller
 	}
 
@@ -156,8 +160,11 @@ func rushProjectFolders(rushDir string) ([]string, error) {
 // (stale/renamed/decoupled) is skipped, not fatal — pnpm tolerates a member
 // present in the lockfile but absent from pnpm-workspace.yaml. Only a workspace
 // with zero readable projects is an error.
-func stageRushWorkspace(rushDir string, projectFolders []string) (runDir, scanRoot string, skipped []string, cleanup func(), err error) {
-	lockBytes, err := os.ReadFile(filepath.Join(rushDir, filepath.FromSlash(rushLockfilePath)))
+//
+// The repo is read through fsys, its root, so it need not be on disk; only the
+// staged tree is, because pnpm has to run in it.
+func stageRushWorkspace(fsys fs.FS, projectFolders []string) (runDir, scanRoot string, skipped []string, cleanup func(), err error) {
+	lockBytes, err := fs.ReadFile(fsys, rushLockfilePath)
 	if err != nil {
 		return "", "", nil, nil, fmt.Errorf("reading Rush lockfile: %w", err)
 	}
@@ -173,8 +180,7 @@ func stageRushWorkspace(rushDir string, projectFolders []string) (runDir, scanRo
 	// --lockfile-only reads manifests + the lockfile, never node_modules.
 	var stagedFolders []string
 	for _, pf := range projectFolders {
-		src := filepath.Join(rushDir, filepath.FromSlash(pf), packageJSONFile)
-		data, readErr := os.ReadFile(src)
+		data, readErr := fs.ReadFile(fsys, path.Join(pf, packageJSONFile))
 		if readErr != nil {
 			skipped = append(skipped, pf)
 			continue
@@ -192,7 +198,7 @@ func stageRushWorkspace(rushDir string, projectFolders []string) (runDir, scanRo
 	}
 	if len(stagedFolders) == 0 {
 		cleanup()
-		return "", "", nil, nil, fmt.Errorf("no Rush projects with a readable package.json found under %s", rushDir)
+		return "", "", nil, nil, errors.New("no Rush projects with a readable package.json found")
 	}
 
 	staged := filepath.Join(tmpRoot, filepath.FromSlash(rushImporterBase))
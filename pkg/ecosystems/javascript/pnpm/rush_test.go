@@ -3,12 +3,15 @@ package pnpm
 import (
 	"context"
 	"errors"
+	"io/fs"
 	"os"
 	"path/filepath"
 	"sort"
 	"strings"
 	"testing"
+	"testing/fstest"
 
+	"github.com/snyk/cli-extension-dep-graph/v2/pkg/ecosystems"
 	"github.com/snyk/cli-extension-dep-graph/v2/pkg/ecosystems/logger"
 )
 
@@ -82,13 +85,11 @@ func TestRushSubspacesEnabled(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			path := filepath.Join(t.TempDir(), "subspaces.json")
+			files := map[string]string{}
 			if tt.write {
-				if err := os.WriteFile(path, []byte(tt.content), 0o600); err != nil {
-					t.Fatalf("writing fixture: %v", err)
-				}
+				files[rushSubspacesConfig] = tt.content
 			}
-			if got := rushSubspacesEnabled(path); got != tt.want {
+			if got := rushSubspacesEnabled(rushFS(files)); got != tt.want {
 				t.Errorf("rushSubspacesEnabled() = %v, want %v", got, tt.want)
 			}
 		})
@@ -113,6 +114,16 @@ func writeRushRepo(t *testing.T, files map[string]string) string {
 	return root
 }
 
+// rushFS is writeRushRepo for the helpers that read the repo through an
+// fs.FS: an in-memory Rush repo with the same layout.
+func rushFS(files map[string]string) fstest.MapFS {
+	fsys := fstest.MapFS{}
+	for rel, content := range files {
+		fsys[rel] = &fstest.MapFile{Data: []byte(content)}
+	}
+	return fsys
+}
+
 const rushJSONPnpm = `{
   "pnpmVersion": "8.15.8",
   "projects": [
@@ -123,9 +134,9 @@ const rushJSONPnpm = `{
 
 func TestRushProjectFolders(t *testing.T) {
 	t.Run("pnpm-backed returns project folders", func(t *testing.T) {
-		root := writeRushRepo(t, map[string]string{rushJSONFile: rushJSONPnpm})
+		fsys := rushFS(map[string]string{rushJSONFile: rushJSONPnpm})
 
-		folders, err := rushProjectFolders(root)
+		folders, err := rushProjectFolders(fsys)
 		if err != nil {
 			t.Fatalf("unexpected error: %v", err)
 		}
@@ -137,31 +148,31 @@ func TestRushProjectFolders(t *testing.T) {
 	})
 
 	t.Run("npm-backed is errRushNotPnpm", func(t *testing.T) {
-		root := writeRushRepo(t, map[string]string{
+		fsys := rushFS(map[string]string{
 			rushJSONFile: `{ "npmVersion": "9.0.0", "projects": [{ "projectFolder": "apps/a" }] }`,
 		})
-		_, err := rushProjectFolders(root)
+		_, err := rushProjectFolders(fsys)
 		if !errors.Is(err, errRushNotPnpm) {
 			t.Errorf("err = %v, want errRushNotPnpm", err)
 		}
 	})
 
 	t.Run("commented-out pnpmVersion does not satisfy the pnpm gate", func(t *testing.T) {
-		root := writeRushRepo(t, map[string]string{
+		fsys := rushFS(map[string]string{
 			rushJSONFile: `{ /* "pnpmVersion": "7.0.0", */ "npmVersion": "9.0.0" }`,
 		})
-		_, err := rushProjectFolders(root)
+		_, err := rushProjectFolders(fsys)
 		if !errors.Is(err, errRushNotPnpm) {
 			t.Errorf("err = %v, want errRushNotPnpm (commented pnpmVersion must not count)", err)
 		}
 	})
 
 	t.Run("subspaces enabled is errRushSubspaces", func(t *testing.T) {
-		root := writeRushRepo(t, map[string]string{
+		fsys := rushFS(map[string]string{
 			rushJSONFile:        rushJSONPnpm,
 			rushSubspacesConfig: `{"subspacesEnabled": true}`,
 		})
-		_, err := rushProjectFolders(root)
+		_, err := rushProjectFolders(fsys)
 		if !errors.Is(err, errRushSubspaces) {
 			t.Errorf("err = %v, want errRushSubspaces", err)
 		}
@@ -171,11 +182,11 @@ func TestRushProjectFolders(t *testing.T) {
 	// subspacesEnabled:false must NOT skip the repo — the monorepo lockfile is
 	// still authoritative, so the projects resolve normally.
 	t.Run("subspaces present but disabled is scanned", func(t *testing.T) {
-		root := writeRushRepo(t, map[string]string{
+		fsys := rushFS(map[string]string{
 			rushJSONFile:        rushJSONPnpm,
 			rushSubspacesConfig: `{"subspacesEnabled": false, "subspaceNames": []}`,
 		})
-		folders, err := rushProjectFolders(root)
+		folders, err := rushProjectFolders(fsys)
 		if err != nil {
 			t.Fatalf("disabled subspaces must not error, got: %v", err)
 		}
@@ -185,7 +196,7 @@ func TestRushProjectFolders(t *testing.T) {
 	})
 
 	t.Run("missing rush.json errors", func(t *testing.T) {
-		_, err := rushProjectFolders(t.TempDir())
+		_, err := rushProjectFolders(fstest.MapFS{})
 		if err == nil {
 			t.Error("expected an error reading a missing rush.json")
 		}
@@ -196,11 +207,13 @@ func TestRushProjectFolders(t *testing.T) {
 }
 
 func TestIsRushRoot(t *testing.T) {
-	withRush := writeRushRepo(t, map[string]string{rushJSONFile: `{}`})
-	if !isRushRoot(withRush) {
+	if !isRushRoot(rushFS(map[string]string{rushJSONFile: `{}`})) {
 		t.Error("dir containing rush.json should be a Rush root")
 	}
-	if isRushRoot(t.TempDir()) {
+	if isRushRoot(fstest.MapFS{"rush.json/x": {}}) {
+		t.Error("a rush.json directory should not make a Rush root")
+	}
+	if isRushRoot(fstest.MapFS{}) {
 		t.Error("dir without rush.json should not be a Rush root")
 	}
 }
@@ -225,9 +238,9 @@ func stageRushFiles() map[string]string {
 }
 
 func TestStageRushWorkspace_HappyPath(t *testing.T) {
-	root := writeRushRepo(t, stageRushFiles())
+	fsys := rushFS(stageRushFiles())
 
-	runDir, scanRoot, skipped, cleanup, err := stageRushWorkspace(root, []string{"apps/app-a", "libs/lib-b"})
+	runDir, scanRoot, skipped, cleanup, err := stageRushWorkspace(fsys, []string{"apps/app-a", "libs/lib-b"})
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -275,9 +288,9 @@ func TestStageRushWorkspace_HappyPath(t *testing.T) {
 }
 
 func TestStageRushWorkspace_MissingProjectSkippedNotFatal(t *testing.T) {
-	root := writeRushRepo(t, stageRushFiles())
+	fsys := rushFS(stageRushFiles())
 
-	_, _, skipped, cleanup, err := stageRushWorkspace(root, []string{"apps/app-a", "libs/lib-b", "apps/ghost"})
+	_, _, skipped, cleanup, err := stageRushWorkspace(fsys, []string{"apps/app-a", "libs/lib-b", "apps/ghost"})
 	if err != nil {
 		t.Fatalf("a stale project folder must not be fatal, got: %v", err)
 	}
@@ -290,27 +303,27 @@ func TestStageRushWorkspace_MissingProjectSkippedNotFatal(t *testing.T) {
 
 // stageErr runs stageRushWorkspace for the failure-path tests, which only care
 // about the returned error (a failed stage returns a nil cleanup).
-func stageErr(root string, folders []string) error {
-	_, _, _, _, err := stageRushWorkspace(root, folders) //nolint:dogsled // error-path tests want only err
+func stageErr(fsys fs.FS, folders []string) error {
+	_, _, _, _, err := stageRushWorkspace(fsys, folders) //nolint:dogsled // error-path tests want only err
 	return err
 }
 
 func TestStageRushWorkspace_ZeroReadableProjectsIsError(t *testing.T) {
-	root := writeRushRepo(t, map[string]string{
+	fsys := rushFS(map[string]string{
 		rushJSONFile:     rushJSONPnpm,
 		rushLockfilePath: "lockfileVersion: '6.0'\n",
 	})
-	if err := stageErr(root, []string{"apps/app-a"}); err == nil {
+	if err := stageErr(fsys, []string{"apps/app-a"}); err == nil {
 		t.Error("a workspace with no readable package.json should error")
 	}
 }
 
 func TestStageRushWorkspace_MissingLockfileIsError(t *testing.T) {
-	root := writeRushRepo(t, map[string]string{
+	fsys := rushFS(map[string]string{
 		rushJSONFile:                    rushJSONPnpm,
 		"apps/app-a/" + packageJSONFile: `{"name":"@x/app-a","version":"1.0.0"}`,
 	})
-	if err := stageErr(root, []string{"apps/app-a"}); err == nil {
+	if err := stageErr(fsys, []string{"apps/app-a"}); err == nil {
 		t.Error("a missing Rush lockfile should error")
 	}
 }
@@ -416,3 +429,44 @@ func contains(xs []string, want string) bool {
 	}
 	return false
 }
+
+func TestDetectProjects_Rush(t *testing.T) {
+	t.Run("pnpm-backed repo is one project claiming the shared lockfile", func(t *testing.T) {
+		root := writeRushRepo(t, map[string]string{rushJSONFile: rushJSONPnpm})
+
+		var got []ecosystems.DetectedProject
+		err := Plugin{}.DetectProjects(context.Background(), logger.Nop(), root, ecosystems.NewPluginOptions(),
+			func(p ecosystems.DetectedProject) error {
+				got = append(got, p)
+				return nil
+			})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(got) != 1 {
+			t.Fatalf("projects = %v, want 1", got)
+		}
+		if got[0].TargetFile != rushJSONFile || got[0].ProjectType != pkgManager {
+			t.Errorf("project = %+v, want target %s of type %s", got[0], rushJSONFile, pkgManager)
+		}
+		want := []string{rushJSONFile, filepath.FromSlash(rushLockfilePath)}
+		if len(got[0].ClaimedFiles) != 2 || got[0].ClaimedFiles[0] != want[0] || got[0].ClaimedFiles[1] != want[1] {
+			t.Errorf("claimed = %v, want %v", got[0].ClaimedFiles, want)
+		}
+	})
+
+	t.Run("npm-backed repo is not detected", func(t *testing.T) {
+		root := writeRushRepo(t, map[string]string{
+			rushJSONFile: `{ "npmVersion": "9.0.0", "projects": [{ "projectFolder": "apps/a" }] }`,
+		})
+
+		err := Plugin{}.DetectProjects(context.Background(), logger.Nop(), root, ecosystems.NewPluginOptions(),
+			func(p ecosystems.DetectedProject) error {
+				t.Errorf("unexpected project %+v", p)
+				return nil
+			})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+}
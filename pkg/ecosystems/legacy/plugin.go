@@ -82,7 +82,8 @@ func (l *Resolver) BuildDepGraphsFromDir(
 // it carries the user's `--exclude-paths` (parsed onto opts at construction) plus any files earlier plugins
 // reported as processed. When non-empty, we write it onto the cloned config's FlagExcludePaths, overriding
 // whatever was already there: in every production path the user's `--exclude-paths` is also on opts, so the
-// live config's value is a subset of opts and the override is lossless.
+// live config's value is a subset of opts and the override is lossless. The legacy CLI takes paths relative
+// to the scanned directory, so the leading slash that anchors root files for discovery is dropped.
 func buildLegacyConfig(src configuration.Configuration, opts *ecosystems.SCAPluginOptions) configuration.Configuration {
 	cfg := src.Clone()
 
@@ -95,7 +96,11 @@ func buildLegacyConfig(src configuration.Configuration, opts *ecosystems.SCAPlug
 	}
 
 	if len(opts.Global.ExcludePaths) > 0 {
-		cfg.Set(workflow.FlagExcludePaths, strings.Join(opts.Global.ExcludePaths, ","))
+		excludePaths := make([]string, len(opts.Global.ExcludePaths))
+		for i, p := range opts.Global.ExcludePaths {
+			excludePaths[i] = strings.TrimPrefix(p, "/")
+		}
+		cfg.Set(workflow.FlagExcludePaths, strings.Join(excludePaths, ","))
 	}
 
 	return cfg
@@ -313,6 +313,12 @@ func TestPlugin_BuildLegacyConfig_WritesExcludePathsFromOpts(t *testing.T) {
 			optsExcludePaths:     []string{"user-supplied.lock", "processed1.lock", "processed2.lock"},
 			expectedExcludePaths: "user-supplied.lock,processed1.lock,processed2.lock",
 		},
+		{
+			name:                 "root files anchored for discovery are passed relative",
+			liveExcludePaths:     "",
+			optsExcludePaths:     []string{"/package-lock.json", "a/package-lock.json"},
+			expectedExcludePaths: "package-lock.json,a/package-lock.json",
+		},
 		{
 			name:                 "single processed file",
 			liveExcludePaths:     "",
@@ -4,8 +4,10 @@ import (
 	"fmt"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/snyk/cli-extension-dep-graph/v2/pkg/ecosystems/argparser"
+	"github.com/snyk/cli-extension-dep-graph/v2/pkg/ecosystems/discovery"
 )
 
 // SCAPluginOptions contains configuration options for SCA plugins,
@@ -18,6 +20,16 @@ type SCAPluginOptions struct {
 }
 
 // GlobalOptions contains options that apply globally across all SCA plugins.
+//
+// Exclude and ExcludePaths both keep files out of --all-projects discovery,
+// with the same patterns: one without a slash matches file and directory names
+// at any depth, and one with a slash, including a leading one as in
+// "/bun.lock", the path from the scanned directory. The registry adds the
+// files each plugin processed to ExcludePaths as such paths, so they exclude
+// only themselves. Both accept "**" and "!" negation. Exclude is applied
+// before ExcludePaths and the last matching pattern wins, so a negated
+// ExcludePaths entry brings back what Exclude left out; files inside an
+// excluded directory cannot be brought back, as it is not walked.
 type GlobalOptions struct {
 	TargetFile                    *string              `arg:"--target-file"`
 	AllProjects                   bool                 `arg:"--all-projects"`
@@ -30,8 +42,19 @@ type GlobalOptions struct {
 	ForceIncludeWorkspacePackages bool                 `arg:"--internal-uv-workspace-packages"`
 	ProjectName                   *string              `arg:"--project-name"`
 	IncludeProvenance             bool                 `arg:"--include-provenance"`
+	PruneRepeatedSubdependencies  bool                 `arg:"--prune-repeated-subdependencies,-p"`
+	StrictGraphValidation         bool                 `arg:"--strict-graph-validation"`
+	RespectGitignore              bool                 `arg:"--respect-gitignore"`
+	DetectionDepth                *int                 `arg:"--detection-depth"` // Directory levels --all-projects searches, the root being 1; nil means no limit.
 	WorkspacePackage              *string              `arg:"--workspace-package"`
+	OnlyPlugins                   CommaSeparatedString `arg:"--only-plugins"`
+	SkipPlugins                   CommaSeparatedString `arg:"--skip-plugins"`
+	PluginTimeout                 Duration             `arg:"--plugin-timeout"`  // Per plugin run; 0 means no limit.
+	ScanTimeout                   Duration             `arg:"--scan-timeout"`    // Per registry run; 0 means no limit.
+	CommandTimeout                Duration             `arg:"--command-timeout"` // Per external command a plugin runs; 0 means no limit.
+	WhyLabels                     bool                 // Set by the why workflow; plugins label nodes with why the package is there, e.g. Gradle resolution failures.
 	RawFlags                      []string
+	DiscoveryIndex                *discovery.Index // Set by the plugin registry per run; shared by every plugin's file discovery.
 }
 
 // CommaSeparatedString is a custom type that parses comma-separated values.
@@ -43,6 +66,23 @@ func (c *CommaSeparatedString) UnmarshalText(text []byte) error {
 	return nil
 }
 
+// Duration is a time.Duration parsed from flags with time.ParseDuration,
+// e.g. "90s" or "10m".
+type Duration time.Duration
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (d *Duration) UnmarshalText(text []byte) error {
+	parsed, err := time.ParseDuration(string(text))
+	if err != nil {
+		return fmt.Errorf("invalid duration %q: %w", text, err)
+	}
+	if parsed < 0 {
+		return fmt.Errorf("invalid duration %q: must not be negative", text)
+	}
+	*d = Duration(parsed)
+	return nil
+}
+
 // PythonOptions contains Python-specific options for dependency graph generation.
 type PythonOptions struct {
 	NoBuildIsolation bool `arg:"--no-build-isolation"`
@@ -178,6 +218,46 @@ func (o *SCAPluginOptions) WithWorkspacePackage(pkg string) *SCAPluginOptions {
 	return o
 }
 
+// WithOnlyPlugins restricts a registry run to the named plugins.
+func (o *SCAPluginOptions) WithOnlyPlugins(names []string) *SCAPluginOptions {
+	o.Global.OnlyPlugins = append(o.Global.OnlyPlugins, names...)
+	return o
+}
+
+// WithSkipPlugins excludes the named plugins from a registry run.
+func (o *SCAPluginOptions) WithSkipPlugins(names []string) *SCAPluginOptions {
+	o.Global.SkipPlugins = append(o.Global.SkipPlugins, names...)
+	return o
+}
+
+// WithPluginTimeout limits how long each plugin may run. 0 disables the limit.
+func (o *SCAPluginOptions) WithPluginTimeout(timeout time.Duration) *SCAPluginOptions {
+	o.Global.PluginTimeout = Duration(timeout)
+	return o
+}
+
+// WithScanTimeout limits how long a whole registry run may take. 0 disables
+// the limit.
+func (o *SCAPluginOptions) WithScanTimeout(timeout time.Duration) *SCAPluginOptions {
+	o.Global.ScanTimeout = Duration(timeout)
+	return o
+}
+
+// WithCommandTimeout limits how long each external command a plugin runs,
+// such as one Gradle or pnpm invocation, may take. 0 disables the limit.
+func (o *SCAPluginOptions) WithCommandTimeout(timeout time.Duration) *SCAPluginOptions {
+	o.Global.CommandTimeout = Duration(timeout)
+	return o
+}
+
+// WithWhyLabels has plugins label nodes with what they know about why a
+// package is in the graph, such as its dependents or why it failed to
+// resolve. Only the why workflow sets it, as the labels can be large.
+func (o *SCAPluginOptions) WithWhyLabels(whyLabels bool) *SCAPluginOptions {
+	o.Global.WhyLabels = whyLabels
+	return o
+}
+
 func (o *SCAPluginOptions) WithGradleConfigurationMatching(pattern string) *SCAPluginOptions {
 	o.Gradle.ConfigurationMatching = pattern
 	return o
@@ -218,6 +298,56 @@ func (o *SCAPluginOptions) WithIncludeProvenance(includeProvenance bool) *SCAPlu
 	return o
 }
 
+// WithPruneRepeatedSubdependencies sets whether every result's dep-graph has
+// its repeated sub-dependencies pruned. See PruneRepeatedSubdependencies.
+func (o *SCAPluginOptions) WithPruneRepeatedSubdependencies(prune bool) *SCAPluginOptions {
+	o.Global.PruneRepeatedSubdependencies = prune
+	return o
+}
+
+// WithStrictGraphValidation sets whether a dep-graph that fails
+// ValidateDepGraph turns its result into an error rather than a warning.
+func (o *SCAPluginOptions) WithStrictGraphValidation(strict bool) *SCAPluginOptions {
+	o.Global.StrictGraphValidation = strict
+	return o
+}
+
+// WithRespectGitignore sets whether --all-projects discovery skips the paths
+// git ignores.
+func (o *SCAPluginOptions) WithRespectGitignore(respect bool) *SCAPluginOptions {
+	o.Global.RespectGitignore = respect
+	return o
+}
+
+// WithDetectionDepth sets how many directory levels --all-projects discovery
+// searches. See DetectionDepth.
+func (o *SCAPluginOptions) WithDetectionDepth(depth int) *SCAPluginOptions {
+	o.Global.DetectionDepth = &depth
+	return o
+}
+
+// DiscoveryOptions returns the find options every plugin's --all-projects
+// discovery shares: the run's discovery index, the user's excludes,
+// RespectGitignore and DetectionDepth. Plugins add their own includes.
+func (o *SCAPluginOptions) DiscoveryOptions() []discovery.FindOption {
+	findOpts := []discovery.FindOption{
+		discovery.WithIndex(o.Global.DiscoveryIndex),
+	}
+	if len(o.Global.Exclude) > 0 {
+		findOpts = append(findOpts, discovery.WithExcludes(o.Global.Exclude...))
+	}
+	if len(o.Global.ExcludePaths) > 0 {
+		findOpts = append(findOpts, discovery.WithExcludePaths(o.Global.ExcludePaths...))
+	}
+	if o.Global.RespectGitignore {
+		findOpts = append(findOpts, discovery.WithGitignore())
+	}
+	if o.Global.DetectionDepth != nil {
+		findOpts = append(findOpts, discovery.WithMaxDepth(*o.Global.DetectionDepth))
+	}
+	return findOpts
+}
+
 // WithBazelJvm sets whether the Bazel JVM dep-graph scanner should run.
 func (o *SCAPluginOptions) WithBazelJvm(b bool) *SCAPluginOptions {
 	o.Bazel.Jvm = b
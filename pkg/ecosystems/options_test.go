@@ -1,13 +1,20 @@
 package ecosystems
 
 import (
+	"context"
 	"testing"
+	"testing/fstest"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/snyk/cli-extension-dep-graph/v2/pkg/ecosystems/discovery"
 )
 
 func TestNewPluginOptionsFromRawFlags_AllFields(t *testing.T) {
 	targetFile := "requirements.txt"
+	detectionDepth := 2
 
 	tests := []struct {
 		name     string
@@ -129,6 +136,54 @@ func TestNewPluginOptionsFromRawFlags_AllFields(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "only p",
+			rawFlags: []string{
+				"-p",
+			},
+			expected: &SCAPluginOptions{
+				Global: GlobalOptions{
+					PruneRepeatedSubdependencies: true,
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "only strict graph validation",
+			rawFlags: []string{
+				"--strict-graph-validation",
+			},
+			expected: &SCAPluginOptions{
+				Global: GlobalOptions{
+					StrictGraphValidation: true,
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "only respect gitignore",
+			rawFlags: []string{
+				"--respect-gitignore",
+			},
+			expected: &SCAPluginOptions{
+				Global: GlobalOptions{
+					RespectGitignore: true,
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "only detection depth",
+			rawFlags: []string{
+				"--detection-depth=2",
+			},
+			expected: &SCAPluginOptions{
+				Global: GlobalOptions{
+					DetectionDepth: &detectionDepth,
+				},
+			},
+			wantErr: false,
+		},
 		{
 			name: "only d",
 			rawFlags: []string{
@@ -254,6 +309,14 @@ func TestNewPluginOptionsFromRawFlags_FailFast(t *testing.T) {
 	assert.True(t, got.Global.FailFast)
 }
 
+func TestNewPluginOptionsFromRawFlags_PluginSelection(t *testing.T) {
+	got, err := NewPluginOptionsFromRawFlags([]string{"--only-plugins", "cargo,pnpm", "--skip-plugins=legacycli"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, CommaSeparatedString{"cargo", "pnpm"}, got.Global.OnlyPlugins)
+	assert.Equal(t, CommaSeparatedString{"legacycli"}, got.Global.SkipPlugins)
+}
+
 func TestNewPluginOptionsFromRawFlags_ForceSingleGraph(t *testing.T) {
 	got, err := NewPluginOptionsFromRawFlags([]string{"--force-single-graph"})
 
@@ -371,3 +434,53 @@ func TestWithIncludeProvenance(t *testing.T) {
 	options = NewPluginOptions().WithIncludeProvenance(false)
 	assert.False(t, options.Global.IncludeProvenance)
 }
+
+func TestDiscoveryOptions(t *testing.T) {
+	fsys := fstest.MapFS{
+		".git/HEAD":          {Data: []byte("ref: refs/heads/main\n")},
+		".gitignore":         {Data: []byte("ignored/\n")},
+		"uv.lock":            {},
+		"app/uv.lock":        {},
+		"app/nested/uv.lock": {},
+		"ignored/uv.lock":    {},
+		"fixtures/uv.lock":   {},
+		"vendor/uv.lock":     {},
+	}
+	find := func(options *SCAPluginOptions) []string {
+		findOpts := append([]discovery.FindOption{discovery.WithInclude("uv.lock")}, options.DiscoveryOptions()...)
+		files, err := discovery.FindFilesFS(context.Background(), fsys, ".", findOpts...)
+		require.NoError(t, err)
+		paths := make([]string, 0, len(files))
+		for _, f := range files {
+			paths = append(paths, f.RelPath)
+		}
+		return paths
+	}
+
+	assert.ElementsMatch(t,
+		[]string{"uv.lock", "app/uv.lock", "app/nested/uv.lock", "ignored/uv.lock", "fixtures/uv.lock", "vendor/uv.lock"},
+		find(NewPluginOptions()))
+
+	options := NewPluginOptions().
+		WithExclude([]string{"vendor"}).
+		WithExcludePaths([]string{"fixtures"}).
+		WithRespectGitignore(true).
+		WithDetectionDepth(2)
+	assert.ElementsMatch(t, []string{"uv.lock", "app/uv.lock"}, find(options))
+}
+
+func TestNewPluginOptionsFromRawFlags_Timeouts(t *testing.T) {
+	got, err := NewPluginOptionsFromRawFlags([]string{"--plugin-timeout", "90s", "--scan-timeout=10m", "--command-timeout=5m"})
+
+	assert.NoError(t, err)
+	assert.Equal(t, Duration(90*time.Second), got.Global.PluginTimeout)
+	assert.Equal(t, Duration(10*time.Minute), got.Global.ScanTimeout)
+	assert.Equal(t, Duration(5*time.Minute), got.Global.CommandTimeout)
+}
+
+func TestNewPluginOptionsFromRawFlags_InvalidTimeout(t *testing.T) {
+	for _, value := range []string{"ten minutes", "-1s"} {
+		_, err := NewPluginOptionsFromRawFlags([]string{"--plugin-timeout", value})
+		assert.Error(t, err, value)
+	}
+}
@@ -0,0 +1,110 @@
+package orchestrator
+
+import (
+	"context"
+	"path/filepath"
+
+	"github.com/snyk/cli-extension-dep-graph/v2/pkg/ecosystems"
+	"github.com/snyk/cli-extension-dep-graph/v2/pkg/ecosystems/logger"
+)
+
+// DetectionReport lists the projects each plugin would handle in a run, and
+// the files more than one plugin would claim.
+type DetectionReport struct {
+	Plugins  []PluginDetection `json:"plugins"`
+	Overlaps []Overlap         `json:"overlaps,omitempty"`
+}
+
+// PluginDetection describes what one plugin detected. Plugins that do not
+// implement ecosystems.ProjectDetector, such as the legacy CLI fallback, are
+// listed with Supported unset and no projects.
+type PluginDetection struct {
+	Name      string                       `json:"name"`
+	Supported bool                         `json:"supported"`
+	Projects  []ecosystems.DetectedProject `json:"projects,omitempty"`
+	Error     string                       `json:"error,omitempty"`
+}
+
+// Overlap is a file claimed by more than one plugin. Plugins lists the
+// claimants in scheduling order, so the first one is the plugin that would
+// handle the file in a real run.
+type Overlap struct {
+	File    string   `json:"file"`
+	Plugins []string `json:"plugins"`
+}
+
+// Detect asks every selected plugin, then the fallback, which projects it
+// would handle in dir, without building any dep-graphs. Every plugin sees the
+// caller's options unchanged, rather than the files claimed by the plugins
+// scheduled before it, so that overlapping claims show up in the report.
+//
+// A plugin whose detection fails is reported with its error and does not stop
+// the other plugins. Detect only returns an error if the run was cancelled.
+func (r *PluginRegistry) Detect(dir string, opts *ecosystems.SCAPluginOptions) (*DetectionReport, error) {
+	ctx := r.ictx.Context()
+	log := logger.NewFromZerolog(r.ictx.GetEnhancedLogger())
+	r = r.withSelection(selectionFromOptions(opts))
+	opts = withDiscoveryIndex(opts, dir, r.ictx.GetEnhancedLogger())
+
+	plugins := r.plugins
+	if r.fallback != nil {
+		plugins = append(plugins[:len(plugins):len(plugins)], r.fallback)
+	}
+
+	report := &DetectionReport{Plugins: make([]PluginDetection, 0, len(plugins))}
+	for _, plugin := range plugins {
+		if ctx.Err() != nil {
+			return report, context.Cause(ctx)
+		}
+
+		pd := PluginDetection{Name: plugin.GetName()}
+		detector, ok := plugin.(ecosystems.ProjectDetector)
+		if ok {
+			pd.Supported = true
+			err := detector.DetectProjects(ctx, log, dir, cloneOptions(opts), func(project ecosystems.DetectedProject) error {
+				pd.Projects = append(pd.Projects, project)
+				return nil
+			})
+			if err != nil {
+				if ctx.Err() != nil {
+					return report, context.Cause(ctx)
+				}
+				pd.Error = err.Error()
+			}
+		}
+		report.Plugins = append(report.Plugins, pd)
+	}
+
+	report.Overlaps = findOverlaps(report.Plugins)
+	return report, nil
+}
+
+// findOverlaps returns the files claimed by more than one plugin, in the order
+// they were first claimed.
+func findOverlaps(detections []PluginDetection) []Overlap {
+	claimants := make(map[string][]string)
+	var order []string
+	for _, pd := range detections {
+		for _, project := range pd.Projects {
+			for _, file := range project.ClaimedFiles {
+				file = filepath.Clean(file)
+				names := claimants[file]
+				if len(names) > 0 && names[len(names)-1] == pd.Name {
+					continue
+				}
+				if len(names) == 0 {
+					order = append(order, file)
+				}
+				claimants[file] = append(names, pd.Name)
+			}
+		}
+	}
+
+	var overlaps []Overlap
+	for _, file := range order {
+		if names := claimants[file]; len(names) > 1 {
+			overlaps = append(overlaps, Overlap{File: file, Plugins: names})
+		}
+	}
+	return overlaps
+}
@@ -0,0 +1,73 @@
+package orchestrator
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/snyk/cli-extension-dep-graph/v2/pkg/ecosystems"
+	"github.com/snyk/cli-extension-dep-graph/v2/pkg/ecosystems/logger"
+)
+
+// mockDetector is a mockPlugin that also implements ecosystems.ProjectDetector.
+type mockDetector struct {
+	mockPlugin
+	projects  []ecosystems.DetectedProject
+	detectErr error
+}
+
+func (m *mockDetector) DetectProjects(
+	_ context.Context,
+	_ logger.Logger,
+	_ string,
+	opts *ecosystems.SCAPluginOptions,
+	onProject ecosystems.OnDetectFunc,
+) error {
+	m.capturedExcludePaths = append([]string(nil), opts.Global.ExcludePaths...)
+	for _, project := range m.projects {
+		if err := onProject(project); err != nil {
+			return err
+		}
+	}
+	return m.detectErr
+}
+
+func TestPluginRegistry_Detect(t *testing.T) {
+	uvLike := &mockDetector{
+		mockPlugin: mockPlugin{name: "uv"},
+		projects: []ecosystems.DetectedProject{
+			{TargetFile: "pyproject.toml", ProjectType: "uv", ClaimedFiles: []string{"uv.lock", "requirements.txt"}},
+		},
+	}
+	pipLike := &mockDetector{
+		mockPlugin: mockPlugin{name: "pip"},
+		projects: []ecosystems.DetectedProject{
+			{TargetFile: "requirements.txt", ProjectType: "pip", ClaimedFiles: []string{"requirements.txt"}},
+			{TargetFile: "svc/requirements.txt", ProjectType: "pip", ClaimedFiles: []string{"svc/requirements.txt"}},
+		},
+	}
+	broken := &mockDetector{mockPlugin: mockPlugin{name: "broken"}, detectErr: errors.New("unreadable manifest")}
+	fallback := &mockPlugin{name: "fallback"}
+
+	r, err := NewPluginRegistry(setupMockInvocationContext(t), uvLike, pipLike, broken)
+	require.NoError(t, err)
+	r.fallback = fallback
+
+	report, err := r.Detect("/test/dir", ecosystems.NewPluginOptions().WithAllProjects(true))
+	require.NoError(t, err)
+
+	require.Len(t, report.Plugins, 4)
+	assert.Equal(t, PluginDetection{Name: "uv", Supported: true, Projects: uvLike.projects}, report.Plugins[0])
+	assert.Equal(t, PluginDetection{Name: "pip", Supported: true, Projects: pipLike.projects}, report.Plugins[1])
+	assert.Equal(t, PluginDetection{Name: "broken", Supported: true, Error: "unreadable manifest"}, report.Plugins[2])
+	assert.Equal(t, PluginDetection{Name: "fallback"}, report.Plugins[3])
+
+	assert.Equal(t, []Overlap{{File: "requirements.txt", Plugins: []string{"uv", "pip"}}}, report.Overlaps)
+
+	assert.Empty(t, pipLike.capturedExcludePaths, "detection must not hide earlier plugins' files")
+	assert.False(t, uvLike.called, "detection must not build dep-graphs")
+	assert.False(t, fallback.called, "detection must not run plugins without ProjectDetector")
+}
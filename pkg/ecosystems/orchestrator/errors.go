@@ -0,0 +1,84 @@
+package orchestrator
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/snyk/error-catalog-golang-public/snyk"
+	"github.com/snyk/error-catalog-golang-public/snyk_errors"
+
+	"github.com/snyk/cli-extension-dep-graph/v2/pkg/ecosystems"
+)
+
+// PluginError reports a plugin whose BuildDepGraphsFromDir returned an error,
+// as opposed to a per-project failure carried on SCAResult.Error.
+type PluginError struct {
+	PluginName string
+	// Dir is the directory the plugin was run against.
+	Dir string
+	Err error
+}
+
+func (e *PluginError) Error() string {
+	return fmt.Sprintf("%s plugin failed: %v", e.PluginName, e.Err)
+}
+
+func (e *PluginError) Unwrap() error {
+	return e.Err
+}
+
+// Result wraps the failure in an SCAResult attributed to the failing plugin,
+// so it can travel on the same stream as the other plugins' dep-graphs.
+func (e *PluginError) Result() ecosystems.SCAResult {
+	return ecosystems.SCAResult{
+		ResolverMetadata: &ecosystems.ResolverMetadata{PluginName: e.PluginName},
+		Error:            e,
+	}
+}
+
+// FailFastError is returned by Resolve when --fail-fast is set in
+// --all-projects mode and a plugin emits an errored result. The errored
+// result has already been passed to onGraph when the run is aborted.
+type FailFastError struct {
+	Result ecosystems.SCAResult
+}
+
+func (e *FailFastError) Error() string {
+	// A *PluginError result has no target file and already names the plugin.
+	if targetFile := targetFileOf(e.Result); targetFile != "" {
+		return fmt.Sprintf("aborting scan after %s failed: %v", targetFile, e.Result.Error)
+	}
+	return fmt.Sprintf("aborting scan: %v", e.Result.Error)
+}
+
+func (e *FailFastError) Unwrap() error {
+	return e.Result.Error
+}
+
+// TimeoutError is the context cause when --plugin-timeout or --scan-timeout
+// expires. Results it cuts short carry it as the cause of a catalog error; see
+// newTimeoutResultError.
+type TimeoutError struct {
+	// PluginName is empty when the scan budget, not a plugin's own limit,
+	// expired.
+	PluginName string
+	Timeout    time.Duration
+}
+
+func (e *TimeoutError) Error() string {
+	if e.PluginName == "" {
+		return fmt.Sprintf("scan did not finish within %s", e.Timeout)
+	}
+	return fmt.Sprintf("%s plugin did not finish within %s", e.PluginName, e.Timeout)
+}
+
+// newTimeoutResultError returns the catalog's generic timeout error for a
+// project, or a whole plugin run when targetFile is empty, that timeout cut
+// short. timeout is a *TimeoutError or an *ecosystems.CommandTimeoutError.
+func newTimeoutResultError(timeout error, targetFile string) error {
+	detail := timeout.Error()
+	if targetFile != "" {
+		detail = fmt.Sprintf("%s: %s", targetFile, detail)
+	}
+	return snyk.NewTimeoutError(detail, snyk_errors.WithCause(timeout))
+}
@@ -35,6 +35,21 @@ var FlagPnpmResolver = flag{
 	Value: "internal-pnpm-resolver",
 }
 
+var FlagPipResolver = flag{
+	Key:   "internal-pip-resolver",
+	Value: "internal-pip-resolver",
+}
+
+var FlagPipenvResolver = flag{
+	Key:   "internal-pipenv-resolver",
+	Value: "internal-pipenv-resolver",
+}
+
+var FlagUvResolver = flag{
+	Key:   "internal-uv-resolver",
+	Value: "internal-uv-resolver",
+}
+
 var allFlags = []flag{
 	FlagUnifiedTestAPIOsCLI,
 	FlagNewGradleResolver,
@@ -42,6 +57,9 @@ var allFlags = []flag{
 	FlagBunResolver,
 	FlagCargoResolver,
 	FlagPnpmResolver,
+	FlagPipResolver,
+	FlagPipenvResolver,
+	FlagUvResolver,
 }
 
 // GetAllFlags returns all feature flags as a map of key to flag name.
@@ -1,5 +1,14 @@
 package orchestrator
 
+import (
+	"fmt"
+
+	"github.com/snyk/go-application-framework/pkg/configuration"
+
+	internalworkflow "github.com/snyk/cli-extension-dep-graph/v2/internal/workflow"
+	"github.com/snyk/cli-extension-dep-graph/v2/pkg/ecosystems/resultcache"
+)
+
 type registerOpt func(*PluginRegistry, *pluginEntry)
 
 func withFeatureFlagCheck(flag flag) registerOpt {
@@ -8,6 +17,7 @@ func withFeatureFlagCheck(flag flag) registerOpt {
 			return
 		}
 		entry.skip = true
+		entry.skipReason = fmt.Sprintf("feature flag %s is disabled", flag.Key)
 	}
 }
 
@@ -16,3 +26,45 @@ func withPluginDependencies(deps ...string) registerOpt {
 		entry.dependencies = append(entry.dependencies, deps...)
 	}
 }
+
+// withEnabledByConfig registers the plugin when the given configuration key is
+// set, even if an earlier withFeatureFlagCheck would have skipped it.
+func withEnabledByConfig(key string) registerOpt {
+	return func(reg *PluginRegistry, entry *pluginEntry) {
+		if reg.ictx.GetConfiguration().GetBool(key) {
+			entry.skip = false
+			entry.skipReason = ""
+		}
+	}
+}
+
+// withResultCache serves the plugin's results from the registry's result
+// cache, if one is configured, when the files it would process are unchanged.
+// tool names the binary the plugin runs, or is empty if it runs none. Only
+// register plugins with it whose results depend on nothing but the files
+// their DetectProjects claims, the tool and the options.
+func withResultCache(tool string) registerOpt {
+	return func(reg *PluginRegistry, entry *pluginEntry) {
+		if reg.cache != nil {
+			entry.plugin = reg.cache.Wrap(entry.plugin, tool)
+		}
+	}
+}
+
+// bytesPerMB converts --result-cache-max-size to bytes.
+const bytesPerMB = 1 << 20
+
+// openResultCache opens the result cache in dir, emptying it first if
+// --clear-result-cache is set.
+func openResultCache(cfg configuration.Configuration, dir string) (*resultcache.Cache, error) {
+	cache, err := resultcache.New(dir, int64(cfg.GetInt(internalworkflow.FlagResultCacheMaxSize))*bytesPerMB)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open result cache: %w", err)
+	}
+	if cfg.GetBool(internalworkflow.FlagClearResultCache) {
+		if err := cache.Clear(); err != nil {
+			return nil, fmt.Errorf("failed to clear result cache: %w", err)
+		}
+	}
+	return cache, nil
+}
@@ -2,20 +2,36 @@ package orchestrator
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/rs/zerolog"
 	"github.com/snyk/go-application-framework/pkg/configuration"
 	"github.com/snyk/go-application-framework/pkg/workflow"
 
+	"github.com/snyk/cli-extension-dep-graph/v2/internal/remoteconv"
+	"github.com/snyk/cli-extension-dep-graph/v2/internal/snykclient"
+	internalworkflow "github.com/snyk/cli-extension-dep-graph/v2/internal/workflow"
 	"github.com/snyk/cli-extension-dep-graph/v2/pkg/ecosystems"
 	"github.com/snyk/cli-extension-dep-graph/v2/pkg/ecosystems/bazel"
+	"github.com/snyk/cli-extension-dep-graph/v2/pkg/ecosystems/discovery"
+	"github.com/snyk/cli-extension-dep-graph/v2/pkg/ecosystems/external"
 	"github.com/snyk/cli-extension-dep-graph/v2/pkg/ecosystems/gradle"
 	"github.com/snyk/cli-extension-dep-graph/v2/pkg/ecosystems/javascript/bun"
 	"github.com/snyk/cli-extension-dep-graph/v2/pkg/ecosystems/javascript/pnpm"
 	"github.com/snyk/cli-extension-dep-graph/v2/pkg/ecosystems/legacy"
 	"github.com/snyk/cli-extension-dep-graph/v2/pkg/ecosystems/logger"
+	"github.com/snyk/cli-extension-dep-graph/v2/pkg/ecosystems/python/pip"
+	"github.com/snyk/cli-extension-dep-graph/v2/pkg/ecosystems/python/pipenv"
+	"github.com/snyk/cli-extension-dep-graph/v2/pkg/ecosystems/python/uv"
+	"github.com/snyk/cli-extension-dep-graph/v2/pkg/ecosystems/resultcache"
 	"github.com/snyk/cli-extension-dep-graph/v2/pkg/ecosystems/rust/cargo"
+	"github.com/snyk/cli-extension-dep-graph/v2/pkg/identity"
 )
 
 // bazelPluginName is duplicated here because bazel.pluginName is unexported.
@@ -23,23 +39,68 @@ import (
 // bazel. Promote to an exported bazel.PluginName if a third caller appears.
 const bazelPluginName = "bazel"
 
+// remoteRepoURLKey is the configuration key the uv plugin reads the
+// repository URL from when converting SBOMs.
+const remoteRepoURLKey = "remote-repo-url"
+
 type pluginEntry struct {
 	plugin       ecosystems.SCAPlugin
 	dependencies []string
 	skip         bool
+	// skipReason explains skip in the run report.
+	skipReason string
 }
 
 type PluginRegistry struct {
 	ictx    workflow.InvocationContext
 	entries []pluginEntry
 	plugins []ecosystems.SCAPlugin
+	// skipped holds the entries register dropped, so every run can report them.
+	skipped []pluginEntry
+
+	// fallback runs after every registered plugin has finished, seeing all of
+	// their ProcessedFiles on ExcludePaths. nil disables the fallback.
+	fallback ecosystems.SCAPlugin
+	// serial forces plugins to run one at a time in registration order, each
+	// seeing the ProcessedFiles of every plugin before it, even in
+	// --all-projects mode.
+	serial bool
+	// selection is the plugin choice from --only-plugins and --skip-plugins in
+	// the configuration. Plugins it excludes are skipped at registration.
+	selection pluginSelection
+	// cache serves the results of plugins registered withResultCache. nil
+	// disables caching.
+	cache *resultcache.Cache
+}
+
+// pluginRun summarizes one plugin's execution.
+type pluginRun struct {
+	// files is the deduped union of ProcessedFiles across the plugin's results.
+	files []string
+	// excludesAdded is the subset of files that was not already on the
+	// plugin's ExcludePaths, i.e. what it newly hides from later plugins.
+	excludesAdded  []string
+	results        int
+	erroredResults int
+	duration       time.Duration
+	err            error
 }
 
 func NewDefaultPluginRegistry(ictx workflow.InvocationContext) (*PluginRegistry, error) {
+	cfg := ictx.GetConfiguration()
 	r := &PluginRegistry{
-		ictx:    ictx,
-		entries: make([]pluginEntry, 0),
-		plugins: make([]ecosystems.SCAPlugin, 0),
+		ictx:      ictx,
+		entries:   make([]pluginEntry, 0),
+		plugins:   make([]ecosystems.SCAPlugin, 0),
+		selection: selectionFromConfig(cfg),
+	}
+	r.setFallback(legacy.NewPlugin(ictx))
+	if dir := cfg.GetString(internalworkflow.FlagResultCacheDir); dir != "" {
+		cache, err := openResultCache(cfg, dir)
+		if err != nil {
+			return nil, err
+		}
+		r.cache = cache
 	}
 
 	// bazel, a dependency of every other plugin because it's a build tool that can build any other language.
@@ -47,14 +108,24 @@ func NewDefaultPluginRegistry(ictx workflow.InvocationContext) (*PluginRegistry,
 		return nil, fmt.Errorf("failed to register bazel plugin: %w", err)
 	}
 	// javascript
-	if err := r.register(bun.Plugin{}, withFeatureFlagCheck(FlagBunResolver), withPluginDependencies(bazelPluginName)); err != nil {
+	if err := r.register(
+		bun.Plugin{},
+		withFeatureFlagCheck(FlagBunResolver),
+		withPluginDependencies(bazelPluginName),
+		withResultCache("bun"),
+	); err != nil {
 		return nil, fmt.Errorf("failed to register bun plugin: %w", err)
 	}
-	if err := r.register(pnpm.Plugin{}, withFeatureFlagCheck(FlagPnpmResolver), withPluginDependencies(bazelPluginName)); err != nil {
+	if err := r.register(
+		pnpm.Plugin{},
+		withFeatureFlagCheck(FlagPnpmResolver),
+		withPluginDependencies(bazelPluginName),
+		withResultCache("pnpm"),
+	); err != nil {
 		return nil, fmt.Errorf("failed to register pnpm plugin: %w", err)
 	}
-	// gradle (opt-in via feature flag)
-	cfg := ictx.GetConfiguration()
+	// gradle (opt-in via feature flag). Not cached: its results depend on build
+	// scripts and repositories, not just files detection can claim.
 	normalizeDepsPostHook := gradle.NewNormalizeDepsPostHook(
 		ictx.GetNetworkAccess().GetHttpClient(),
 		cfg.GetString(configuration.API_URL),
@@ -65,53 +136,549 @@ func NewDefaultPluginRegistry(ictx workflow.InvocationContext) (*PluginRegistry,
 		return nil, fmt.Errorf("failed to register gradle plugin: %w", err)
 	}
 	// rust
-	if err := r.register(cargo.Plugin{}, withFeatureFlagCheck(FlagCargoResolver), withPluginDependencies(bazelPluginName)); err != nil {
+	if err := r.register(
+		cargo.Plugin{},
+		withFeatureFlagCheck(FlagCargoResolver),
+		withPluginDependencies(bazelPluginName),
+		withResultCache(cargo.PluginName),
+	); err != nil {
 		return nil, fmt.Errorf("failed to register cargo plugin: %w", err)
 	}
+	// python: within a directory uv.lock beats Pipfile.lock beats requirements.txt.
+	// Each plugin claims its sibling manifests in ProcessedFiles, so running it
+	// after the higher-precedence plugins is enough to skip what they handled.
+	snykClient := snykclient.NewSnykClient(
+		ictx.GetNetworkAccess().GetHttpClient(),
+		cfg.GetString(configuration.API_URL),
+		cfg.GetString(configuration.ORGANIZATION),
+	)
+	converter := remoteconv.NewRemoteSBOMConverter(snykClient, logger.NewFromZerolog(ictx.GetEnhancedLogger()))
+	uvPlugin := uv.NewPlugin(uv.NewClient(), converter, cfg.GetString(remoteRepoURLKey))
+	// uv has always backed --use-sbom-resolution, so that flag keeps it enabled without the feature flag.
+	// Not cached: its graphs come from the remote SBOM conversion and depend on
+	// --remote-repo-url, not just files detection can claim.
+	if err := r.register(
+		uvPlugin,
+		withFeatureFlagCheck(FlagUvResolver),
+		withEnabledByConfig(internalworkflow.FlagUseSBOMResolution),
+		withPluginDependencies(bazelPluginName),
+	); err != nil {
+		return nil, fmt.Errorf("failed to register uv plugin: %w", err)
+	}
+	// pipenv only reads Pipfile.lock, so there is no tool to key its cache on.
+	if err := r.register(
+		pipenv.Plugin{},
+		withFeatureFlagCheck(FlagPipenvResolver),
+		withPluginDependencies(bazelPluginName, uv.PluginName),
+		withResultCache(""),
+	); err != nil {
+		return nil, fmt.Errorf("failed to register pipenv plugin: %w", err)
+	}
+	// pip is not cached: requirements.txt is not a lockfile, so what it
+	// resolves to changes with the package index and the Python environment.
+	if err := r.register(
+		pip.Plugin{},
+		withFeatureFlagCheck(FlagPipResolver),
+		withPluginDependencies(bazelPluginName, uv.PluginName, pipenv.PluginName),
+	); err != nil {
+		return nil, fmt.Errorf("failed to register pip plugin: %w", err)
+	}
+	// external plugins, declared by manifests in a user-configured directory
+	if dir := cfg.GetString(internalworkflow.FlagExternalPluginsDir); dir != "" {
+		externalPlugins, err := external.LoadPlugins(dir)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load external plugins: %w", err)
+		}
+		for _, plugin := range externalPlugins {
+			// Names drive dependency ordering and plugin selection, so an
+			// external plugin must not shadow a built-in one.
+			if r.isKnown(plugin.GetName()) {
+				return nil, fmt.Errorf("external plugin %s clashes with an existing plugin name", plugin.GetName())
+			}
+			if err := r.register(plugin, withPluginDependencies(plugin.Dependencies()...)); err != nil {
+				return nil, fmt.Errorf("failed to register external %s plugin: %w", plugin.GetName(), err)
+			}
+		}
+	}
 
 	return r, nil
 }
 
+// NewPluginRegistry returns a registry that runs plugins strictly one after
+// another in the given order, with no feature flag checks and no legacy CLI
+// fallback. Include legacy.NewPlugin in plugins to get one. --only-plugins and
+// --skip-plugins still apply.
+func NewPluginRegistry(ictx workflow.InvocationContext, plugins ...ecosystems.SCAPlugin) (*PluginRegistry, error) {
+	r := &PluginRegistry{
+		ictx:      ictx,
+		entries:   make([]pluginEntry, 0, len(plugins)),
+		plugins:   make([]ecosystems.SCAPlugin, 0, len(plugins)),
+		serial:    true,
+		selection: selectionFromConfig(ictx.GetConfiguration()),
+	}
+	for _, plugin := range plugins {
+		if err := r.register(plugin); err != nil {
+			return nil, fmt.Errorf("failed to register %s plugin: %w", plugin.GetName(), err)
+		}
+	}
+	return r, nil
+}
+
+// ResolveDepgraphs runs every registered plugin against dir and streams their
+// results on the returned channel, followed by the fallback plugin. A plugin
+// that fails to run is reported as a result whose Error is a *PluginError, and
+// the remaining plugins still run. Plugins excluded by opts.Global.OnlyPlugins
+// or SkipPlugins do not run and are reported as skipped.
 func (r *PluginRegistry) ResolveDepgraphs(dir string, opts *ecosystems.SCAPluginOptions) <-chan ecosystems.SCAResult {
 	resultsChan := make(chan ecosystems.SCAResult)
 
-	// Clone opts so WithExcludePaths mutations during the loop don't reach into the
-	// caller's opts. Other Global fields are shallow-copied; only ExcludePaths is mutated
-	// here, so its backing slice is the only one we need to clone.
-	localOpts := *opts
-	localOpts.Global.ExcludePaths = append(ecosystems.CommaSeparatedString(nil), opts.Global.ExcludePaths...)
-	opts = &localOpts
-
 	go func() {
 		defer close(resultsChan)
 
 		ctx := r.ictx.Context()
 		enhancedLogger := r.ictx.GetEnhancedLogger()
 
-		for _, plugin := range r.plugins {
+		onGraph := func(result ecosystems.SCAResult) error {
 			select {
+			case resultsChan <- result:
+				return nil
 			case <-ctx.Done():
-				return
-			default:
+				return ctx.Err()
 			}
+		}
+		report, err := r.run(dir, opts, onGraph, false)
+		if err != nil {
+			enhancedLogger.Debug().Err(err).Msg("dependency resolution stopped early")
+		}
+		enhancedLogger.Debug().Interface("report", report).Msg("dependency resolution finished")
+	}()
 
-			files := executePluginWithResults(ctx, plugin, enhancedLogger, dir, opts, resultsChan)
-			if len(files) > 0 && !opts.Global.AllProjects {
-				return
+	return resultsChan
+}
+
+// Resolve runs every registered plugin against dir, followed by the fallback
+// plugin, and invokes onGraph for each result. onGraph follows the SCAPlugin
+// contract: calls are serialized and a non-nil return aborts the run, with
+// Resolve returning that error unchanged.
+//
+// Unlike ResolveDepgraphs, a plugin failing to run aborts the run and Resolve
+// returns a *PluginError. With --all-projects and --fail-fast, the first
+// errored result also aborts the run: Resolve then returns a *FailFastError.
+//
+// The returned RunReport is never nil, and covers the plugins considered up
+// to the point an aborted run stopped.
+func (r *PluginRegistry) Resolve(dir string, opts *ecosystems.SCAPluginOptions, onGraph ecosystems.OnGraphFunc) (*RunReport, error) {
+	return r.run(dir, opts, onGraph, true)
+}
+
+// run schedules the registered plugins and the fallback, minus any that
+// opts.Global.OnlyPlugins and SkipPlugins exclude. In --all-projects
+// mode plugins run concurrently: each one starts as soon as every plugin it
+// declared via withPluginDependencies has finished, and sees the
+// ProcessedFiles of those (transitive) dependencies on
+// opts.Global.ExcludePaths. Without --all-projects plugins run one at a time
+// in dependency order and the first plugin to emit results or claim files
+// wins; the fallback only runs if none did.
+//
+// A plugin whose BuildDepGraphsFromDir fails aborts the run when
+// abortOnPluginError is set; otherwise the failure is emitted as a result
+// carrying a *PluginError.
+//
+// opts.Global.PluginTimeout and ScanTimeout bound the plugins through their
+// contexts, and CommandTimeout each external command they run; see
+// ecosystems.CommandContext. Errored results a plugin emits once its time is
+// up or a command timed out, and its own error if it fails, are reported as
+// timeout results rather than aborting the run. Plugins that have not started when the scan budget runs out are not
+// started; a timeout result is reported for each project they would have
+// handled instead.
+func (r *PluginRegistry) run(
+	dir string,
+	opts *ecosystems.SCAPluginOptions,
+	onGraph ecosystems.OnGraphFunc,
+	abortOnPluginError bool,
+) (*RunReport, error) {
+	ctx, cancel := context.WithCancelCause(r.ictx.Context())
+	defer cancel(nil)
+
+	enhancedLogger := r.ictx.GetEnhancedLogger()
+
+	selection := selectionFromOptions(opts)
+	if unknown := r.unknownPluginNames(selection); len(unknown) > 0 {
+		enhancedLogger.Warn().Strs("plugins", unknown).Msg("ignoring unknown plugin names in plugin selection")
+	}
+	r = r.withSelection(selection)
+	failFast := opts.Global.FailFast && opts.Global.AllProjects
+	opts = withDiscoveryIndex(opts, dir, enhancedLogger)
+
+	// The scan budget bounds the plugins rather than the run, so the results
+	// reporting what it cut short can still be emitted.
+	budgetCtx := ctx
+	if scanTimeout := time.Duration(opts.Global.ScanTimeout); scanTimeout > 0 {
+		var cancelBudget context.CancelFunc
+		budgetCtx, cancelBudget = context.WithTimeoutCause(ctx, scanTimeout, &TimeoutError{Timeout: scanTimeout})
+		defer cancelBudget()
+	}
+
+	// Plugins may run concurrently; emitMu serializes their calls into onGraph
+	// so the SCAPlugin contract holds across the whole run.
+	var emitMu sync.Mutex
+	emit := func(result ecosystems.SCAResult) error {
+		emitMu.Lock()
+		defer emitMu.Unlock()
+
+		if ctx.Err() != nil {
+			return context.Cause(ctx)
+		}
+		if err := onGraph(result); err != nil {
+			cancel(err)
+			return err
+		}
+		if failFast && result.Error != nil {
+			err := &FailFastError{Result: result}
+			cancel(err)
+			return err
+		}
+		return nil
+	}
+
+	execute := func(plugin ecosystems.SCAPlugin, pluginOpts *ecosystems.SCAPluginOptions) pluginRun {
+		if timeout := timeoutCause(budgetCtx); timeout != nil {
+			reportNotStarted(ctx, plugin, enhancedLogger, dir, pluginOpts, timeout, emit)
+			return pluginRun{err: timeout}
+		}
+		pluginCtx, cancelPlugin := withPluginTimeout(budgetCtx, plugin.GetName(), time.Duration(opts.Global.PluginTimeout))
+		defer cancelPlugin()
+		pluginCtx = ecosystems.ContextWithCommandTimeout(pluginCtx, time.Duration(opts.Global.CommandTimeout))
+
+		var timedOut *TimeoutError
+		run := executePluginWithResults(pluginCtx, plugin, enhancedLogger, dir, pluginOpts, func(result ecosystems.SCAResult) error {
+			if result.Error != nil {
+				var commandTimeout *ecosystems.CommandTimeoutError
+				if timeout := timeoutCause(pluginCtx); timeout != nil {
+					timedOut = timeout
+					result.Error = newTimeoutResultError(timeout, targetFileOf(result))
+				} else if errors.As(result.Error, &commandTimeout) {
+					result.Error = newTimeoutResultError(commandTimeout, targetFileOf(result))
+				}
 			}
-			opts.WithExcludePaths(files)
+			return emit(result)
+		})
+		if run.err != nil && ctx.Err() == nil {
+			if timeout := timeoutCause(pluginCtx); timeout != nil {
+				timedOut = timeout
+				_ = emit(timeoutPluginResult(plugin.GetName(), dir, timeout))
+			}
+		}
+		if timedOut != nil {
+			run.err = timedOut
+			return run
 		}
 
-		select {
-		case <-ctx.Done():
+		// A plugin returning after the run was aborted is only reporting the
+		// abort back to us; it did not fail on its own.
+		if run.err != nil && ctx.Err() == nil {
+			failure := &PluginError{PluginName: plugin.GetName(), Dir: dir, Err: run.err}
+			var commandTimeout *ecosystems.CommandTimeoutError
+			if errors.As(run.err, &commandTimeout) {
+				// Like the plugin's own timeout, this is reported rather than
+				// aborting the run.
+				failure.Err = newTimeoutResultError(commandTimeout, "")
+				_ = emit(failure.Result())
+			} else if abortOnPluginError {
+				cancel(failure)
+			} else {
+				// emit cancels the run itself if the failure should stop it.
+				_ = emit(failure.Result())
+			}
+		}
+		return run
+	}
+
+	var (
+		runs    []*pluginRun
+		handled bool
+	)
+	if opts.Global.AllProjects && !r.serial {
+		runs = r.runConcurrently(ctx, opts, execute)
+	} else {
+		runs, handled = r.runSerially(ctx, opts, execute, !opts.Global.AllProjects)
+	}
+
+	var fallbackRun *pluginRun
+	if !handled && r.fallback != nil && ctx.Err() == nil {
+		fallbackOpts := cloneOptions(opts)
+		fallbackOpts.WithExcludePaths(anchorFiles(claimedFiles(runs)))
+		run := execute(r.fallback, fallbackOpts)
+		fallbackRun = &run
+	}
+
+	report := r.newRunReport(runs, fallbackRun, handled)
+	if ctx.Err() != nil {
+		return report, context.Cause(ctx)
+	}
+	return report, nil
+}
+
+// reportNotStarted emits a timeout result for each project plugin would have
+// handled, had the scan budget not run out before it started. A plugin that
+// cannot list its projects without running, or fails to, is reported by a
+// single result for the whole plugin.
+func reportNotStarted(
+	ctx context.Context,
+	plugin ecosystems.SCAPlugin,
+	enhancedLogger *zerolog.Logger,
+	dir string,
+	opts *ecosystems.SCAPluginOptions,
+	timeout *TimeoutError,
+	emit ecosystems.OnGraphFunc,
+) {
+	if detector, ok := plugin.(ecosystems.ProjectDetector); ok {
+		var projects []ecosystems.DetectedProject
+		err := detector.DetectProjects(ctx, logger.NewFromZerolog(enhancedLogger), dir, opts, func(project ecosystems.DetectedProject) error {
+			projects = append(projects, project)
+			return nil
+		})
+		if err == nil {
+			for _, project := range projects {
+				if emit(timeoutProjectResult(plugin.GetName(), project, timeout)) != nil {
+					return
+				}
+			}
 			return
-		default:
 		}
+		enhancedLogger.Debug().Err(err).Str("plugin", plugin.GetName()).Msg("could not list the projects of a plugin the scan budget stopped")
+	}
+	_ = emit(timeoutPluginResult(plugin.GetName(), dir, timeout))
+}
 
-		executePluginWithResults(ctx, legacy.NewPlugin(r.ictx), enhancedLogger, dir, opts, resultsChan)
-	}()
+// timeoutPluginResult reports a whole plugin run that timeout cut short, or
+// kept from starting.
+func timeoutPluginResult(pluginName, dir string, timeout *TimeoutError) ecosystems.SCAResult {
+	failure := &PluginError{PluginName: pluginName, Dir: dir, Err: newTimeoutResultError(timeout, "")}
+	return failure.Result()
+}
 
-	return resultsChan
+// timeoutProjectResult reports a detected project that timeout kept its plugin
+// from resolving.
+func timeoutProjectResult(pluginName string, project ecosystems.DetectedProject, timeout *TimeoutError) ecosystems.SCAResult {
+	targetFile := project.TargetFile
+	return ecosystems.SCAResult{
+		ProjectDescriptor: identity.ProjectDescriptor{
+			Identity: identity.ProjectIdentity{
+				ProjectType: project.ProjectType,
+				TargetFile:  &targetFile,
+			},
+		},
+		ResolverMetadata: &ecosystems.ResolverMetadata{
+			PluginName:           pluginName,
+			NormalisedTargetFile: targetFile,
+		},
+		Error: newTimeoutResultError(timeout, targetFile),
+	}
+}
+
+// withPluginTimeout derives the context a plugin runs with, which ends with a
+// *TimeoutError cause once timeout elapses. A zero timeout adds no limit.
+func withPluginTimeout(ctx context.Context, name string, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeoutCause(ctx, timeout, &TimeoutError{PluginName: name, Timeout: timeout})
+}
+
+// timeoutCause returns the *TimeoutError that ended ctx, or nil if ctx is
+// still live or ended for another reason.
+func timeoutCause(ctx context.Context) *TimeoutError {
+	var timeout *TimeoutError
+	if ctx.Err() != nil && errors.As(context.Cause(ctx), &timeout) {
+		return timeout
+	}
+	return nil
+}
+
+// claimedFiles concatenates the ProcessedFiles of every plugin that ran.
+func claimedFiles(runs []*pluginRun) []string {
+	var files []string
+	for _, run := range runs {
+		if run != nil {
+			files = append(files, run.files...)
+		}
+	}
+	return files
+}
+
+// anchorFiles returns files, paths relative to the scanned directory, as
+// ExcludePaths entries that match only those files: a file in the scanned
+// directory itself gets a leading slash, as "bun.lock" alone would exclude
+// every file of that name.
+func anchorFiles(files []string) []string {
+	anchored := make([]string, len(files))
+	for i, file := range files {
+		anchored[i] = anchorFile(file)
+	}
+	return anchored
+}
+
+func anchorFile(file string) string {
+	if strings.Contains(filepath.ToSlash(file), "/") {
+		return file
+	}
+	return "/" + file
+}
+
+// runSerially executes the registered plugins one at a time in registration
+// order, each seeing the ProcessedFiles of every plugin before it. When
+// stopAfterFirst is set it returns as soon as a plugin claims a file or
+// resolves a project, reporting handled. A plugin whose results all failed
+// without claiming files does not stop later plugins or the fallback from
+// resolving the project. The returned runs are indexed like
+// r.plugins, with nil for plugins that never ran.
+func (r *PluginRegistry) runSerially(
+	ctx context.Context,
+	opts *ecosystems.SCAPluginOptions,
+	execute func(ecosystems.SCAPlugin, *ecosystems.SCAPluginOptions) pluginRun,
+	stopAfterFirst bool,
+) (runs []*pluginRun, handled bool) {
+	runs = make([]*pluginRun, len(r.plugins))
+	var files []string
+	for i, plugin := range r.plugins {
+		if ctx.Err() != nil {
+			return runs, false
+		}
+
+		pluginOpts := cloneOptions(opts)
+		pluginOpts.WithExcludePaths(anchorFiles(files))
+
+		run := execute(plugin, pluginOpts)
+		runs[i] = &run
+		files = append(files, run.files...)
+		if stopAfterFirst && (len(run.files) > 0 || run.results > run.erroredResults) {
+			return runs, true
+		}
+	}
+	return runs, false
+}
+
+// runConcurrently executes every registered plugin in its own goroutine, gating
+// each on the completion of its declared dependencies. The returned runs are
+// indexed like r.plugins, with nil for plugins that never ran.
+func (r *PluginRegistry) runConcurrently(
+	ctx context.Context,
+	opts *ecosystems.SCAPluginOptions,
+	execute func(ecosystems.SCAPlugin, *ecosystems.SCAPluginOptions) pluginRun,
+) []*pluginRun {
+	ancestors := r.transitiveDependencies()
+
+	done := make(map[string]chan struct{}, len(r.plugins))
+	for _, plugin := range r.plugins {
+		done[plugin.GetName()] = make(chan struct{})
+	}
+
+	var (
+		mu        sync.Mutex
+		processed = make(map[string][]string, len(r.plugins))
+		runs      = make([]*pluginRun, len(r.plugins))
+		wg        sync.WaitGroup
+	)
+
+	for i, plugin := range r.plugins {
+		name := plugin.GetName()
+		wg.Go(func() {
+			defer close(done[name])
+
+			for _, dep := range ancestors[name] {
+				select {
+				case <-done[dep]:
+				case <-ctx.Done():
+					return
+				}
+			}
+			if ctx.Err() != nil {
+				return
+			}
+
+			pluginOpts := cloneOptions(opts)
+			mu.Lock()
+			for _, dep := range ancestors[name] {
+				pluginOpts.WithExcludePaths(anchorFiles(processed[dep]))
+			}
+			mu.Unlock()
+
+			run := execute(plugin, pluginOpts)
+
+			mu.Lock()
+			processed[name] = run.files
+			runs[i] = &run
+			mu.Unlock()
+		})
+	}
+	wg.Wait()
+
+	return runs
+}
+
+// transitiveDependencies maps each registered plugin to every registered plugin
+// it depends on, directly or indirectly, in registration (topological) order.
+// Dependencies on plugins that were never registered are dropped.
+func (r *PluginRegistry) transitiveDependencies() map[string][]string {
+	direct := make(map[string][]string, len(r.entries))
+	for _, entry := range r.entries {
+		direct[entry.plugin.GetName()] = entry.dependencies
+	}
+
+	result := make(map[string][]string, len(r.plugins))
+	for _, plugin := range r.plugins {
+		name := plugin.GetName()
+		reachable := make(map[string]struct{})
+		for _, dep := range direct[name] {
+			// r.plugins is topologically sorted, so every dependency's
+			// ancestors have already been computed.
+			deps, registered := result[dep]
+			if !registered {
+				continue
+			}
+			reachable[dep] = struct{}{}
+			for _, d := range deps {
+				reachable[d] = struct{}{}
+			}
+		}
+
+		ordered := make([]string, 0, len(reachable))
+		for _, p := range r.plugins {
+			if _, ok := reachable[p.GetName()]; ok {
+				ordered = append(ordered, p.GetName())
+			}
+		}
+		result[name] = ordered
+	}
+	return result
+}
+
+// withDiscoveryIndex returns a copy of opts whose plugins share one
+// discovery.Index of dir, so that the tree is listed once per run rather than
+// once per plugin. Without an index each plugin walks the tree itself.
+func withDiscoveryIndex(opts *ecosystems.SCAPluginOptions, dir string, log *zerolog.Logger) *ecosystems.SCAPluginOptions {
+	if opts.Global.DiscoveryIndex != nil {
+		return opts
+	}
+	idx, err := discovery.NewIndex(dir)
+	if err != nil {
+		log.Warn().Err(err).Msg("failed to create discovery index, plugins will walk the directory separately")
+		return opts
+	}
+	indexed := cloneOptions(opts)
+	indexed.Global.DiscoveryIndex = idx
+	return indexed
+}
+
+// cloneOptions returns a copy of opts whose ExcludePaths can be appended to
+// without affecting the original. Other Global fields are shallow-copied; only
+// ExcludePaths is mutated by the registry, so its backing slice is the only one
+// we need to clone.
+func cloneOptions(opts *ecosystems.SCAPluginOptions) *ecosystems.SCAPluginOptions {
+	localOpts := *opts
+	localOpts.Global.ExcludePaths = append(ecosystems.CommaSeparatedString(nil), opts.Global.ExcludePaths...)
+	return &localOpts
 }
 
 func (r *PluginRegistry) register(plugin ecosystems.SCAPlugin, opts ...registerOpt) error {
@@ -120,7 +687,14 @@ func (r *PluginRegistry) register(plugin ecosystems.SCAPlugin, opts ...registerO
 		opt(r, &entry)
 	}
 
+	if !entry.skip {
+		if reason := r.selection.skipReason(plugin.GetName()); reason != "" {
+			entry.skip = true
+			entry.skipReason = reason
+		}
+	}
 	if entry.skip {
+		r.skipped = append(r.skipped, entry)
 		return nil
 	}
 
@@ -133,6 +707,30 @@ func (r *PluginRegistry) register(plugin ecosystems.SCAPlugin, opts ...registerO
 	return nil
 }
 
+// isKnown reports whether name is already taken by a registered or skipped
+// plugin, or by the fallback.
+func (r *PluginRegistry) isKnown(name string) bool {
+	if r.fallback != nil && r.fallback.GetName() == name {
+		return true
+	}
+	for _, entry := range slices.Concat(r.entries, r.skipped) {
+		if entry.plugin.GetName() == name {
+			return true
+		}
+	}
+	return false
+}
+
+// setFallback installs plugin as the fallback, unless the selection excludes
+// it, in which case it is reported as skipped and no fallback runs.
+func (r *PluginRegistry) setFallback(plugin ecosystems.SCAPlugin) {
+	if reason := r.selection.skipReason(plugin.GetName()); reason != "" {
+		r.skipped = append(r.skipped, pluginEntry{plugin: plugin, skip: true, skipReason: reason})
+		return
+	}
+	r.fallback = plugin
+}
+
 func (r *PluginRegistry) sortPlugins() []ecosystems.SCAPlugin {
 	if len(r.entries) == 0 {
 		return nil
@@ -193,36 +791,94 @@ func executePluginWithResults(
 	enhancedLogger *zerolog.Logger,
 	dir string,
 	opts *ecosystems.SCAPluginOptions,
-	resultsChan chan ecosystems.SCAResult,
-) []string {
+	emit ecosystems.OnGraphFunc,
+) pluginRun {
 	enhancedLogger.Info().Msg(fmt.Sprintf("Executing %s plugin", plugin.GetName()))
 
 	// processedFiles is the deduped union across every emitted result.
 	// Plugins attach per-result file lists on SCAResult.ProcessedFiles;
 	// the orchestrator unions them here so callers see one flat list
 	// per plugin run.
+	var run pluginRun
 	seen := make(map[string]struct{})
-	var processedFiles []string
+	alreadyExcluded := make(map[string]struct{}, len(opts.Global.ExcludePaths))
+	for _, p := range opts.Global.ExcludePaths {
+		alreadyExcluded[p] = struct{}{}
+	}
 
-	err := plugin.BuildDepGraphsFromDir(ctx, logger.NewFromZerolog(enhancedLogger), dir, opts, func(result ecosystems.SCAResult) error {
+	start := time.Now()
+	run.err = plugin.BuildDepGraphsFromDir(ctx, logger.NewFromZerolog(enhancedLogger), dir, opts, func(result ecosystems.SCAResult) error {
 		for _, p := range result.ProcessedFiles {
 			if _, ok := seen[p]; ok {
 				continue
 			}
 			seen[p] = struct{}{}
-			processedFiles = append(processedFiles, p)
+			run.files = append(run.files, p)
+			if _, ok := alreadyExcluded[anchorFile(p)]; !ok {
+				run.excludesAdded = append(run.excludesAdded, p)
+			}
 		}
-		select {
-		case resultsChan <- result:
-			return nil
-		case <-ctx.Done():
-			return ctx.Err()
+		checkDepGraph(opts, &result)
+
+		run.results++
+		if result.Error != nil {
+			run.erroredResults++
 		}
+
+		// Attribute the result to the plugin that produced it so consumers
+		// can tell results apart without knowing how they were scheduled.
+		if result.ResolverMetadata == nil || result.ResolverMetadata.PluginName == "" {
+			var metadata ecosystems.ResolverMetadata
+			if result.ResolverMetadata != nil {
+				metadata = *result.ResolverMetadata
+			}
+			metadata.PluginName = plugin.GetName()
+			result.ResolverMetadata = &metadata
+		}
+
+		return emit(result)
 	})
-	if err != nil {
-		enhancedLogger.Warn().Err(err).Msg(fmt.Sprintf("%s plugin failed", plugin.GetName()))
-		return processedFiles
+	run.duration = time.Since(start)
+
+	return run
+}
+
+// checkDepGraph prunes the dep-graph of result if opts ask for it, then
+// validates it. Validation findings become warnings, or in strict mode the
+// result's error in place of the dep-graph.
+func checkDepGraph(opts *ecosystems.SCAPluginOptions, result *ecosystems.SCAResult) {
+	if result.DepGraph == nil {
+		return
+	}
+	if opts.Global.PruneRepeatedSubdependencies {
+		pruned, err := ecosystems.PruneRepeatedSubdependencies(result.DepGraph)
+		if err != nil {
+			result.DepGraph = nil
+			result.Error = errors.Join(result.Error, fmt.Errorf("failed to prune repeated sub-dependencies: %w", err))
+			return
+		}
+		result.DepGraph = pruned
 	}
 
-	return processedFiles
+	issues := ecosystems.ValidateDepGraph(result.DepGraph)
+	if len(issues) == 0 {
+		return
+	}
+	if opts.Global.StrictGraphValidation {
+		result.DepGraph = nil
+		result.Error = errors.Join(result.Error, &ecosystems.GraphValidationError{Issues: issues})
+		return
+	}
+	for _, issue := range issues {
+		result.Warnings = append(result.Warnings, issue.String())
+	}
+}
+
+// targetFileOf returns the best available name for the file a result was
+// built from.
+func targetFileOf(result ecosystems.SCAResult) string {
+	if result.ResolverMetadata != nil && result.ResolverMetadata.NormalisedTargetFile != "" {
+		return result.ResolverMetadata.NormalisedTargetFile
+	}
+	return result.ProjectDescriptor.GetTargetFile()
 }
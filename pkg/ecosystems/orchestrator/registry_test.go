@@ -2,11 +2,18 @@ package orchestrator
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"net/http"
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/golang/mock/gomock"
 	"github.com/rs/zerolog"
+	"github.com/snyk/dep-graph/go/pkg/depgraph"
+	"github.com/snyk/error-catalog-golang-public/snyk_errors"
 	"github.com/snyk/go-application-framework/pkg/configuration"
 	gafmocks "github.com/snyk/go-application-framework/pkg/mocks"
 	"github.com/snyk/go-application-framework/pkg/workflow"
@@ -14,13 +21,17 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/snyk/cli-extension-dep-graph/v2/pkg/ecosystems"
+	"github.com/snyk/cli-extension-dep-graph/v2/pkg/ecosystems/discovery"
 	"github.com/snyk/cli-extension-dep-graph/v2/pkg/ecosystems/logger"
+	"github.com/snyk/cli-extension-dep-graph/v2/pkg/ecosystems/resultcache"
 	"github.com/snyk/cli-extension-dep-graph/v2/pkg/identity"
 )
 
 type mockPlugin struct {
 	name    string
 	results []ecosystems.SCAResult
+	err     error
+	called  bool
 
 	// capturedExclude / capturedExcludePaths snapshot opts.Global.{Exclude,ExcludePaths}
 	// at the moment BuildDepGraphsFromDir is called, so tests can assert on what each
@@ -28,6 +39,7 @@ type mockPlugin struct {
 	// keeps mutating.
 	capturedExclude      []string
 	capturedExcludePaths []string
+	capturedIndex        *discovery.Index
 }
 
 func (m *mockPlugin) GetName() string {
@@ -41,9 +53,14 @@ func (m *mockPlugin) BuildDepGraphsFromDir(
 	opts *ecosystems.SCAPluginOptions,
 	onGraph ecosystems.OnGraphFunc,
 ) error {
+	m.called = true
 	if opts != nil {
 		m.capturedExclude = append([]string(nil), opts.Global.Exclude...)
 		m.capturedExcludePaths = append([]string(nil), opts.Global.ExcludePaths...)
+		m.capturedIndex = opts.Global.DiscoveryIndex
+	}
+	if m.err != nil {
+		return m.err
 	}
 	for _, r := range m.results {
 		if err := onGraph(r); err != nil {
@@ -122,12 +139,15 @@ func TestPluginRegistry_DefaultRegistryOrder_WithFeatureFlags(t *testing.T) {
 		cfg.Set(FlagPnpmResolver.Key, true)
 		cfg.Set(FlagNewGradleResolver.Key, true)
 		cfg.Set(FlagCargoResolver.Key, true)
+		cfg.Set(FlagUvResolver.Key, true)
+		cfg.Set(FlagPipenvResolver.Key, true)
+		cfg.Set(FlagPipResolver.Key, true)
 	})
 	r, err := NewDefaultPluginRegistry(ictx)
 	require.NoError(t, err)
 
-	require.Len(t, r.plugins, 5)
-	expectedOrder := []string{"bazel", "bun", "pnpm", "gradle", "cargo"}
+	require.Len(t, r.plugins, 8)
+	expectedOrder := []string{"bazel", "bun", "pnpm", "gradle", "cargo", "uv", "pipenv", "pip"}
 	for i, plugin := range r.plugins {
 		assert.Equal(t, expectedOrder[i], plugin.GetName())
 	}
@@ -150,12 +170,30 @@ func TestPluginRegistry_DefaultRegistryHasNoCircularDependencies_WithFeatureFlag
 		cfg.Set(FlagPnpmResolver.Key, true)
 		cfg.Set(FlagNewGradleResolver.Key, true)
 		cfg.Set(FlagCargoResolver.Key, true)
+		cfg.Set(FlagUvResolver.Key, true)
+		cfg.Set(FlagPipenvResolver.Key, true)
+		cfg.Set(FlagPipResolver.Key, true)
 	})
 	r, err := NewDefaultPluginRegistry(ictx)
 	require.NoError(t, err, "NewDefaultPluginRegistry should not return error for valid dependency graph")
 	assert.NotNil(t, r)
 	assert.NotNil(t, r.plugins, "plugins should be successfully sorted without circular dependencies")
-	assert.Len(t, r.plugins, 5, "all 5 plugins should be registered")
+	assert.Len(t, r.plugins, 8, "all 8 plugins should be registered")
+}
+
+func TestPluginRegistry_PythonPluginPrecedence(t *testing.T) {
+	ictx := setupMockInvocationContextWithConfig(t, func(cfg configuration.Configuration) {
+		cfg.Set(FlagUvResolver.Key, true)
+		cfg.Set(FlagPipenvResolver.Key, true)
+		cfg.Set(FlagPipResolver.Key, true)
+	})
+	r, err := NewDefaultPluginRegistry(ictx)
+	require.NoError(t, err)
+
+	ancestors := r.transitiveDependencies()
+	assert.Empty(t, ancestors["uv"])
+	assert.Equal(t, []string{"uv"}, ancestors["pipenv"])
+	assert.Equal(t, []string{"uv", "pipenv"}, ancestors["pip"])
 }
 
 func TestPluginRegistry_CircularDependencyReturnsError(t *testing.T) {
@@ -325,11 +363,65 @@ func TestPluginRegistry_ResolveDepgraphs_ContinuesWhenNoResults(t *testing.T) {
 	assert.Equal(t, "type-b", results[0].ProjectDescriptor.Identity.ProjectType)
 }
 
+// TestPluginRegistry_ResolveDepgraphs_ContinuesAfterUnclaimedFailure locks in that
+// a plugin whose only result failed without claiming files does not stop the
+// next plugin, or the fallback, from resolving the project.
+func TestPluginRegistry_ResolveDepgraphs_ContinuesAfterUnclaimedFailure(t *testing.T) {
+	r := &PluginRegistry{
+		ictx:    setupMockInvocationContext(t),
+		entries: make([]pluginEntry, 0),
+		plugins: make([]ecosystems.SCAPlugin, 0),
+	}
+	fallback := &mockPlugin{name: "fallback"}
+	r.setFallback(fallback)
+
+	require.NoError(t, r.register(&mockPlugin{
+		name: "plugin-a",
+		results: []ecosystems.SCAResult{{
+			ProjectDescriptor: identity.ProjectDescriptor{Identity: identity.ProjectIdentity{ProjectType: "type-a"}},
+			Error:             errors.New("resolution failed"),
+		}},
+	}))
+	require.NoError(t, r.register(&mockPlugin{
+		name: "plugin-b",
+		results: []ecosystems.SCAResult{{
+			ProjectDescriptor: identity.ProjectDescriptor{Identity: identity.ProjectIdentity{ProjectType: "type-b"}},
+		}},
+	}))
+
+	opts := ecosystems.NewPluginOptions()
+	opts.Global.AllProjects = false
+
+	results := collectResults(r.ResolveDepgraphs("/test/dir", opts))
+
+	require.Len(t, results, 2)
+	assert.Equal(t, "type-a", results[0].ProjectDescriptor.Identity.ProjectType)
+	assert.Equal(t, "type-b", results[1].ProjectDescriptor.Identity.ProjectType)
+	assert.False(t, fallback.called, "a resolved project stops before the fallback")
+
+	onlyFailing := &PluginRegistry{
+		ictx:    setupMockInvocationContext(t),
+		entries: make([]pluginEntry, 0),
+		plugins: make([]ecosystems.SCAPlugin, 0),
+	}
+	fallback = &mockPlugin{name: "fallback"}
+	onlyFailing.setFallback(fallback)
+	require.NoError(t, onlyFailing.register(&mockPlugin{
+		name:    "plugin-a",
+		results: []ecosystems.SCAResult{{Error: errors.New("resolution failed")}},
+	}))
+
+	collectResults(onlyFailing.ResolveDepgraphs("/test/dir", opts))
+
+	assert.True(t, fallback.called, "the fallback resolves the project the failing plugin did not claim")
+}
+
 // TestPluginRegistry_ResolveDepgraphs_PropagatesProcessedFilesAsExcludePaths locks in
-// that after a plugin returns ProcessedFiles, every subsequent plugin in the chain sees
+// that after a plugin returns ProcessedFiles, every plugin depending on it sees
 // those paths on `opts.Global.ExcludePaths` so they can skip already-handled files.
 // Processed files are exact paths, not basename patterns, so they belong on the
-// ExcludePaths channel rather than the basename-matching Exclude channel.
+// ExcludePaths channel rather than the basename-matching Exclude channel, and
+// files in the scanned directory itself are anchored with a leading slash.
 func TestPluginRegistry_ResolveDepgraphs_PropagatesProcessedFilesAsExcludePaths(t *testing.T) {
 	r := &PluginRegistry{
 		ictx:    setupMockInvocationContext(t),
@@ -341,7 +433,7 @@ func TestPluginRegistry_ResolveDepgraphs_PropagatesProcessedFilesAsExcludePaths(
 		name: "plugin-a",
 		results: []ecosystems.SCAResult{{
 			ProjectDescriptor: identity.ProjectDescriptor{Identity: identity.ProjectIdentity{ProjectType: "type-a"}},
-			ProcessedFiles:    []string{"a/lock.json", "a/sub/lock.json"},
+			ProcessedFiles:    []string{"lock.json", "a/lock.json", "a/sub/lock.json"},
 		}},
 	}
 	pluginB := &mockPlugin{
@@ -352,7 +444,7 @@ func TestPluginRegistry_ResolveDepgraphs_PropagatesProcessedFilesAsExcludePaths(
 		}},
 	}
 	require.NoError(t, r.register(pluginA))
-	require.NoError(t, r.register(pluginB))
+	require.NoError(t, r.register(pluginB, withPluginDependencies("plugin-a")))
 
 	opts := ecosystems.NewPluginOptions()
 	opts.Global.AllProjects = true
@@ -360,8 +452,8 @@ func TestPluginRegistry_ResolveDepgraphs_PropagatesProcessedFilesAsExcludePaths(
 	collectResults(r.ResolveDepgraphs("/test/dir", opts))
 
 	assert.Empty(t, pluginA.capturedExcludePaths,
-		"first plugin sees no ExcludePaths because no plugin has run before it")
-	assert.Equal(t, []string{"a/lock.json", "a/sub/lock.json"}, pluginB.capturedExcludePaths,
+		"first plugin sees no ExcludePaths because it has no dependencies")
+	assert.Equal(t, []string{"/lock.json", "a/lock.json", "a/sub/lock.json"}, pluginB.capturedExcludePaths,
 		"second plugin must see the first plugin's ProcessedFiles on opts.Global.ExcludePaths")
 	assert.Empty(t, pluginA.capturedExclude,
 		"processed files must NOT leak onto opts.Global.Exclude — that channel is for basename patterns only")
@@ -369,6 +461,824 @@ func TestPluginRegistry_ResolveDepgraphs_PropagatesProcessedFilesAsExcludePaths(
 		"processed files must NOT leak onto opts.Global.Exclude — that channel is for basename patterns only")
 }
 
+// blockingPlugin waits on release before emitting its results, and closes
+// started when BuildDepGraphsFromDir is entered.
+type blockingPlugin struct {
+	mockPlugin
+	started chan struct{}
+	release chan struct{}
+}
+
+func (b *blockingPlugin) BuildDepGraphsFromDir(
+	ctx context.Context,
+	log logger.Logger,
+	dir string,
+	opts *ecosystems.SCAPluginOptions,
+	onGraph ecosystems.OnGraphFunc,
+) error {
+	close(b.started)
+	<-b.release
+	return b.mockPlugin.BuildDepGraphsFromDir(ctx, log, dir, opts, onGraph)
+}
+
+func TestPluginRegistry_ResolveDepgraphs_RunsIndependentPluginsConcurrently(t *testing.T) {
+	r := &PluginRegistry{
+		ictx:    setupMockInvocationContext(t),
+		entries: make([]pluginEntry, 0),
+		plugins: make([]ecosystems.SCAPlugin, 0),
+	}
+
+	pluginA := &blockingPlugin{
+		mockPlugin: mockPlugin{name: "plugin-a"},
+		started:    make(chan struct{}),
+		release:    make(chan struct{}),
+	}
+	pluginB := &blockingPlugin{
+		mockPlugin: mockPlugin{name: "plugin-b"},
+		started:    make(chan struct{}),
+		release:    make(chan struct{}),
+	}
+	require.NoError(t, r.register(pluginA))
+	require.NoError(t, r.register(pluginB))
+
+	opts := ecosystems.NewPluginOptions()
+	opts.Global.AllProjects = true
+
+	resultsChan := r.ResolveDepgraphs("/test/dir", opts)
+
+	// plugin-b must start while plugin-a is still blocked.
+	<-pluginA.started
+	<-pluginB.started
+	close(pluginA.release)
+	close(pluginB.release)
+
+	assert.Empty(t, collectResults(resultsChan))
+}
+
+func TestPluginRegistry_ResolveDepgraphs_DependentWaitsForDependency(t *testing.T) {
+	r := &PluginRegistry{
+		ictx:    setupMockInvocationContext(t),
+		entries: make([]pluginEntry, 0),
+		plugins: make([]ecosystems.SCAPlugin, 0),
+	}
+
+	pluginA := &blockingPlugin{
+		mockPlugin: mockPlugin{
+			name: "plugin-a",
+			results: []ecosystems.SCAResult{{
+				ProjectDescriptor: identity.ProjectDescriptor{Identity: identity.ProjectIdentity{ProjectType: "type-a"}},
+				ProcessedFiles:    []string{"a/lock.json"},
+			}},
+		},
+		started: make(chan struct{}),
+		release: make(chan struct{}),
+	}
+	pluginB := &mockPlugin{name: "plugin-b"}
+	pluginC := &mockPlugin{name: "plugin-c"}
+	require.NoError(t, r.register(pluginA))
+	require.NoError(t, r.register(pluginB, withPluginDependencies("plugin-a")))
+	require.NoError(t, r.register(pluginC, withPluginDependencies("plugin-b")))
+
+	opts := ecosystems.NewPluginOptions()
+	opts.Global.AllProjects = true
+	opts.Global.ExcludePaths = []string{"user/excluded"}
+
+	resultsChan := r.ResolveDepgraphs("/test/dir", opts)
+	<-pluginA.started
+	close(pluginA.release)
+
+	results := collectResults(resultsChan)
+
+	require.Len(t, results, 1)
+	assert.Equal(t, []string{"user/excluded", "a/lock.json"}, pluginB.capturedExcludePaths)
+	assert.Equal(t, []string{"user/excluded", "a/lock.json"}, pluginC.capturedExcludePaths,
+		"transitive dependents must see ProcessedFiles of every plugin upstream of them")
+	assert.Equal(t, []string{"user/excluded"}, []string(opts.Global.ExcludePaths),
+		"caller's opts must not be mutated")
+}
+
+func TestPluginRegistry_DefaultRegistry_UseSBOMResolutionEnablesUv(t *testing.T) {
+	ictx := setupMockInvocationContextWithConfig(t, func(cfg configuration.Configuration) {
+		cfg.Set("use-sbom-resolution", true)
+	})
+	r, err := NewDefaultPluginRegistry(ictx)
+	require.NoError(t, err)
+
+	require.Len(t, r.plugins, 1)
+	assert.Equal(t, "uv", r.plugins[0].GetName())
+	require.NotNil(t, r.fallback)
+	assert.Equal(t, "legacycli", r.fallback.GetName())
+}
+
+func TestPluginRegistry_DefaultRegistry_SkipPluginsDisablesFallback(t *testing.T) {
+	ictx := setupMockInvocationContextWithConfig(t, func(cfg configuration.Configuration) {
+		cfg.Set("use-sbom-resolution", true)
+		cfg.Set("skip-plugins", "legacycli")
+	})
+	r, err := NewDefaultPluginRegistry(ictx)
+	require.NoError(t, err)
+
+	assert.Nil(t, r.fallback)
+	report, err := r.Resolve("/test/dir", ecosystems.NewPluginOptions(), func(ecosystems.SCAResult) error { return nil })
+	require.NoError(t, err)
+	pr, ok := report.Plugin("legacycli")
+	require.True(t, ok)
+	assert.Equal(t, PluginStatusSkipped, pr.Status)
+	assert.Equal(t, "listed in --skip-plugins", pr.Reason)
+}
+
+func TestPluginRegistry_Register_OnlyPluginsFromConfig(t *testing.T) {
+	ictx := setupMockInvocationContextWithConfig(t, func(cfg configuration.Configuration) {
+		cfg.Set("only-plugins", "plugin-b, plugin-c")
+	})
+	r, err := NewPluginRegistry(ictx, &mockPlugin{name: "plugin-a"}, &mockPlugin{name: "plugin-b"})
+	require.NoError(t, err)
+
+	require.Len(t, r.plugins, 1)
+	assert.Equal(t, "plugin-b", r.plugins[0].GetName())
+	require.Len(t, r.skipped, 1)
+	assert.Equal(t, "not listed in --only-plugins", r.skipped[0].skipReason)
+}
+
+func TestPluginRegistry_Resolve_SharesOneDiscoveryIndex(t *testing.T) {
+	pluginA := &mockPlugin{name: "plugin-a"}
+	pluginB := &mockPlugin{name: "plugin-b"}
+	r, err := NewPluginRegistry(setupMockInvocationContext(t), pluginA, pluginB)
+	require.NoError(t, err)
+
+	opts := ecosystems.NewPluginOptions().WithAllProjects(true)
+	_, err = r.Resolve(t.TempDir(), opts, func(ecosystems.SCAResult) error { return nil })
+	require.NoError(t, err)
+
+	require.NotNil(t, pluginA.capturedIndex)
+	assert.Same(t, pluginA.capturedIndex, pluginB.capturedIndex, "every plugin of a run must query the same index")
+	assert.Nil(t, opts.Global.DiscoveryIndex, "caller's opts must not be mutated")
+
+	first := pluginA.capturedIndex
+	_, err = r.Resolve(t.TempDir(), opts, func(ecosystems.SCAResult) error { return nil })
+	require.NoError(t, err)
+	assert.NotSame(t, first, pluginA.capturedIndex, "each run must list the tree afresh")
+}
+
+func TestPluginRegistry_Resolve_PluginSelectionFromOptions(t *testing.T) {
+	pluginA := &mockPlugin{name: "plugin-a", results: []ecosystems.SCAResult{{ProcessedFiles: []string{"a/lock.json"}}}}
+	pluginB := &mockPlugin{name: "plugin-b", results: []ecosystems.SCAResult{{ProcessedFiles: []string{"b/lock.json"}}}}
+	fallback := &mockPlugin{name: "fallback"}
+	r, err := NewPluginRegistry(setupMockInvocationContext(t), pluginA, pluginB)
+	require.NoError(t, err)
+	r.fallback = fallback
+
+	opts := ecosystems.NewPluginOptions().
+		WithAllProjects(true).
+		WithOnlyPlugins([]string{"plugin-b", "fallback"}).
+		WithSkipPlugins([]string{"fallback"})
+	report, err := r.Resolve("/test/dir", opts, func(ecosystems.SCAResult) error { return nil })
+	require.NoError(t, err)
+
+	assert.False(t, pluginA.called)
+	assert.True(t, pluginB.called)
+	assert.False(t, fallback.called)
+	assert.Empty(t, pluginB.capturedExcludePaths, "an excluded plugin claims no files")
+
+	prA, ok := report.Plugin("plugin-a")
+	require.True(t, ok)
+	assert.Equal(t, PluginReport{Name: "plugin-a", Status: PluginStatusSkipped, Reason: "not listed in --only-plugins"}, prA)
+	prFallback, ok := report.Plugin("fallback")
+	require.True(t, ok)
+	assert.Equal(t, PluginReport{Name: "fallback", Status: PluginStatusSkipped, Reason: "listed in --skip-plugins"}, prFallback)
+
+	// The selection only applies to the run it was passed to.
+	require.Len(t, r.plugins, 2)
+	assert.NotNil(t, r.fallback)
+}
+
+func TestPluginRegistry_DefaultRegistry_RegistersExternalPlugins(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "buck.json"),
+		[]byte(`{"name": "buck", "executable": "buck-resolver", "dependencies": ["uv"]}`), 0o600))
+	ictx := setupMockInvocationContextWithConfig(t, func(cfg configuration.Configuration) {
+		cfg.Set("use-sbom-resolution", true)
+		cfg.Set("external-plugins-dir", dir)
+	})
+
+	r, err := NewDefaultPluginRegistry(ictx)
+	require.NoError(t, err)
+
+	require.Len(t, r.plugins, 2)
+	assert.Equal(t, "uv", r.plugins[0].GetName())
+	assert.Equal(t, "buck", r.plugins[1].GetName())
+	assert.Equal(t, []string{"uv"}, r.transitiveDependencies()["buck"])
+}
+
+func TestPluginRegistry_DefaultRegistry_ExternalPluginNameClash(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "pnpm.json"),
+		[]byte(`{"name": "pnpm", "executable": "my-pnpm"}`), 0o600))
+	ictx := setupMockInvocationContextWithConfig(t, func(cfg configuration.Configuration) {
+		cfg.Set("external-plugins-dir", dir)
+	})
+
+	_, err := NewDefaultPluginRegistry(ictx)
+	assert.ErrorContains(t, err, "external plugin pnpm clashes with an existing plugin name")
+}
+
+func TestPluginRegistry_Resolve_StampsPluginName(t *testing.T) {
+	r, err := NewPluginRegistry(setupMockInvocationContext(t),
+		&mockPlugin{name: "plugin-a", results: []ecosystems.SCAResult{{}}},
+		&mockPlugin{name: "plugin-b", results: []ecosystems.SCAResult{{
+			ResolverMetadata: &ecosystems.ResolverMetadata{PluginName: "custom", NormalisedTargetFile: "b/lock.json"},
+		}}},
+	)
+	require.NoError(t, err)
+
+	opts := ecosystems.NewPluginOptions().WithAllProjects(true)
+
+	var results []ecosystems.SCAResult
+	_, err = r.Resolve("/test/dir", opts, func(result ecosystems.SCAResult) error {
+		results = append(results, result)
+		return nil
+	})
+	require.NoError(t, err)
+
+	require.Len(t, results, 2)
+	assert.Equal(t, "plugin-a", results[0].ResolverMetadata.PluginName)
+	assert.Equal(t, "custom", results[1].ResolverMetadata.PluginName, "a name set by the plugin is kept")
+}
+
+func TestPluginRegistry_Resolve_PrunesRepeatedSubdependencies(t *testing.T) {
+	builder, err := depgraph.NewBuilder(&depgraph.PkgManager{Name: "npm"}, &depgraph.PkgInfo{Name: "root", Version: "1.0.0"})
+	require.NoError(t, err)
+	builder.AddNode("a@1.0.0", &depgraph.PkgInfo{Name: "a", Version: "1.0.0"})
+	builder.AddNode("b@1.0.0", &depgraph.PkgInfo{Name: "b", Version: "1.0.0"})
+	builder.AddNode("c@1.0.0", &depgraph.PkgInfo{Name: "c", Version: "1.0.0"})
+	require.NoError(t, builder.ConnectNodes(builder.GetRootNode().NodeID, "a@1.0.0"))
+	require.NoError(t, builder.ConnectNodes(builder.GetRootNode().NodeID, "b@1.0.0"))
+	require.NoError(t, builder.ConnectNodes("a@1.0.0", "c@1.0.0"))
+	require.NoError(t, builder.ConnectNodes("b@1.0.0", "a@1.0.0"))
+
+	r, err := NewPluginRegistry(setupMockInvocationContext(t),
+		&mockPlugin{name: "plugin-a", results: []ecosystems.SCAResult{{DepGraph: builder.Build()}}},
+	)
+	require.NoError(t, err)
+
+	opts := ecosystems.NewPluginOptions().WithPruneRepeatedSubdependencies(true)
+
+	var results []ecosystems.SCAResult
+	_, err = r.Resolve("/test/dir", opts, func(result ecosystems.SCAResult) error {
+		results = append(results, result)
+		return nil
+	})
+	require.NoError(t, err)
+
+	require.Len(t, results, 1)
+	require.NoError(t, results[0].Error)
+	var prunedLeaf *depgraph.Node
+	for i := range results[0].DepGraph.Graph.Nodes {
+		if results[0].DepGraph.Graph.Nodes[i].NodeID == "a@1.0.0:pruned" {
+			prunedLeaf = &results[0].DepGraph.Graph.Nodes[i]
+		}
+	}
+	require.NotNil(t, prunedLeaf, "the repeated a@1.0.0 under b is replaced by a pruned leaf")
+	assert.Empty(t, prunedLeaf.Deps)
+	assert.Equal(t, "true", prunedLeaf.Info.Labels[ecosystems.PrunedLabel])
+}
+
+func TestPluginRegistry_Resolve_ValidatesDepGraphs(t *testing.T) {
+	newGraph := func() *depgraph.DepGraph {
+		builder, err := depgraph.NewBuilder(&depgraph.PkgManager{Name: "pip"}, &depgraph.PkgInfo{Name: "root", Version: "1.0.0"})
+		require.NoError(t, err)
+		builder.AddNode("a@?", &depgraph.PkgInfo{Name: "a", Version: "?"})
+		require.NoError(t, builder.ConnectNodes(builder.GetRootNode().NodeID, "a@?"))
+		return builder.Build()
+	}
+	resolve := func(t *testing.T, opts *ecosystems.SCAPluginOptions) ecosystems.SCAResult {
+		t.Helper()
+		r, err := NewPluginRegistry(setupMockInvocationContext(t),
+			&mockPlugin{name: "plugin-a", results: []ecosystems.SCAResult{{DepGraph: newGraph()}}},
+		)
+		require.NoError(t, err)
+
+		var results []ecosystems.SCAResult
+		_, err = r.Resolve("/test/dir", opts, func(result ecosystems.SCAResult) error {
+			results = append(results, result)
+			return nil
+		})
+		require.NoError(t, err)
+		require.Len(t, results, 1)
+		return results[0]
+	}
+
+	t.Run("findings become warnings", func(t *testing.T) {
+		result := resolve(t, ecosystems.NewPluginOptions())
+
+		require.NoError(t, result.Error)
+		assert.NotNil(t, result.DepGraph)
+		assert.Equal(t, []string{`missing-version: package "a" of node "a@?" has no version`}, result.Warnings)
+	})
+
+	t.Run("findings become errors in strict mode", func(t *testing.T) {
+		result := resolve(t, ecosystems.NewPluginOptions().WithStrictGraphValidation(true))
+
+		var validationErr *ecosystems.GraphValidationError
+		require.ErrorAs(t, result.Error, &validationErr)
+		assert.Len(t, validationErr.Issues, 1)
+		assert.Nil(t, result.DepGraph)
+		assert.Empty(t, result.Warnings)
+	})
+}
+
+func TestPluginRegistry_Resolve_SerialRegistryPropagatesProcessedFilesInAllProjectsMode(t *testing.T) {
+	pluginA := &mockPlugin{name: "plugin-a", results: []ecosystems.SCAResult{{ProcessedFiles: []string{"a/lock.json"}}}}
+	pluginB := &mockPlugin{name: "plugin-b", results: []ecosystems.SCAResult{{ProcessedFiles: []string{"b/lock.json"}}}}
+	pluginC := &mockPlugin{name: "plugin-c"}
+
+	r, err := NewPluginRegistry(setupMockInvocationContext(t), pluginA, pluginB, pluginC)
+	require.NoError(t, err)
+
+	opts := ecosystems.NewPluginOptions().WithAllProjects(true)
+	_, err = r.Resolve("/test/dir", opts, func(ecosystems.SCAResult) error { return nil })
+	require.NoError(t, err)
+
+	assert.Empty(t, pluginA.capturedExcludePaths)
+	assert.Equal(t, []string{"a/lock.json"}, pluginB.capturedExcludePaths)
+	assert.Equal(t, []string{"a/lock.json", "b/lock.json"}, pluginC.capturedExcludePaths)
+	assert.Empty(t, opts.Global.ExcludePaths, "caller's opts must not be mutated")
+}
+
+func TestPluginRegistry_Resolve_PluginErrorAbortsRun(t *testing.T) {
+	setupErr := errors.New("target limit exceeded")
+	pluginB := &mockPlugin{name: "plugin-b", results: []ecosystems.SCAResult{{}}}
+
+	r, err := NewPluginRegistry(setupMockInvocationContext(t),
+		&mockPlugin{name: "plugin-a", err: setupErr},
+		pluginB,
+	)
+	require.NoError(t, err)
+
+	opts := ecosystems.NewPluginOptions().WithAllProjects(true)
+	_, err = r.Resolve("/test/dir", opts, func(ecosystems.SCAResult) error { return nil })
+
+	var pluginErr *PluginError
+	require.ErrorAs(t, err, &pluginErr)
+	assert.Equal(t, "plugin-a", pluginErr.PluginName)
+	assert.Equal(t, "/test/dir", pluginErr.Dir)
+	require.ErrorIs(t, err, setupErr)
+	assert.False(t, pluginB.called, "plugins after the failing one must not run")
+}
+
+func TestPluginRegistry_Resolve_OnGraphErrorIsReturnedUnchanged(t *testing.T) {
+	callbackErr := errors.New("stop")
+	r, err := NewPluginRegistry(setupMockInvocationContext(t),
+		&mockPlugin{name: "plugin-a", results: []ecosystems.SCAResult{{}, {}}},
+	)
+	require.NoError(t, err)
+
+	calls := 0
+	_, err = r.Resolve("/test/dir", ecosystems.NewPluginOptions(), func(ecosystems.SCAResult) error {
+		calls++
+		return callbackErr
+	})
+
+	assert.Equal(t, callbackErr, err)
+	assert.Equal(t, 1, calls)
+}
+
+func TestPluginRegistry_Resolve_FailFast(t *testing.T) {
+	resultErr := errors.New("could not parse lockfile")
+	newPlugins := func() []ecosystems.SCAPlugin {
+		return []ecosystems.SCAPlugin{
+			&mockPlugin{name: "plugin-a", results: []ecosystems.SCAResult{
+				{ResolverMetadata: &ecosystems.ResolverMetadata{NormalisedTargetFile: "a/lock.json"}, Error: resultErr},
+				{ResolverMetadata: &ecosystems.ResolverMetadata{NormalisedTargetFile: "a/other.json"}},
+			}},
+			&mockPlugin{name: "plugin-b", results: []ecosystems.SCAResult{{}}},
+		}
+	}
+
+	t.Run("aborts on the first errored result with --all-projects", func(t *testing.T) {
+		r, err := NewPluginRegistry(setupMockInvocationContext(t), newPlugins()...)
+		require.NoError(t, err)
+
+		opts := ecosystems.NewPluginOptions().WithAllProjects(true).WithFailFast(true)
+		var seen []string
+		_, err = r.Resolve("/test/dir", opts, func(result ecosystems.SCAResult) error {
+			seen = append(seen, result.ResolverMetadata.NormalisedTargetFile)
+			return nil
+		})
+
+		var ffErr *FailFastError
+		require.ErrorAs(t, err, &ffErr)
+		assert.Equal(t, "a/lock.json", ffErr.Result.ResolverMetadata.NormalisedTargetFile)
+		require.ErrorIs(t, err, resultErr)
+		assert.Equal(t, []string{"a/lock.json"}, seen, "the errored result is delivered, nothing after it")
+	})
+
+	t.Run("is ignored without --all-projects", func(t *testing.T) {
+		r, err := NewPluginRegistry(setupMockInvocationContext(t), newPlugins()...)
+		require.NoError(t, err)
+
+		opts := ecosystems.NewPluginOptions().WithFailFast(true)
+		calls := 0
+		_, err = r.Resolve("/test/dir", opts, func(ecosystems.SCAResult) error {
+			calls++
+			return nil
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, 2, calls)
+	})
+}
+
+func TestPluginRegistry_ResolveDepgraphs_EmitsPluginFailureAndContinues(t *testing.T) {
+	setupErr := errors.New("too many targets")
+	r := &PluginRegistry{
+		ictx:    setupMockInvocationContext(t),
+		entries: make([]pluginEntry, 0),
+		plugins: make([]ecosystems.SCAPlugin, 0),
+	}
+
+	require.NoError(t, r.register(&mockPlugin{name: "plugin-a", err: setupErr}))
+	require.NoError(t, r.register(&mockPlugin{name: "plugin-b", results: []ecosystems.SCAResult{{}}}, withPluginDependencies("plugin-a")))
+
+	opts := ecosystems.NewPluginOptions().WithAllProjects(true)
+	results := collectResults(r.ResolveDepgraphs("/test/dir", opts))
+
+	require.Len(t, results, 2)
+
+	failure := results[0]
+	assert.Equal(t, "plugin-a", failure.ResolverMetadata.PluginName)
+	var pluginErr *PluginError
+	require.ErrorAs(t, failure.Error, &pluginErr)
+	assert.Equal(t, "plugin-a", pluginErr.PluginName)
+	assert.Equal(t, "/test/dir", pluginErr.Dir)
+	require.ErrorIs(t, failure.Error, setupErr)
+
+	assert.Equal(t, "plugin-b", results[1].ResolverMetadata.PluginName)
+	assert.NoError(t, results[1].Error)
+}
+
+func TestPluginRegistry_ResolveDepgraphs_PluginFailureTriggersFailFast(t *testing.T) {
+	pluginB := &mockPlugin{name: "plugin-b", results: []ecosystems.SCAResult{{}}}
+	r, err := NewPluginRegistry(setupMockInvocationContext(t),
+		&mockPlugin{name: "plugin-a", err: errors.New("boom")},
+		pluginB,
+	)
+	require.NoError(t, err)
+
+	opts := ecosystems.NewPluginOptions().WithAllProjects(true).WithFailFast(true)
+	results := collectResults(r.ResolveDepgraphs("/test/dir", opts))
+
+	require.Len(t, results, 1)
+	var pluginErr *PluginError
+	require.ErrorAs(t, results[0].Error, &pluginErr)
+	assert.False(t, pluginB.called, "plugins after the failure must not run")
+}
+
+func TestPluginRegistry_Resolve_RunReport(t *testing.T) {
+	r := &PluginRegistry{
+		ictx:     setupMockInvocationContext(t),
+		entries:  make([]pluginEntry, 0),
+		plugins:  make([]ecosystems.SCAPlugin, 0),
+		fallback: &mockPlugin{name: "fallback"},
+	}
+	require.NoError(t, r.register(&mockPlugin{name: "plugin-off"}, withFeatureFlagCheck(flag{Key: "this-ff-is-disabled"})))
+	require.NoError(t, r.register(&mockPlugin{name: "plugin-a", results: []ecosystems.SCAResult{
+		{ProcessedFiles: []string{"user/excluded", "a/lock.json"}},
+		{ProcessedFiles: []string{"a/lock.json"}, Error: errors.New("bad lockfile")},
+	}}))
+	require.NoError(t, r.register(&mockPlugin{name: "plugin-b"}))
+
+	opts := ecosystems.NewPluginOptions().WithExcludePaths([]string{"user/excluded"})
+	report, err := r.Resolve("/test/dir", opts, func(ecosystems.SCAResult) error { return nil })
+	require.NoError(t, err)
+
+	require.Len(t, report.Plugins, 4)
+	assert.Equal(t, PluginReport{
+		Name:   "plugin-off",
+		Status: PluginStatusSkipped,
+		Reason: "feature flag this-ff-is-disabled is disabled",
+	}, report.Plugins[0])
+
+	pluginA := report.Plugins[1]
+	assert.Equal(t, "plugin-a", pluginA.Name)
+	assert.Equal(t, PluginStatusSucceeded, pluginA.Status)
+	assert.Equal(t, 2, pluginA.Results)
+	assert.Equal(t, 1, pluginA.ErroredResults)
+	assert.Equal(t, []string{"user/excluded", "a/lock.json"}, pluginA.ProcessedFiles)
+	assert.Equal(t, []string{"a/lock.json"}, pluginA.AddedExcludePaths)
+
+	for _, name := range []string{"plugin-b", "fallback"} {
+		pr, ok := report.Plugin(name)
+		require.True(t, ok, name)
+		assert.Equal(t, PluginStatusNotRun, pr.Status, name)
+		assert.Equal(t, reasonHandled, pr.Reason, name)
+	}
+}
+
+func TestPluginRegistry_Resolve_RunReportRecordsPluginFailure(t *testing.T) {
+	r, err := NewPluginRegistry(setupMockInvocationContext(t),
+		&mockPlugin{name: "plugin-a", err: errors.New("boom")},
+		&mockPlugin{name: "plugin-b"},
+	)
+	require.NoError(t, err)
+
+	report, err := r.Resolve("/test/dir", ecosystems.NewPluginOptions(), func(ecosystems.SCAResult) error { return nil })
+	require.Error(t, err)
+
+	pluginA, ok := report.Plugin("plugin-a")
+	require.True(t, ok)
+	assert.Equal(t, PluginStatusFailed, pluginA.Status)
+	assert.Equal(t, "boom", pluginA.Error)
+
+	pluginB, ok := report.Plugin("plugin-b")
+	require.True(t, ok)
+	assert.Equal(t, PluginStatusNotRun, pluginB.Status)
+	assert.Equal(t, reasonAborted, pluginB.Reason)
+}
+
+// hangingPlugin blocks until its context ends, as a plugin stuck on a slow
+// build tool would. It then emits an errored result for targetFile, if set,
+// and returns the context's error unless reportOnly is set.
+type hangingPlugin struct {
+	name       string
+	targetFile string
+	reportOnly bool
+}
+
+func (p *hangingPlugin) GetName() string {
+	return p.name
+}
+
+func (p *hangingPlugin) BuildDepGraphsFromDir(
+	ctx context.Context,
+	_ logger.Logger,
+	_ string,
+	_ *ecosystems.SCAPluginOptions,
+	onGraph ecosystems.OnGraphFunc,
+) error {
+	<-ctx.Done()
+	if p.targetFile != "" {
+		err := onGraph(ecosystems.SCAResult{
+			ResolverMetadata: &ecosystems.ResolverMetadata{NormalisedTargetFile: p.targetFile},
+			Error:            fmt.Errorf("resolving %s: %w", p.targetFile, ctx.Err()),
+		})
+		if err != nil {
+			return err
+		}
+	}
+	if p.reportOnly {
+		return nil
+	}
+	return ctx.Err()
+}
+
+func TestPluginRegistry_Resolve_PluginTimeout(t *testing.T) {
+	pluginB := &mockPlugin{name: "plugin-b", results: []ecosystems.SCAResult{{}}}
+	r, err := NewPluginRegistry(setupMockInvocationContext(t), &hangingPlugin{name: "plugin-a"}, pluginB)
+	require.NoError(t, err)
+
+	opts := ecosystems.NewPluginOptions().WithAllProjects(true).WithPluginTimeout(10 * time.Millisecond)
+	var results []ecosystems.SCAResult
+	report, err := r.Resolve("/test/dir", opts, func(result ecosystems.SCAResult) error {
+		results = append(results, result)
+		return nil
+	})
+	require.NoError(t, err, "a timeout is reported as a result, not by aborting the run")
+
+	require.Len(t, results, 2)
+	assert.Equal(t, "plugin-a", results[0].ResolverMetadata.PluginName)
+	var snykErr snyk_errors.Error
+	require.ErrorAs(t, results[0].Error, &snykErr)
+	assert.Equal(t, "SNYK-0004", snykErr.ErrorCode)
+	var timeoutErr *TimeoutError
+	require.ErrorAs(t, results[0].Error, &timeoutErr)
+	assert.Equal(t, "plugin-a", timeoutErr.PluginName)
+	assert.Equal(t, 10*time.Millisecond, timeoutErr.Timeout)
+
+	assert.True(t, pluginB.called, "plugins after the timed-out one still run")
+	require.NoError(t, results[1].Error)
+
+	pluginA, ok := report.Plugin("plugin-a")
+	require.True(t, ok)
+	assert.Equal(t, PluginStatusTimedOut, pluginA.Status)
+	assert.Equal(t, "plugin-a plugin did not finish within 10ms", pluginA.Error)
+}
+
+func TestPluginRegistry_Resolve_PluginTimeoutReplacesProjectErrors(t *testing.T) {
+	r, err := NewPluginRegistry(setupMockInvocationContext(t),
+		&hangingPlugin{name: "plugin-a", targetFile: "a/lock.json", reportOnly: true},
+	)
+	require.NoError(t, err)
+
+	opts := ecosystems.NewPluginOptions().WithAllProjects(true).WithPluginTimeout(10 * time.Millisecond)
+	var results []ecosystems.SCAResult
+	report, err := r.Resolve("/test/dir", opts, func(result ecosystems.SCAResult) error {
+		results = append(results, result)
+		return nil
+	})
+	require.NoError(t, err)
+
+	require.Len(t, results, 1, "a plugin that reports its own projects gets no extra result")
+	var snykErr snyk_errors.Error
+	require.ErrorAs(t, results[0].Error, &snykErr)
+	assert.Equal(t, "SNYK-0004", snykErr.ErrorCode)
+	assert.Equal(t, "a/lock.json: plugin-a plugin did not finish within 10ms", snykErr.Detail)
+
+	pluginA, ok := report.Plugin("plugin-a")
+	require.True(t, ok)
+	assert.Equal(t, PluginStatusTimedOut, pluginA.Status)
+}
+
+func TestPluginRegistry_Resolve_ScanTimeout(t *testing.T) {
+	pluginB := &mockPlugin{name: "plugin-b", results: []ecosystems.SCAResult{{}}}
+	r, err := NewPluginRegistry(setupMockInvocationContext(t), &hangingPlugin{name: "plugin-a"}, pluginB)
+	require.NoError(t, err)
+
+	opts := ecosystems.NewPluginOptions().WithAllProjects(true).WithScanTimeout(10 * time.Millisecond)
+	var results []ecosystems.SCAResult
+	report, err := r.Resolve("/test/dir", opts, func(result ecosystems.SCAResult) error {
+		results = append(results, result)
+		return nil
+	})
+	require.NoError(t, err)
+
+	require.Len(t, results, 2)
+	var timeoutErr *TimeoutError
+	require.ErrorAs(t, results[0].Error, &timeoutErr)
+	assert.Empty(t, timeoutErr.PluginName, "the scan budget, not a plugin limit, expired")
+	assert.False(t, pluginB.called, "plugins must not start once the scan budget is spent")
+
+	// A plugin that never started is still reported, by one result for the
+	// whole plugin when it cannot list its projects.
+	assert.Equal(t, "plugin-b", results[1].ResolverMetadata.PluginName)
+	var pluginErr *PluginError
+	require.ErrorAs(t, results[1].Error, &pluginErr)
+	assert.Equal(t, "plugin-b", pluginErr.PluginName)
+	var snykErr snyk_errors.Error
+	require.ErrorAs(t, results[1].Error, &snykErr)
+	assert.Equal(t, "SNYK-0004", snykErr.ErrorCode)
+
+	pluginBReport, ok := report.Plugin("plugin-b")
+	require.True(t, ok)
+	assert.Equal(t, PluginStatusTimedOut, pluginBReport.Status)
+}
+
+func TestPluginRegistry_Resolve_ScanTimeoutReportsDetectedProjects(t *testing.T) {
+	pluginB := &mockDetector{
+		mockPlugin: mockPlugin{name: "plugin-b", results: []ecosystems.SCAResult{{}}},
+		projects: []ecosystems.DetectedProject{
+			{TargetFile: "a/uv.lock", ProjectType: "pip"},
+			{TargetFile: "b/uv.lock", ProjectType: "pip"},
+		},
+	}
+	r, err := NewPluginRegistry(setupMockInvocationContext(t), &hangingPlugin{name: "plugin-a"}, pluginB)
+	require.NoError(t, err)
+
+	opts := ecosystems.NewPluginOptions().WithAllProjects(true).WithScanTimeout(10 * time.Millisecond)
+	var results []ecosystems.SCAResult
+	_, err = r.Resolve("/test/dir", opts, func(result ecosystems.SCAResult) error {
+		results = append(results, result)
+		return nil
+	})
+	require.NoError(t, err)
+	assert.False(t, pluginB.called)
+
+	require.Len(t, results, 3)
+	for i, targetFile := range []string{"a/uv.lock", "b/uv.lock"} {
+		result := results[i+1]
+		assert.Equal(t, targetFile, result.ProjectDescriptor.GetTargetFile())
+		assert.Equal(t, "pip", result.ProjectDescriptor.Identity.ProjectType)
+		assert.Equal(t, "plugin-b", result.ResolverMetadata.PluginName)
+		var snykErr snyk_errors.Error
+		require.ErrorAs(t, result.Error, &snykErr)
+		assert.Equal(t, "SNYK-0004", snykErr.ErrorCode)
+		assert.Equal(t, targetFile+": scan did not finish within 10ms", snykErr.Detail)
+	}
+}
+
+// hungCommandPlugin runs a command, named command, that never finishes on its
+// own, as a build tool waiting on a lock would. It emits an errored result for
+// targetFile once the command is killed, or fails as a whole when targetFile
+// is empty.
+type hungCommandPlugin struct {
+	name       string
+	command    string
+	targetFile string
+}
+
+func (p *hungCommandPlugin) GetName() string {
+	return p.name
+}
+
+func (p *hungCommandPlugin) BuildDepGraphsFromDir(
+	ctx context.Context,
+	_ logger.Logger,
+	_ string,
+	_ *ecosystems.SCAPluginOptions,
+	onGraph ecosystems.OnGraphFunc,
+) error {
+	cmdCtx, cancel := ecosystems.CommandContext(ctx, p.command)
+	defer cancel()
+
+	<-cmdCtx.Done()
+	err := fmt.Errorf("%s failed: %w", p.command, ecosystems.CommandError(cmdCtx, errors.New("signal: killed")))
+	if p.targetFile == "" {
+		return err
+	}
+	return onGraph(ecosystems.SCAResult{
+		ResolverMetadata: &ecosystems.ResolverMetadata{NormalisedTargetFile: p.targetFile},
+		Error:            err,
+	})
+}
+
+func TestPluginRegistry_Resolve_CommandTimeout(t *testing.T) {
+	pluginB := &mockPlugin{name: "plugin-b", results: []ecosystems.SCAResult{{}}}
+	r, err := NewPluginRegistry(setupMockInvocationContext(t),
+		&hungCommandPlugin{name: "plugin-a", command: "gradle", targetFile: "a/build.gradle"},
+		pluginB,
+	)
+	require.NoError(t, err)
+
+	opts := ecosystems.NewPluginOptions().WithAllProjects(true).WithCommandTimeout(10 * time.Millisecond)
+	var results []ecosystems.SCAResult
+	report, err := r.Resolve("/test/dir", opts, func(result ecosystems.SCAResult) error {
+		results = append(results, result)
+		return nil
+	})
+	require.NoError(t, err)
+
+	require.Len(t, results, 2)
+	var snykErr snyk_errors.Error
+	require.ErrorAs(t, results[0].Error, &snykErr)
+	assert.Equal(t, "SNYK-0004", snykErr.ErrorCode)
+	assert.Equal(t, "a/build.gradle: gradle did not finish within 10ms", snykErr.Detail)
+	var commandTimeout *ecosystems.CommandTimeoutError
+	require.ErrorAs(t, results[0].Error, &commandTimeout)
+	assert.Equal(t, "gradle", commandTimeout.Command)
+
+	assert.True(t, pluginB.called, "a command timeout only cuts its own project short")
+	require.NoError(t, results[1].Error)
+
+	pluginA, ok := report.Plugin("plugin-a")
+	require.True(t, ok)
+	assert.Equal(t, PluginStatusSucceeded, pluginA.Status)
+	assert.Equal(t, 1, pluginA.ErroredResults)
+}
+
+func TestPluginRegistry_Resolve_CommandTimeoutFailsPlugin(t *testing.T) {
+	r, err := NewPluginRegistry(setupMockInvocationContext(t), &hungCommandPlugin{name: "plugin-a", command: "pnpm"})
+	require.NoError(t, err)
+
+	opts := ecosystems.NewPluginOptions().WithAllProjects(true).WithCommandTimeout(10 * time.Millisecond)
+	var results []ecosystems.SCAResult
+	report, err := r.Resolve("/test/dir", opts, func(result ecosystems.SCAResult) error {
+		results = append(results, result)
+		return nil
+	})
+	require.NoError(t, err)
+
+	require.Len(t, results, 1)
+	var pluginErr *PluginError
+	require.ErrorAs(t, results[0].Error, &pluginErr)
+	var snykErr snyk_errors.Error
+	require.ErrorAs(t, results[0].Error, &snykErr)
+	assert.Equal(t, "SNYK-0004", snykErr.ErrorCode)
+	assert.Equal(t, "pnpm did not finish within 10ms", snykErr.Detail)
+
+	pluginA, ok := report.Plugin("plugin-a")
+	require.True(t, ok)
+	assert.Equal(t, PluginStatusTimedOut, pluginA.Status)
+}
+
+func TestPluginRegistry_Resolve_WithResultCache(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "uv.lock"), []byte("version = 1"), 0o600))
+	cache, err := resultcache.New(t.TempDir(), 0)
+	require.NoError(t, err)
+
+	uvLike := &mockDetector{
+		mockPlugin: mockPlugin{name: "uv", results: []ecosystems.SCAResult{{ProcessedFiles: []string{"uv.lock"}}}},
+		projects:   []ecosystems.DetectedProject{{TargetFile: "pyproject.toml", ClaimedFiles: []string{"uv.lock"}}},
+	}
+	r := &PluginRegistry{ictx: setupMockInvocationContext(t), cache: cache}
+	require.NoError(t, r.register(uvLike, withResultCache("")))
+
+	for i := range 2 {
+		uvLike.called = false
+		var results []ecosystems.SCAResult
+		_, err := r.Resolve(dir, ecosystems.NewPluginOptions(), func(result ecosystems.SCAResult) error {
+			results = append(results, result)
+			return nil
+		})
+		require.NoError(t, err)
+
+		require.Len(t, results, 1)
+		assert.Equal(t, []string{"uv.lock"}, results[0].ProcessedFiles)
+		assert.Equal(t, "uv", results[0].ResolverMetadata.PluginName)
+		assert.Equal(t, i == 0, uvLike.called, "only the first run resolves; the second replays the cache")
+	}
+}
+
 func TestPluginRegistry_Register_WithFeatureFlag(t *testing.T) {
 	r := &PluginRegistry{
 		ictx:    setupMockInvocationContext(t),
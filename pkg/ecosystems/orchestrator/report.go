@@ -0,0 +1,127 @@
+package orchestrator
+
+import (
+	"errors"
+
+	"github.com/snyk/cli-extension-dep-graph/v2/pkg/ecosystems"
+)
+
+// PluginStatus describes what happened to a plugin during a run.
+type PluginStatus string
+
+const (
+	// PluginStatusSucceeded means BuildDepGraphsFromDir returned without error.
+	// Individual results may still have failed; see PluginReport.ErroredResults.
+	PluginStatusSucceeded PluginStatus = "succeeded"
+	// PluginStatusFailed means BuildDepGraphsFromDir returned an error.
+	PluginStatusFailed PluginStatus = "failed"
+	// PluginStatusTimedOut means --plugin-timeout or --scan-timeout expired
+	// before the plugin finished, or before it started, or --command-timeout
+	// expired for a command the plugin failed on.
+	PluginStatusTimedOut PluginStatus = "timed-out"
+	// PluginStatusSkipped means the plugin was never registered, e.g. because
+	// its feature flag is disabled.
+	PluginStatusSkipped PluginStatus = "skipped"
+	// PluginStatusNotRun means the plugin was registered but the run finished
+	// or was aborted before it started.
+	PluginStatusNotRun PluginStatus = "not-run"
+)
+
+// RunReport summarizes a single registry run, one entry per plugin that was
+// considered, in the order the plugins were scheduled. Skipped plugins come
+// first and the fallback, if any, last.
+type RunReport struct {
+	Plugins []PluginReport `json:"plugins"`
+}
+
+// PluginReport describes one plugin's part in a run.
+type PluginReport struct {
+	Name   string       `json:"name"`
+	Status PluginStatus `json:"status"`
+	// Reason explains a skipped or not-run status.
+	Reason     string `json:"reason,omitempty"`
+	DurationMs int64  `json:"durationMs"`
+	// Results counts every SCAResult the plugin emitted, including the
+	// ErroredResults.
+	Results        int `json:"results"`
+	ErroredResults int `json:"erroredResults"`
+	// ProcessedFiles is every file the plugin claimed across its results.
+	ProcessedFiles []string `json:"processedFiles,omitempty"`
+	// AddedExcludePaths is the subset of ProcessedFiles that was not already
+	// on the plugin's ExcludePaths, and is hidden from later plugins because
+	// of this one.
+	AddedExcludePaths []string `json:"addedExcludePaths,omitempty"`
+	Error             string   `json:"error,omitempty"`
+}
+
+const (
+	reasonHandled = "an earlier plugin already handled the project"
+	reasonAborted = "the run was aborted"
+)
+
+// newRunReport assembles the report for a run from the registry's skipped
+// entries, the runs of its registered plugins (indexed like r.plugins, nil
+// for plugins that never ran) and the fallback's run, if it ran.
+func (r *PluginRegistry) newRunReport(runs []*pluginRun, fallbackRun *pluginRun, handled bool) *RunReport {
+	report := &RunReport{Plugins: make([]PluginReport, 0, len(r.skipped)+len(r.plugins)+1)}
+
+	for _, entry := range r.skipped {
+		report.Plugins = append(report.Plugins, PluginReport{
+			Name:   entry.plugin.GetName(),
+			Status: PluginStatusSkipped,
+			Reason: entry.skipReason,
+		})
+	}
+
+	notRunReason := reasonAborted
+	if handled {
+		notRunReason = reasonHandled
+	}
+	for i, plugin := range r.plugins {
+		report.Plugins = append(report.Plugins, newPluginReport(plugin.GetName(), runs[i], notRunReason))
+	}
+	if r.fallback != nil {
+		report.Plugins = append(report.Plugins, newPluginReport(r.fallback.GetName(), fallbackRun, notRunReason))
+	}
+
+	return report
+}
+
+func newPluginReport(name string, run *pluginRun, notRunReason string) PluginReport {
+	if run == nil {
+		return PluginReport{Name: name, Status: PluginStatusNotRun, Reason: notRunReason}
+	}
+
+	pr := PluginReport{
+		Name:              name,
+		Status:            PluginStatusSucceeded,
+		DurationMs:        run.duration.Milliseconds(),
+		Results:           run.results,
+		ErroredResults:    run.erroredResults,
+		ProcessedFiles:    run.files,
+		AddedExcludePaths: run.excludesAdded,
+	}
+	if run.err != nil {
+		pr.Status = PluginStatusFailed
+		var (
+			timeout        *TimeoutError
+			commandTimeout *ecosystems.CommandTimeoutError
+		)
+		if errors.As(run.err, &timeout) || errors.As(run.err, &commandTimeout) {
+			pr.Status = PluginStatusTimedOut
+		}
+		pr.Error = run.err.Error()
+	}
+	return pr
+}
+
+// Plugin returns the report for the named plugin, or false if the run did not
+// consider it.
+func (rr *RunReport) Plugin(name string) (PluginReport, bool) {
+	for _, pr := range rr.Plugins {
+		if pr.Name == name {
+			return pr, true
+		}
+	}
+	return PluginReport{}, false
+}
@@ -0,0 +1,113 @@
+package orchestrator
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/snyk/go-application-framework/pkg/configuration"
+
+	internalworkflow "github.com/snyk/cli-extension-dep-graph/v2/internal/workflow"
+	"github.com/snyk/cli-extension-dep-graph/v2/pkg/ecosystems"
+)
+
+// pluginSelection is the user's choice of plugins from --only-plugins and
+// --skip-plugins. The zero value selects every plugin.
+type pluginSelection struct {
+	only []string
+	skip []string
+}
+
+func selectionFromConfig(cfg configuration.Configuration) pluginSelection {
+	return pluginSelection{
+		only: splitPluginNames(cfg.GetString(internalworkflow.FlagOnlyPlugins)),
+		skip: splitPluginNames(cfg.GetString(internalworkflow.FlagSkipPlugins)),
+	}
+}
+
+func selectionFromOptions(opts *ecosystems.SCAPluginOptions) pluginSelection {
+	return pluginSelection{
+		only: splitPluginNames(strings.Join(opts.Global.OnlyPlugins, ",")),
+		skip: splitPluginNames(strings.Join(opts.Global.SkipPlugins, ",")),
+	}
+}
+
+// splitPluginNames parses a comma-separated list of plugin names, dropping
+// blanks.
+func splitPluginNames(value string) []string {
+	var names []string
+	for _, name := range strings.Split(value, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+func (s pluginSelection) isEmpty() bool {
+	return len(s.only) == 0 && len(s.skip) == 0
+}
+
+// skipReason explains why the selection excludes the named plugin, or returns
+// "" if the plugin is selected.
+func (s pluginSelection) skipReason(name string) string {
+	if len(s.only) > 0 && !slices.Contains(s.only, name) {
+		return "not listed in --only-plugins"
+	}
+	if slices.Contains(s.skip, name) {
+		return "listed in --skip-plugins"
+	}
+	return ""
+}
+
+// withSelection returns a copy of r that only runs the plugins s selects, with
+// the others, the fallback included, reported as skipped. r is not modified,
+// so a registry can serve runs with different selections.
+func (r *PluginRegistry) withSelection(s pluginSelection) *PluginRegistry {
+	if s.isEmpty() {
+		return r
+	}
+
+	selected := *r
+	selected.entries = make([]pluginEntry, 0, len(r.entries))
+	selected.plugins = make([]ecosystems.SCAPlugin, 0, len(r.plugins))
+	selected.skipped = slices.Clone(r.skipped)
+
+	excluded := make(map[string]bool)
+	for _, entry := range r.entries {
+		if reason := s.skipReason(entry.plugin.GetName()); reason != "" {
+			excluded[entry.plugin.GetName()] = true
+			entry.skip = true
+			entry.skipReason = reason
+			selected.skipped = append(selected.skipped, entry)
+			continue
+		}
+		selected.entries = append(selected.entries, entry)
+	}
+	// r.plugins is already in dependency order, and dropping plugins cannot
+	// break it.
+	for _, plugin := range r.plugins {
+		if !excluded[plugin.GetName()] {
+			selected.plugins = append(selected.plugins, plugin)
+		}
+	}
+
+	if r.fallback != nil {
+		if reason := s.skipReason(r.fallback.GetName()); reason != "" {
+			selected.skipped = append(selected.skipped, pluginEntry{plugin: r.fallback, skip: true, skipReason: reason})
+			selected.fallback = nil
+		}
+	}
+	return &selected
+}
+
+// unknownPluginNames returns the names in s that match no plugin known to r,
+// registered, skipped or fallback, which usually means a typo.
+func (r *PluginRegistry) unknownPluginNames(s pluginSelection) []string {
+	var unknown []string
+	for _, name := range slices.Concat(s.only, s.skip) {
+		if !r.isKnown(name) && !slices.Contains(unknown, name) {
+			unknown = append(unknown, name)
+		}
+	}
+	return unknown
+}
@@ -23,12 +23,16 @@ type ResolverMetadata struct {
 // (lockfile + any manifests consulted). Per-graph attribution; if a
 // consumer wants a deduped union across all results, it computes it
 // itself.
+//
+// Warnings lists problems that did not stop the dep-graph from being
+// built, such as those ValidateDepGraph finds.
 type SCAResult struct {
 	DepGraph          *depgraph.DepGraph         `json:"depGraph,omitempty"`
 	ProjectDescriptor identity.ProjectDescriptor `json:"projectDescriptor"`
 	ResolverMetadata  *ResolverMetadata          `json:"meta,omitempty"`
 	ProcessedFiles    []string                   `json:"processedFiles,omitempty"`
 	Error             error                      `json:"error,omitempty"`
+	Warnings          []string                   `json:"warnings,omitempty"`
 }
 
 // OnGraphFunc is the per-graph callback BuildDepGraphsFromDir invokes
@@ -61,3 +65,37 @@ type SCAPlugin interface {
 	) error
 	GetName() string
 }
+
+// DetectedProject is a project a plugin would resolve, found by inspecting
+// files on disk only.
+type DetectedProject struct {
+	TargetFile  string `json:"targetFile"`
+	ProjectType string `json:"projectType"`
+	// ClaimedFiles lists the files the plugin expects to report in
+	// ProcessedFiles when it resolves the project, and so hides from the
+	// plugins that run after it.
+	ClaimedFiles []string `json:"claimedFiles,omitempty"`
+}
+
+// OnDetectFunc is the per-project callback DetectProjects invokes. It
+// follows the same contract as OnGraphFunc.
+type OnDetectFunc func(DetectedProject) error
+
+// ProjectDetector is an optional extension of SCAPlugin for plugins that
+// can list the projects they would handle without running a package
+// manager or build tool. DetectProjects honors the same discovery options
+// as BuildDepGraphsFromDir (target file, --all-projects, excludes), so
+// the projects it reports are the ones a real run would attempt.
+//
+// Detection is a cheap preview: where the set of projects is only known
+// after running the tool (workspace members, Gradle sub-projects), a
+// plugin reports the root project it would start from.
+type ProjectDetector interface {
+	DetectProjects(
+		ctx context.Context,
+		log logger.Logger,
+		dir string,
+		options *SCAPluginOptions,
+		onProject OnDetectFunc,
+	) error
+}
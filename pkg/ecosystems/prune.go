@@ -0,0 +1,121 @@
+package ecosystems
+
+import (
+	"fmt"
+	"maps"
+
+	"github.com/snyk/dep-graph/go/pkg/depgraph"
+)
+
+const (
+	// PrunedLabel is the node label set on a package whose dependencies
+	// PruneRepeatedSubdependencies removed because they appear earlier in the
+	// graph.
+	PrunedLabel = "pruned"
+	prunedValue = "true"
+	// prunedNodeSuffix is appended to a package's ID to form the ID of the
+	// node that stands in for its repeated occurrences.
+	prunedNodeSuffix = ":pruned"
+)
+
+// PruneRepeatedSubdependencies returns a copy of dg in which every package's
+// dependencies are listed once, at its first occurrence in a depth-first walk
+// from the root. Later occurrences point to a single leaf node labelled
+// PrunedLabel instead, which also breaks any cycles. dg is not modified.
+func PruneRepeatedSubdependencies(dg *depgraph.DepGraph) (*depgraph.DepGraph, error) {
+	nodes := make(map[string]*depgraph.Node, len(dg.Graph.Nodes))
+	for i := range dg.Graph.Nodes {
+		nodes[dg.Graph.Nodes[i].NodeID] = &dg.Graph.Nodes[i]
+	}
+	root, ok := nodes[dg.Graph.RootNodeID]
+	if !ok {
+		return nil, fmt.Errorf("root node %q not found", dg.Graph.RootNodeID)
+	}
+
+	p := &pruner{
+		nodes:    nodes,
+		expanded: map[string]bool{root.PkgID: true},
+		kept:     map[string]int{},
+		usedPkgs: map[string]bool{root.PkgID: true},
+	}
+	if err := p.keep(root); err != nil {
+		return nil, err
+	}
+
+	pruned := &depgraph.DepGraph{
+		SchemaVersion: dg.SchemaVersion,
+		PkgManager:    dg.PkgManager,
+		Pkgs:          make([]depgraph.Pkg, 0, len(p.usedPkgs)),
+		Graph:         depgraph.Graph{RootNodeID: dg.Graph.RootNodeID, Nodes: p.out},
+	}
+	for _, pkg := range dg.Pkgs {
+		if p.usedPkgs[pkg.ID] {
+			pruned.Pkgs = append(pruned.Pkgs, pkg)
+		}
+	}
+	if err := pruned.BuildGraph(); err != nil {
+		return nil, fmt.Errorf("failed to build pruned graph: %w", err)
+	}
+	return pruned, nil
+}
+
+type pruner struct {
+	nodes map[string]*depgraph.Node
+	// expanded records the packages whose dependencies have been listed.
+	expanded map[string]bool
+	// kept maps the ID of every node in out to its index there.
+	kept     map[string]int
+	usedPkgs map[string]bool
+	out      []depgraph.Node
+}
+
+// keep adds node to the output and walks its dependencies, keeping each the
+// first time its package is reached and pointing to a pruned stand-in after.
+func (p *pruner) keep(node *depgraph.Node) error {
+	index := len(p.out)
+	p.kept[node.NodeID] = index
+	p.out = append(p.out, depgraph.Node{NodeID: node.NodeID, PkgID: node.PkgID, Info: node.Info, Deps: []depgraph.Dependency{}})
+
+	for _, dep := range node.Deps {
+		child, ok := p.nodes[dep.NodeID]
+		if !ok {
+			return fmt.Errorf("node %q depends on unknown node %q", node.NodeID, dep.NodeID)
+		}
+		p.usedPkgs[child.PkgID] = true
+
+		var childID string
+		switch _, seen := p.kept[child.NodeID]; {
+		case seen && len(child.Deps) == 0:
+			childID = child.NodeID
+		case len(child.Deps) > 0 && p.expanded[child.PkgID]:
+			childID = p.prunedNode(child)
+		default:
+			p.expanded[child.PkgID] = true
+			if err := p.keep(child); err != nil {
+				return err
+			}
+			childID = child.NodeID
+		}
+		p.out[index].Deps = append(p.out[index].Deps, depgraph.Dependency{NodeID: childID})
+	}
+	return nil
+}
+
+// prunedNode returns the ID of the leaf node standing in for the repeated
+// occurrences of node's package, adding it on first use.
+func (p *pruner) prunedNode(node *depgraph.Node) string {
+	id := node.PkgID + prunedNodeSuffix
+	if _, ok := p.kept[id]; ok {
+		return id
+	}
+
+	info := &depgraph.NodeInfo{Labels: map[string]string{PrunedLabel: prunedValue}}
+	if node.Info != nil {
+		info.VersionProvenance = node.Info.VersionProvenance
+		maps.Copy(info.Labels, node.Info.Labels)
+		info.Labels[PrunedLabel] = prunedValue
+	}
+	p.kept[id] = len(p.out)
+	p.out = append(p.out, depgraph.Node{NodeID: id, PkgID: node.PkgID, Info: info, Deps: []depgraph.Dependency{}})
+	return id
+}
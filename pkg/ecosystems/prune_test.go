@@ -0,0 +1,86 @@
+package ecosystems
+
+import (
+	"testing"
+
+	"github.com/snyk/dep-graph/go/pkg/depgraph"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// depsOf maps every node of dg to the IDs of its dependencies.
+func depsOf(dg *depgraph.DepGraph) map[string][]string {
+	deps := make(map[string][]string, len(dg.Graph.Nodes))
+	for _, node := range dg.Graph.Nodes {
+		deps[node.NodeID] = []string{}
+		for _, dep := range node.Deps {
+			deps[node.NodeID] = append(deps[node.NodeID], dep.NodeID)
+		}
+	}
+	return deps
+}
+
+func TestPruneRepeatedSubdependencies(t *testing.T) {
+	builder, err := depgraph.NewBuilder(&depgraph.PkgManager{Name: "gradle"}, &depgraph.PkgInfo{Name: "app", Version: "1.0.0"})
+	require.NoError(t, err)
+	root := builder.GetRootNode().NodeID
+	for _, id := range []string{"a", "b", "shared", "leaf", "unused"} {
+		builder.AddNode(id, &depgraph.PkgInfo{Name: id, Version: "1.0.0"})
+	}
+	for _, edge := range [][2]string{
+		{root, "a"}, {root, "b"},
+		{"a", "shared"}, {"b", "shared"},
+		{"shared", "leaf"}, {"b", "leaf"},
+		{"leaf", "unused"}, {"unused", "leaf"},
+	} {
+		require.NoError(t, builder.ConnectNodes(edge[0], edge[1]))
+	}
+	dg := builder.Build()
+
+	pruned, err := PruneRepeatedSubdependencies(dg)
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string][]string{
+		root:                  {"a", "b"},
+		"a":                   {"shared"},
+		"shared":              {"leaf"},
+		"leaf":                {"unused"},
+		"unused":              {"leaf@1.0.0:pruned"},
+		"leaf@1.0.0:pruned":   {},
+		"b":                   {"shared@1.0.0:pruned", "leaf@1.0.0:pruned"},
+		"shared@1.0.0:pruned": {},
+	}, depsOf(pruned), "repeated packages with dependencies point to a pruned stand-in, which also breaks the cycle")
+
+	pkg, ok := pruned.GetPkg("shared@1.0.0")
+	require.True(t, ok)
+	assert.Equal(t, "shared", pkg.Info.Name)
+	for _, node := range pruned.Graph.Nodes {
+		if node.NodeID == "shared@1.0.0:pruned" {
+			assert.Equal(t, map[string]string{PrunedLabel: "true"}, node.Info.Labels)
+		}
+	}
+
+	assert.Len(t, dg.Graph.Nodes, 6, "the input graph is not modified")
+	assert.Equal(t, []string{"shared"}, depsOf(dg)["a"])
+	assert.Equal(t, []string{"shared", "leaf"}, depsOf(dg)["b"])
+}
+
+func TestPruneRepeatedSubdependencies_KeepsRepeatedLeaves(t *testing.T) {
+	builder, err := depgraph.NewBuilder(&depgraph.PkgManager{Name: "npm"}, &depgraph.PkgInfo{Name: "app", Version: "1.0.0"})
+	require.NoError(t, err)
+	root := builder.GetRootNode().NodeID
+	builder.AddNode("a", &depgraph.PkgInfo{Name: "a", Version: "1.0.0"})
+	builder.AddNode("leaf", &depgraph.PkgInfo{Name: "leaf", Version: "1.0.0"})
+	require.NoError(t, builder.ConnectNodes(root, "a"))
+	require.NoError(t, builder.ConnectNodes(root, "leaf"))
+	require.NoError(t, builder.ConnectNodes("a", "leaf"))
+
+	pruned, err := PruneRepeatedSubdependencies(builder.Build())
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string][]string{
+		root:   {"a", "leaf"},
+		"a":    {"leaf"},
+		"leaf": {},
+	}, depsOf(pruned), "a package without dependencies has nothing to prune")
+}
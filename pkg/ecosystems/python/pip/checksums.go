@@ -0,0 +1,62 @@
+package pip
+
+import (
+	"strings"
+
+	"github.com/package-url/packageurl-go"
+	"github.com/snyk/dep-graph/go/pkg/depgraph"
+)
+
+// checksumQualifier is the purl qualifier checksums are recorded in, as the
+// gradle plugin does for artifact checksums.
+const checksumQualifier = "checksum"
+
+// ChecksumKey returns the key AddChecksums looks a package's checksums up by.
+func ChecksumKey(name, version string) string {
+	return normalizePackageName(name) + "@" + version
+}
+
+// AddChecksums records lockfile checksums on the dep-graph's packages, as the
+// purl checksum qualifier ("sha256:<hex>,sha256:<hex>"). checksums is keyed by
+// ChecksumKey and holds "algorithm:hex" values. An existing purl keeps its
+// other qualifiers; packages without checksums and the root are left as is.
+func AddChecksums(depGraph *depgraph.DepGraph, checksums map[string][]string) {
+	if depGraph == nil || len(checksums) == 0 {
+		return
+	}
+
+	var rootID string
+	if rootPkg := depGraph.GetRootPkg(); rootPkg != nil {
+		rootID = rootPkg.ID
+	}
+
+	for i := range depGraph.Pkgs {
+		pkg := &depGraph.Pkgs[i]
+		if pkg.ID == rootID {
+			continue
+		}
+		sums := checksums[ChecksumKey(pkg.Info.Name, pkg.Info.Version)]
+		if len(sums) == 0 {
+			continue
+		}
+		pkg.Info.PackageURL = withChecksums(pkg.Info, sums)
+	}
+}
+
+func withChecksums(info depgraph.PkgInfo, sums []string) string {
+	purl := packageurl.NewPackageURL(packageurl.TypePyPi, "", normalizePackageName(info.Name), info.Version, nil, "")
+	if info.PackageURL != "" {
+		if existing, err := packageurl.FromString(info.PackageURL); err == nil {
+			purl = &existing
+		}
+	}
+
+	qualifiers := make(packageurl.Qualifiers, 0, len(purl.Qualifiers)+1)
+	for _, q := range purl.Qualifiers {
+		if q.Key != checksumQualifier {
+			qualifiers = append(qualifiers, q)
+		}
+	}
+	purl.Qualifiers = append(qualifiers, packageurl.Qualifier{Key: checksumQualifier, Value: strings.Join(sums, ",")})
+	return purl.ToString()
+}
@@ -0,0 +1,42 @@
+//go:build !integration
+// +build !integration
+
+package pip
+
+import (
+	"testing"
+
+	"github.com/snyk/dep-graph/go/pkg/depgraph"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAddChecksums(t *testing.T) {
+	builder, err := depgraph.NewBuilder(&depgraph.PkgManager{Name: "pip"}, &depgraph.PkgInfo{Name: "root", Version: "0.0.0"})
+	require.NoError(t, err)
+	builder.AddNode("requests@2.31.0", &depgraph.PkgInfo{Name: "requests", Version: "2.31.0"})
+	builder.AddNode("mypy-extensions@1.0.0", &depgraph.PkgInfo{
+		Name:       "mypy-extensions",
+		Version:    "1.0.0",
+		PackageURL: "pkg:pypi/mypy-extensions@1.0.0?checksum=md5:stale&repository_url=https://pypi.example.com",
+	})
+	builder.AddNode("idna@3.6", &depgraph.PkgInfo{Name: "idna", Version: "3.6"})
+	depGraph := builder.Build()
+
+	AddChecksums(depGraph, map[string][]string{
+		ChecksumKey("requests", "2.31.0"):       {"sha256:aaa", "sha256:bbb"},
+		ChecksumKey("Mypy_Extensions", "1.0.0"): {"sha256:ccc"},
+		ChecksumKey("root", "0.0.0"):            {"sha256:ddd"},
+	})
+
+	purls := make(map[string]string)
+	for _, pkg := range depGraph.Pkgs {
+		purls[pkg.ID] = pkg.Info.PackageURL
+	}
+	assert.Equal(t, "pkg:pypi/requests@2.31.0?checksum=sha256%3Aaaa%2Csha256%3Abbb", purls["requests@2.31.0"])
+	assert.Equal(t,
+		"pkg:pypi/mypy-extensions@1.0.0?checksum=sha256%3Accc&repository_url=https%3A%2F%2Fpypi.example.com",
+		purls["mypy-extensions@1.0.0"])
+	assert.Empty(t, purls["idna@3.6"], "packages without checksums are left as is")
+	assert.Empty(t, depGraph.GetRootPkg().Info.PackageURL, "the root is never a locked package")
+}
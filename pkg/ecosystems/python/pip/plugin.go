@@ -24,6 +24,7 @@ import (
 const (
 	PluginName            = "pip"
 	requirementsFile      = "requirements.txt"
+	requirementsFileExt   = ".txt"
 	maxConcurrentInstalls = 5
 	logFieldFile          = "file"
 )
@@ -34,8 +35,11 @@ func (p Plugin) GetName() string {
 	return PluginName
 }
 
-// Compile-time check to ensure Plugin implements SCAPlugin interface.
-var _ ecosystems.SCAPlugin = (*Plugin)(nil)
+// Compile-time check to ensure Plugin implements SCAPlugin and ProjectDetector.
+var (
+	_ ecosystems.SCAPlugin       = (*Plugin)(nil)
+	_ ecosystems.ProjectDetector = (*Plugin)(nil)
+)
 
 // BuildDepGraphsFromDir discovers and builds dependency graphs for
 // Python pip projects. Build work runs concurrently (bounded by
@@ -62,7 +66,7 @@ func (p Plugin) BuildDepGraphsFromDir(
 	}
 
 	// Get Python runtime version
-	pythonVersion, err := GetPythonVersion()
+	pythonVersion, err := GetPythonVersion(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to detect Python version: %w", err)
 	}
@@ -114,13 +118,42 @@ func (p Plugin) BuildDepGraphsFromDir(
 	return nil
 }
 
+// DetectProjects reports one project per requirements.txt
+// BuildDepGraphsFromDir would install, without
+// running Python.
+func (p Plugin) DetectProjects(
+	ctx context.Context, _ logger.Logger, dir string, options *ecosystems.SCAPluginOptions,
+	onProject ecosystems.OnDetectFunc,
+) error {
+	files, err := p.discoverRequirementsFiles(ctx, dir, options)
+	if err != nil {
+		return fmt.Errorf("failed to discover requirements files: %w", err)
+	}
+
+	for _, file := range files {
+		if err := onProject(ecosystems.DetectedProject{
+			TargetFile:   file.RelPath,
+			ProjectType:  "pip",
+			ClaimedFiles: []string{file.RelPath},
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // discoverRequirementsFiles finds requirements.txt files based on the provided options.
 func (p Plugin) discoverRequirementsFiles(ctx context.Context, dir string, options *ecosystems.SCAPluginOptions) ([]discovery.FindResult, error) {
 	var findOpts []discovery.FindOption
 
 	switch {
 	case options.Global.TargetFile != nil:
-		// Use specific target file if provided
+		// Requirements files may carry any name (dev-requirements.txt,
+		// requirements/base.txt, ...), so only reject targets that clearly
+		// belong to another plugin.
+		if filepath.Ext(*options.Global.TargetFile) != requirementsFileExt {
+			return nil, nil
+		}
 		findOpts = []discovery.FindOption{
 			discovery.WithTargetFile(*options.Global.TargetFile),
 		}
@@ -128,14 +161,11 @@ func (p Plugin) discoverRequirementsFiles(ctx context.Context, dir string, optio
 		// Find all requirements.txt files recursively
 		// Exclude common directories to avoid scanning unnecessary paths
 		defaultExcludes := []string{".*", "__pycache__", "*.egg-info", "dist", "build", "venv"}
-		excludes := make([]string, 0, len(defaultExcludes)+len(options.Global.Exclude)+len(options.Global.ExcludePaths))
-		excludes = append(excludes, defaultExcludes...)
-		excludes = append(excludes, options.Global.Exclude...)
-		excludes = append(excludes, options.Global.ExcludePaths...)
 		findOpts = []discovery.FindOption{
 			discovery.WithInclude(requirementsFile),
-			discovery.WithExcludes(excludes...),
+			discovery.WithExcludes(defaultExcludes...),
 		}
+		findOpts = append(findOpts, options.DiscoveryOptions()...)
 	default:
 		// Default: find requirements.txt at root only
 		findOpts = []discovery.FindOption{
@@ -225,14 +255,19 @@ func (p Plugin) buildDepGraphFromFile(
 }
 
 // GetPythonVersion detects the installed Python version.
-func GetPythonVersion() (string, error) {
+func GetPythonVersion(ctx context.Context) (string, error) {
 	// Try python3 first (more common on Unix systems)
-	if version, err := execPythonVersion("python3"); err == nil {
+	version, err := execPythonVersion(ctx, "python3")
+	if err == nil {
 		return version, nil
 	}
+	var timeout *ecosystems.CommandTimeoutError
+	if errors.As(err, &timeout) {
+		return "", err
+	}
 
 	// Fall back to python (Windows or systems with python pointing to Python 3)
-	if version, err := execPythonVersion("python"); err == nil {
+	if version, err := execPythonVersion(ctx, "python"); err == nil {
 		return version, nil
 	}
 
@@ -243,11 +278,14 @@ func GetPythonVersion() (string, error) {
 }
 
 // execPythonVersion executes python --version and parses the output.
-func execPythonVersion(pythonCmd string) (string, error) {
-	cmd := exec.Command(pythonCmd, "--version") //nolint:noctx // No context available in this function
+func execPythonVersion(ctx context.Context, pythonCmd string) (string, error) {
+	cmdCtx, cancel := ecosystems.CommandContext(ctx, pythonCmd)
+	defer cancel()
+
+	cmd := exec.CommandContext(cmdCtx, pythonCmd, "--version")
 	output, err := cmd.Output()
 	if err != nil {
-		return "", fmt.Errorf("failed to execute %s --version: %w", pythonCmd, err)
+		return "", fmt.Errorf("failed to execute %s --version: %w", pythonCmd, ecosystems.CommandError(cmdCtx, err))
 	}
 
 	// Parse "Python 3.11.5" -> "3.11.5"
@@ -411,7 +411,7 @@ func loadExpectedResults(path string) ([]ecosystems.SCAResult, error) {
 
 // getPythonMajorMinorVersion returns the Python version as "X.Y" (e.g., "3.11")
 func getPythonMajorMinorVersion() (string, error) {
-	version, err := GetPythonVersion()
+	version, err := GetPythonVersion(context.Background())
 	if err != nil {
 		return "", err
 	}
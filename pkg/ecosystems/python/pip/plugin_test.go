@@ -97,3 +97,28 @@ func TestPlugin_DiscoverRequirementsFiles_HonorsExcludePaths(t *testing.T) {
 	assert.Contains(t, rels, "requirements.txt")
 	assert.Contains(t, rels, "b/requirements.txt")
 }
+
+// TestPlugin_DiscoverRequirementsFiles_HonorsDetectionDepth locks in that the pip plugin passes
+// `opts.Global.DetectionDepth` to discovery, so --detection-depth finds the same
+// projects as it does in the legacy CLI.
+func TestPlugin_DiscoverRequirementsFiles_HonorsDetectionDepth(t *testing.T) {
+	tmpDir := t.TempDir()
+	for _, rel := range []string{"requirements.txt", "a/requirements.txt", "a/b/requirements.txt"} {
+		full := filepath.Join(tmpDir, rel)
+		require.NoError(t, os.MkdirAll(filepath.Dir(full), 0o755))
+		require.NoError(t, os.WriteFile(full, []byte(""), 0o644))
+	}
+
+	opts := ecosystems.NewPluginOptions().
+		WithAllProjects(true).
+		WithDetectionDepth(2)
+
+	got, err := Plugin{}.discoverRequirementsFiles(t.Context(), tmpDir, opts)
+	require.NoError(t, err)
+
+	rels := make([]string, len(got))
+	for i, r := range got {
+		rels[i] = filepath.ToSlash(r.RelPath)
+	}
+	assert.ElementsMatch(t, []string{"requirements.txt", "a/requirements.txt"}, rels)
+}
@@ -10,10 +10,11 @@ import (
 	"os/exec"
 	"strings"
 
-	"github.com/snyk/error-catalog-golang-public/opensource/ecosystems"
+	snyk_ecosystems "github.com/snyk/error-catalog-golang-public/opensource/ecosystems"
 	"github.com/snyk/error-catalog-golang-public/snyk"
 	"github.com/snyk/error-catalog-golang-public/snyk_errors"
 
+	"github.com/snyk/cli-extension-dep-graph/v2/pkg/ecosystems"
 	"github.com/snyk/cli-extension-dep-graph/v2/pkg/ecosystems/logger"
 )
 
@@ -163,7 +164,10 @@ type DefaultExecutor struct{}
 
 // Execute runs a command with optional stdin input and returns its stdout output.
 func (e *DefaultExecutor) Execute(ctx context.Context, stdin, name string, args ...string) ([]byte, error) {
-	cmd := exec.CommandContext(ctx, name, args...)
+	cmdCtx, cancel := ecosystems.CommandContext(ctx, name)
+	defer cancel()
+
+	cmd := exec.CommandContext(cmdCtx, name, args...)
 
 	var stdout bytes.Buffer
 	var stderr bytes.Buffer
@@ -176,7 +180,7 @@ func (e *DefaultExecutor) Execute(ctx context.Context, stdin, name string, args
 
 	if err := cmd.Run(); err != nil {
 		return nil, &pipError{
-			err:    err,
+			err:    ecosystems.CommandError(cmdCtx, err),
 			stderr: stderr.String(),
 		}
 	}
@@ -228,7 +232,7 @@ func classifyPipError(ctx context.Context, log logger.Logger, err error) error {
 		strings.Contains(stderr, "invalid requirement") ||
 		strings.Contains(stderr, "InvalidVersion") ||
 		strings.Contains(stderr, "Invalid version") {
-		return ecosystems.NewSyntaxIssuesError(
+		return snyk_ecosystems.NewSyntaxIssuesError(
 			fmt.Sprintf("Invalid syntax in requirements file: %s", stderr),
 			snyk_errors.WithCause(errors.New(stderr)),
 		)
@@ -238,7 +242,7 @@ func classifyPipError(ctx context.Context, log logger.Logger, err error) error {
 	if strings.Contains(stderr, "Could not find a version") ||
 		strings.Contains(stderr, "No matching distribution") ||
 		strings.Contains(stderr, "Could not find a version that satisfies") {
-		return ecosystems.NewPythonPackageNotFoundError(
+		return snyk_ecosystems.NewPythonPackageNotFoundError(
 			fmt.Sprintf("Package not found: %s", stderr),
 			snyk_errors.WithCause(errors.New(stderr)),
 		)
@@ -247,7 +251,7 @@ func classifyPipError(ctx context.Context, log logger.Logger, err error) error {
 	// Check for Python version mismatch
 	if strings.Contains(stderr, "requires Python") ||
 		strings.Contains(stderr, "Requires-Python") {
-		return ecosystems.NewPipUnsupportedPythonVersionError(
+		return snyk_ecosystems.NewPipUnsupportedPythonVersionError(
 			fmt.Sprintf("Python version mismatch: %s", stderr),
 			snyk_errors.WithCause(errors.New(stderr)),
 		)
@@ -257,7 +261,7 @@ func classifyPipError(ctx context.Context, log logger.Logger, err error) error {
 	if strings.Contains(stderr, "Conflict") ||
 		strings.Contains(stderr, "conflicting") ||
 		strings.Contains(stderr, "incompatible") {
-		return ecosystems.NewPythonVersionConfictError(
+		return snyk_ecosystems.NewPythonVersionConfictError(
 			fmt.Sprintf("Conflicting package requirements: %s", stderr),
 			snyk_errors.WithCause(errors.New(stderr)),
 		)
@@ -271,13 +275,13 @@ func classifyPipError(ctx context.Context, log logger.Logger, err error) error {
 				logger.Attr("package", pkgName),
 				logger.Attr("full_stderr", stderr))
 		}
-		return ecosystems.NewInstallationFailureError(
+		return snyk_ecosystems.NewInstallationFailureError(
 			fmt.Sprintf("Failed to install package '%s'. Check that the package version is compatible with your current Python version.", pkgName),
 			snyk_errors.WithCause(errors.New(stderr)),
 		)
 	}
 
-	return ecosystems.NewInstallationFailureError(
+	return snyk_ecosystems.NewInstallationFailureError(
 		fmt.Sprintf("Pip install failed: %s", stderr),
 		snyk_errors.WithCause(errors.New(stderr)),
 	)
@@ -3,8 +3,12 @@ package pipenv
 import (
 	"encoding/json"
 	"fmt"
+	"io/fs"
 	"os"
+	"path/filepath"
 	"strings"
+
+	"github.com/snyk/cli-extension-dep-graph/v2/pkg/ecosystems/python/pip"
 )
 
 // PipfileLock represents the structure of a Pipfile.lock file.
@@ -53,7 +57,12 @@ type LockedPackage struct {
 
 // ParsePipfileLock reads and parses a Pipfile.lock from the given path.
 func ParsePipfileLock(path string) (*PipfileLock, error) {
-	data, err := os.ReadFile(path)
+	return ParsePipfileLockFS(os.DirFS(filepath.Dir(path)), filepath.Base(path))
+}
+
+// ParsePipfileLockFS reads and parses the Pipfile.lock named name in fsys.
+func ParsePipfileLockFS(fsys fs.FS, name string) (*PipfileLock, error) {
+	data, err := fs.ReadFile(fsys, name)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read Pipfile.lock: %w", err)
 	}
@@ -94,6 +103,22 @@ func (l *PipfileLock) ToConstraints(includeDevDeps bool) []string {
 	return constraints
 }
 
+// Checksums returns the hashes recorded for every locked package, keyed by
+// pip.ChecksumKey, for pip.AddChecksums.
+func (l *PipfileLock) Checksums() map[string][]string {
+	checksums := make(map[string][]string)
+	for _, packages := range []map[string]LockedPackage{l.Default, l.Develop} {
+		for name, pkg := range packages {
+			version := strings.TrimPrefix(pkg.Version, "==")
+			if version == "" || len(pkg.Hashes) == 0 {
+				continue
+			}
+			checksums[pip.ChecksumKey(name, version)] = pkg.Hashes
+		}
+	}
+	return checksums
+}
+
 // formatConstraint converts a locked package to constraints format.
 func formatConstraint(name string, pkg *LockedPackage) string {
 	// Handle git dependencies with pip-compatible git URL format
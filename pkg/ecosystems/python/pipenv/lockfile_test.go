@@ -4,9 +4,11 @@
 package pipenv
 
 import (
+	"io/fs"
 	"os"
 	"path/filepath"
 	"testing"
+	"testing/fstest"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -222,6 +224,19 @@ func TestPipfileLock_ToConstraints(t *testing.T) {
 	}
 }
 
+func TestParsePipfileLockFS(t *testing.T) {
+	fsys := fstest.MapFS{
+		"app/Pipfile.lock": {Data: []byte(`{"default": {"requests": {"version": "==2.28.0"}}, "develop": {}}`)},
+	}
+
+	lf, err := ParsePipfileLockFS(fsys, "app/Pipfile.lock")
+	require.NoError(t, err)
+	assert.Equal(t, "==2.28.0", lf.Default["requests"].Version)
+
+	_, err = ParsePipfileLockFS(fsys, "Pipfile.lock")
+	assert.ErrorIs(t, err, fs.ErrNotExist)
+}
+
 func TestParsePipfileLock_FileNotFound(t *testing.T) {
 	_, err := ParsePipfileLock("/nonexistent/path/Pipfile.lock")
 	assert.Error(t, err)
@@ -238,3 +253,21 @@ func TestParsePipfileLock_InvalidJSON(t *testing.T) {
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "failed to parse Pipfile.lock")
 }
+
+func TestPipfileLock_Checksums(t *testing.T) {
+	lockfile := &PipfileLock{
+		Default: map[string]LockedPackage{
+			"Requests":  {Version: "==2.28.0", Hashes: []string{"sha256:abc", "sha256:def"}},
+			"local-pkg": {Path: ".", Hashes: []string{"sha256:ignored"}},
+			"unhashed":  {Version: "==1.0.0"},
+		},
+		Develop: map[string]LockedPackage{
+			"pytest": {Version: "==7.0.0", Hashes: []string{"sha256:ghi"}},
+		},
+	}
+
+	assert.Equal(t, map[string][]string{
+		"requests@2.28.0": {"sha256:abc", "sha256:def"},
+		"pytest@7.0.0":    {"sha256:ghi"},
+	}, lockfile.Checksums())
+}
@@ -2,7 +2,9 @@ package pipenv
 
 import (
 	"fmt"
+	"io/fs"
 	"os"
+	"path/filepath"
 	"runtime"
 	"strings"
 
@@ -38,7 +40,12 @@ type PipfileRequiresPythonVer struct {
 
 // ParsePipfile reads and parses a Pipfile from the given path.
 func ParsePipfile(path string) (*Pipfile, error) {
-	data, err := os.ReadFile(path)
+	return ParsePipfileFS(os.DirFS(filepath.Dir(path)), filepath.Base(path))
+}
+
+// ParsePipfileFS reads and parses the Pipfile named name in fsys.
+func ParsePipfileFS(fsys fs.FS, name string) (*Pipfile, error) {
+	data, err := fs.ReadFile(fsys, name)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read Pipfile: %w", err)
 	}
@@ -4,9 +4,11 @@
 package pipenv
 
 import (
+	"io/fs"
 	"os"
 	"path/filepath"
 	"testing"
+	"testing/fstest"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -166,6 +168,20 @@ func TestMatchesPlatformMarker(t *testing.T) {
 	}
 }
 
+func TestParsePipfileFS(t *testing.T) {
+	fsys := fstest.MapFS{
+		"app/Pipfile": {Data: []byte("[packages]\nrequests = \"==2.28.0\"\n\n[requires]\npython_version = \"3.11\"\n")},
+	}
+
+	pf, err := ParsePipfileFS(fsys, "app/Pipfile")
+	require.NoError(t, err)
+	assert.Equal(t, "==2.28.0", pf.Packages["requests"])
+	assert.Equal(t, "3.11", pf.Requires.PythonVersion)
+
+	_, err = ParsePipfileFS(fsys, "Pipfile")
+	assert.ErrorIs(t, err, fs.ErrNotExist)
+}
+
 func TestParsePipfile_FileNotFound(t *testing.T) {
 	_, err := ParsePipfile("/nonexistent/path/Pipfile")
 	assert.Error(t, err)
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io/fs"
 	"os"
 	"path/filepath"
 	"sync"
@@ -26,6 +27,7 @@ const (
 	PluginName            = "pipenv"
 	pipfileFile           = "Pipfile"
 	pipfileLockFile       = "Pipfile.lock"
+	requirementsFile      = "requirements.txt"
 	maxConcurrentInstalls = 5
 	logFieldFile          = "file"
 	pythonRuntimeFmt      = "python@%s"
@@ -33,8 +35,11 @@ const (
 
 type Plugin struct{}
 
-// Compile-time check to ensure Plugin implements SCAPlugin interface.
-var _ ecosystems.SCAPlugin = (*Plugin)(nil)
+// Compile-time check to ensure Plugin implements SCAPlugin and ProjectDetector.
+var (
+	_ ecosystems.SCAPlugin       = (*Plugin)(nil)
+	_ ecosystems.ProjectDetector = (*Plugin)(nil)
+)
 
 func (p Plugin) GetName() string {
 	return PluginName
@@ -64,7 +69,7 @@ func (p Plugin) BuildDepGraphsFromDir(
 	}
 
 	// Get Python runtime version
-	pythonVersion, err := pip.GetPythonVersion()
+	pythonVersion, err := pip.GetPythonVersion(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to detect Python version: %w", err)
 	}
@@ -78,7 +83,7 @@ func (p Plugin) BuildDepGraphsFromDir(
 		g.Go(func() error {
 			projectName := pip.GetProjectName(file.RelPath, dir, options.Global.ProjectName)
 			result, err := p.buildDepGraphFromPipfile(ctx, log, file, pythonVersion, options.Python.NoBuildIsolation,
-				options.Global.IncludeDev, projectName)
+				options.Global.IncludeDev, options.Global.IncludeProvenance, projectName)
 			if err != nil {
 				attrs := []logger.Field{
 					logger.Attr(logFieldFile, file.RelPath),
@@ -102,8 +107,12 @@ func (p Plugin) BuildDepGraphsFromDir(
 					Error: err,
 				}
 			}
-			if tf := result.ProjectDescriptor.GetTargetFile(); tf != "" {
-				result.ProcessedFiles = []string{tf}
+			if result.Error != nil {
+				// Leave a sibling requirements.txt to pip and the legacy
+				// fallback, as this project produced no graph.
+				result.ProcessedFiles = []string{file.RelPath}
+			} else {
+				result.ProcessedFiles = processedFiles(os.DirFS(dir), file.RelPath)
 			}
 
 			emitMu.Lock()
@@ -118,27 +127,69 @@ func (p Plugin) BuildDepGraphsFromDir(
 	return nil
 }
 
+// DetectProjects reports one project per Pipfile
+// BuildDepGraphsFromDir would resolve, without
+// running Python.
+func (p Plugin) DetectProjects(
+	ctx context.Context, _ logger.Logger, dir string, options *ecosystems.SCAPluginOptions,
+	onProject ecosystems.OnDetectFunc,
+) error {
+	files, err := p.discoverPipfiles(ctx, dir, options)
+	if err != nil {
+		return fmt.Errorf("failed to discover Pipfiles: %w", err)
+	}
+
+	fsys := os.DirFS(dir)
+	for _, file := range files {
+		if err := onProject(ecosystems.DetectedProject{
+			TargetFile:   file.RelPath,
+			ProjectType:  "pip",
+			ClaimedFiles: processedFiles(fsys, file.RelPath),
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// processedFiles lists the files a Pipfile project claims: the Pipfile, and
+// the lock file and requirements.txt next to it when they exist in fsys, the
+// scan directory. Claiming the sibling requirements.txt is what makes
+// Pipfile.lock take precedence over the pip plugin for the same directory.
+func processedFiles(fsys fs.FS, pipfilePath string) []string {
+	files := []string{pipfilePath}
+	dir := filepath.Dir(pipfilePath)
+	for _, name := range []string{pipfileLockFile, requirementsFile} {
+		sibling := filepath.Join(dir, name)
+		if info, err := fs.Stat(fsys, filepath.ToSlash(sibling)); err == nil && !info.IsDir() {
+			files = append(files, sibling)
+		}
+	}
+	return files
+}
+
 // discoverPipfiles finds Pipfile files based on the provided options.
 func (p Plugin) discoverPipfiles(ctx context.Context, dir string, options *ecosystems.SCAPluginOptions) ([]discovery.FindResult, error) {
 	var findOpts []discovery.FindOption
 
 	switch {
 	case options.Global.TargetFile != nil:
-		// Use specific target file if provided
+		// Only a Pipfile target is ours to handle; leave anything else
+		// (requirements.txt, uv.lock, ...) to the other Python plugins.
+		if filepath.Base(*options.Global.TargetFile) != pipfileFile {
+			return nil, nil
+		}
 		findOpts = []discovery.FindOption{
 			discovery.WithTargetFile(*options.Global.TargetFile),
 		}
 	case options.Global.AllProjects:
 		// Find all Pipfile files recursively
 		defaultExcludes := []string{".*", "__pycache__", "*.egg-info", "dist", "build", "venv"}
-		excludes := make([]string, 0, len(defaultExcludes)+len(options.Global.Exclude)+len(options.Global.ExcludePaths))
-		excludes = append(excludes, defaultExcludes...)
-		excludes = append(excludes, options.Global.Exclude...)
-		excludes = append(excludes, options.Global.ExcludePaths...)
 		findOpts = []discovery.FindOption{
 			discovery.WithInclude(pipfileFile),
-			discovery.WithExcludes(excludes...),
+			discovery.WithExcludes(defaultExcludes...),
 		}
+		findOpts = append(findOpts, options.DiscoveryOptions()...)
 	default:
 		// Default: find Pipfile at root only
 		findOpts = []discovery.FindOption{
@@ -161,6 +212,7 @@ func (p Plugin) buildDepGraphFromPipfile(
 	pythonVersion string,
 	noBuildIsolation bool,
 	includeDevDeps bool,
+	includeProvenance bool,
 	projectName string,
 ) (ecosystems.SCAResult, error) {
 	log.Debug(ctx, "Building dependency graph from Pipfile",
@@ -169,25 +221,26 @@ func (p Plugin) buildDepGraphFromPipfile(
 		logger.Attr("project_name", projectName))
 
 	// Parse Pipfile
-	pipfile, err := ParsePipfile(file.Path)
+	pipfileDir := filepath.Dir(file.Path)
+	fsys := os.DirFS(pipfileDir)
+	pipfile, err := ParsePipfileFS(fsys, filepath.Base(file.Path))
 	if err != nil {
 		return ecosystems.SCAResult{}, fmt.Errorf("failed to parse Pipfile: %w", err)
 	}
 
 	// Check for Pipfile.lock in the same directory
-	pipfileDir := filepath.Dir(file.Path)
 	lockfilePath := filepath.Join(pipfileDir, pipfileLockFile)
 
 	var lockfile *PipfileLock
-	if _, statErr := os.Stat(lockfilePath); statErr == nil {
-		lockfile, err = ParsePipfileLock(lockfilePath)
+	if _, statErr := fs.Stat(fsys, pipfileLockFile); statErr == nil {
+		lockfile, err = ParsePipfileLockFS(fsys, pipfileLockFile)
 		if err != nil {
 			return ecosystems.SCAResult{}, snykecosystems.NewUnparseableLockFileError(
 				fmt.Sprintf("Failed to parse Pipfile.lock: %v", err),
 				snyk_errors.WithCause(err),
 			)
 		}
-	} else if os.IsNotExist(statErr) {
+	} else if errors.Is(statErr, fs.ErrNotExist) {
 		return ecosystems.SCAResult{}, prchecks.NewManifestNotFoundError(
 			fmt.Sprintf("Pipfile.lock not found at %s. Run 'pipenv lock' to generate it.", lockfilePath))
 	} else {
@@ -206,6 +259,10 @@ func (p Plugin) buildDepGraphFromPipfile(
 		return ecosystems.SCAResult{}, fmt.Errorf("failed to convert pip report to dependency graph: %w", err)
 	}
 
+	if includeProvenance {
+		pip.AddChecksums(depGraph, lockfile.Checksums())
+	}
+
 	log.Info(ctx, "Successfully built dependency graph from Pipfile",
 		logger.Attr(logFieldFile, file.RelPath))
 
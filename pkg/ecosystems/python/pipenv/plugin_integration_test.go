@@ -390,7 +390,7 @@ func substituteWildcards(v any, nameToVersion map[string]string) {
 
 // getPythonMajorMinorVersion returns the Python version as "X.Y" (e.g., "3.11")
 func getPythonMajorMinorVersion() (string, error) {
-	version, err := pip.GetPythonVersion()
+	version, err := pip.GetPythonVersion(context.Background())
 	if err != nil {
 		return "", err
 	}
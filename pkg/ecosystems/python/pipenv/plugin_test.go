@@ -5,13 +5,17 @@ package pipenv
 
 import (
 	"os"
+	"os/exec"
 	"path/filepath"
 	"testing"
+	"testing/fstest"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
 	"github.com/snyk/cli-extension-dep-graph/v2/pkg/ecosystems"
+	"github.com/snyk/cli-extension-dep-graph/v2/pkg/ecosystems/logger"
+	"github.com/snyk/cli-extension-dep-graph/v2/pkg/ecosystems/scatest"
 )
 
 // TestPlugin_DiscoverPipfiles_HonorsExcludePaths locks in that the pipenv plugin reads
@@ -41,3 +45,123 @@ func TestPlugin_DiscoverPipfiles_HonorsExcludePaths(t *testing.T) {
 	assert.Contains(t, rels, "Pipfile")
 	assert.Contains(t, rels, "b/Pipfile")
 }
+
+// TestPlugin_DiscoverPipfiles_HonorsDetectionDepth locks in that the pipenv plugin passes
+// `opts.Global.DetectionDepth` to discovery, so --detection-depth finds the same
+// projects as it does in the legacy CLI.
+func TestPlugin_DiscoverPipfiles_HonorsDetectionDepth(t *testing.T) {
+	tmpDir := t.TempDir()
+	for _, rel := range []string{"Pipfile", "a/Pipfile", "a/b/Pipfile"} {
+		full := filepath.Join(tmpDir, rel)
+		require.NoError(t, os.MkdirAll(filepath.Dir(full), 0o755))
+		require.NoError(t, os.WriteFile(full, []byte(""), 0o644))
+	}
+
+	opts := ecosystems.NewPluginOptions().
+		WithAllProjects(true).
+		WithDetectionDepth(2)
+
+	got, err := Plugin{}.discoverPipfiles(t.Context(), tmpDir, opts)
+	require.NoError(t, err)
+
+	rels := make([]string, len(got))
+	for i, r := range got {
+		rels[i] = filepath.ToSlash(r.RelPath)
+	}
+	assert.ElementsMatch(t, []string{"Pipfile", "a/Pipfile"}, rels)
+}
+
+// TestPlugin_DiscoverPipfiles_IgnoresForeignTargetFile locks in that a --file pointing
+// at another Python manifest is left to the plugin that owns it.
+func TestPlugin_DiscoverPipfiles_IgnoresForeignTargetFile(t *testing.T) {
+	tmpDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(tmpDir, "requirements.txt"), []byte(""), 0o644))
+
+	opts := ecosystems.NewPluginOptions().WithTargetFile("requirements.txt")
+
+	got, err := Plugin{}.discoverPipfiles(t.Context(), tmpDir, opts)
+	require.NoError(t, err)
+	assert.Empty(t, got)
+}
+
+// TestProcessedFiles_ClaimsSiblingRequirements locks in that a Pipfile project claims
+// the requirements.txt next to it, so the pip plugin does not resolve it a second time.
+func TestProcessedFiles_ClaimsSiblingRequirements(t *testing.T) {
+	fsys := fstest.MapFS{
+		"Pipfile":              {},
+		"Pipfile.lock":         {},
+		"requirements.txt":     {},
+		"svc/Pipfile":          {},
+		"svc/Pipfile.lock":     {},
+		"svc/requirements.txt": {},
+	}
+	assert.Equal(t,
+		[]string{filepath.Join("svc", "Pipfile"), filepath.Join("svc", "Pipfile.lock"), filepath.Join("svc", "requirements.txt")},
+		processedFiles(fsys, filepath.Join("svc", "Pipfile")))
+	assert.Equal(t,
+		[]string{"Pipfile", "Pipfile.lock", "requirements.txt"},
+		processedFiles(fsys, "Pipfile"))
+}
+
+// TestProcessedFiles_SkipsMissingSiblings locks in that only sibling files that
+// exist are claimed, so detection reports no overlaps on files that are absent.
+func TestProcessedFiles_SkipsMissingSiblings(t *testing.T) {
+	fsys := fstest.MapFS{
+		"Pipfile":                      {},
+		"svc/Pipfile":                  {},
+		"svc/Pipfile.lock":             {},
+		"svc/requirements.txt/ignored": {},
+	}
+	assert.Equal(t, []string{"Pipfile"}, processedFiles(fsys, "Pipfile"))
+	assert.Equal(t,
+		[]string{filepath.Join("svc", "Pipfile"), filepath.Join("svc", "Pipfile.lock")},
+		processedFiles(fsys, filepath.Join("svc", "Pipfile")))
+}
+
+// TestPlugin_ErroredProjectClaimsOnlyPipfile locks in that a Pipfile project
+// that fails to resolve leaves its requirements.txt to the pip plugin and the
+// legacy fallback, so the directory is still scanned.
+func TestPlugin_ErroredProjectClaimsOnlyPipfile(t *testing.T) {
+	if _, err := exec.LookPath("python3"); err != nil {
+		if _, err := exec.LookPath("python"); err != nil {
+			t.Skip("python is not installed")
+		}
+	}
+	tmpDir := t.TempDir()
+	files := map[string]string{
+		"Pipfile":          "[packages]\nrequests = \"*\"\n",
+		"Pipfile.lock":     "{not json",
+		"requirements.txt": "requests\n",
+	}
+	for name, content := range files {
+		require.NoError(t, os.WriteFile(filepath.Join(tmpDir, name), []byte(content), 0o644))
+	}
+
+	results, err := scatest.Run(t.Context(), Plugin{}, logger.Nop(), tmpDir, ecosystems.NewPluginOptions())
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	require.Error(t, results[0].Error, "a malformed Pipfile.lock fails the project")
+	assert.Equal(t, []string{"Pipfile"}, results[0].ProcessedFiles)
+}
+
+// TestPlugin_DetectProjects_ClaimsLikeBuild locks in that detection reports the
+// same claimed files as a real run, so overlap reporting matches precedence.
+func TestPlugin_DetectProjects_ClaimsLikeBuild(t *testing.T) {
+	tmpDir := t.TempDir()
+	for _, rel := range []string{"Pipfile", "svc/Pipfile", "svc/Pipfile.lock"} {
+		full := filepath.Join(tmpDir, rel)
+		require.NoError(t, os.MkdirAll(filepath.Dir(full), 0o755))
+		require.NoError(t, os.WriteFile(full, []byte(""), 0o644))
+	}
+
+	got, err := scatest.Detect(t.Context(), Plugin{}, logger.Nop(), tmpDir, ecosystems.NewPluginOptions().WithAllProjects(true))
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []ecosystems.DetectedProject{
+		{TargetFile: "Pipfile", ProjectType: "pip", ClaimedFiles: []string{"Pipfile"}},
+		{
+			TargetFile:   filepath.Join("svc", "Pipfile"),
+			ProjectType:  "pip",
+			ClaimedFiles: []string{filepath.Join("svc", "Pipfile"), filepath.Join("svc", "Pipfile.lock")},
+		},
+	}, got)
+}
@@ -0,0 +1,75 @@
+package uv
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/BurntSushi/toml"
+
+	scaecosystems "github.com/snyk/cli-extension-dep-graph/v2/pkg/ecosystems"
+	"github.com/snyk/cli-extension-dep-graph/v2/pkg/ecosystems/logger"
+	"github.com/snyk/cli-extension-dep-graph/v2/pkg/ecosystems/python/pip"
+)
+
+// uvLock holds the parts of uv.lock needed to recover package checksums,
+// which uv's CycloneDX export leaves out.
+type uvLock struct {
+	Packages []uvLockPackage `toml:"package"`
+}
+
+type uvLockPackage struct {
+	Name    string           `toml:"name"`
+	Version string           `toml:"version"`
+	Sdist   *uvLockArtifact  `toml:"sdist"`
+	Wheels  []uvLockArtifact `toml:"wheels"`
+}
+
+type uvLockArtifact struct {
+	Hash string `toml:"hash"`
+}
+
+// readLockChecksums returns the sdist and wheel hashes uv.lock records for
+// each package, keyed by pip.ChecksumKey.
+func readLockChecksums(path string) (map[string][]string, error) {
+	var lock uvLock
+	if _, err := toml.DecodeFile(path, &lock); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+
+	checksums := make(map[string][]string)
+	for _, pkg := range lock.Packages {
+		var hashes []string
+		if pkg.Sdist != nil && pkg.Sdist.Hash != "" {
+			hashes = append(hashes, pkg.Sdist.Hash)
+		}
+		for _, wheel := range pkg.Wheels {
+			if wheel.Hash != "" {
+				hashes = append(hashes, wheel.Hash)
+			}
+		}
+		if len(hashes) > 0 {
+			checksums[pip.ChecksumKey(pkg.Name, pkg.Version)] = hashes
+		}
+	}
+	return checksums, nil
+}
+
+// withLockChecksums returns an OnGraphFunc that records the checksums from
+// the uv.lock at lockFilePath on each dep-graph before passing it on. An
+// unreadable lockfile only costs the checksums, not the results.
+func withLockChecksums(
+	ctx context.Context,
+	log logger.Logger,
+	lockFilePath string,
+	onGraph scaecosystems.OnGraphFunc,
+) scaecosystems.OnGraphFunc {
+	checksums, err := readLockChecksums(lockFilePath)
+	if err != nil {
+		log.Info(ctx, "Failed to read checksums from uv.lock", logger.Attr("lockFile", lockFilePath), logger.Err(err))
+		return onGraph
+	}
+	return func(result scaecosystems.SCAResult) error {
+		pip.AddChecksums(result.DepGraph, checksums)
+		return onGraph(result)
+	}
+}
@@ -0,0 +1,87 @@
+package uv
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/snyk/dep-graph/go/pkg/depgraph"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/snyk/cli-extension-dep-graph/v2/pkg/ecosystems"
+	"github.com/snyk/cli-extension-dep-graph/v2/pkg/ecosystems/scatest"
+)
+
+const checksumsUvLock = `version = 1
+requires-python = ">=3.12"
+
+[[package]]
+name = "mock-project"
+version = "1.0.0"
+source = { virtual = "." }
+
+[[package]]
+name = "django"
+version = "3.1"
+source = { registry = "https://pypi.org/simple" }
+sdist = { url = "https://files.pythonhosted.org/django-3.1.tar.gz", hash = "sha256:aaa", size = 1 }
+wheels = [
+    { url = "https://files.pythonhosted.org/django-3.1-py3-none-any.whl", hash = "sha256:bbb", size = 1 },
+]
+
+[[package]]
+name = "idna"
+version = "3.6"
+source = { registry = "https://pypi.org/simple" }
+`
+
+func writeChecksumsUvLock(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, LockFileName), []byte(checksumsUvLock), 0o600))
+	return dir
+}
+
+func TestReadLockChecksums(t *testing.T) {
+	dir := writeChecksumsUvLock(t)
+
+	got, err := readLockChecksums(filepath.Join(dir, LockFileName))
+
+	require.NoError(t, err)
+	assert.Equal(t, map[string][]string{"django@3.1": {"sha256:aaa", "sha256:bbb"}}, got)
+}
+
+func TestPlugin_IncludeProvenanceAddsLockChecksums(t *testing.T) {
+	dir := writeChecksumsUvLock(t)
+	newGraph := func() *depgraph.DepGraph {
+		builder, err := depgraph.NewBuilder(&depgraph.PkgManager{Name: "uv"}, &depgraph.PkgInfo{Name: "mock-project", Version: "1.0.0"})
+		require.NoError(t, err)
+		builder.AddNode("django@3.1", &depgraph.PkgInfo{Name: "django", Version: "3.1", PackageURL: "pkg:pypi/django@3.1"})
+		require.NoError(t, builder.ConnectNodes(builder.GetRootNode().NodeID, "django@3.1"))
+		return builder.Build()
+	}
+	purlOf := func(results []ecosystems.SCAResult) string {
+		require.Len(t, results, 1)
+		for _, pkg := range results[0].DepGraph.Pkgs {
+			if pkg.Info.Name == "django" {
+				return pkg.Info.PackageURL
+			}
+		}
+		return ""
+	}
+
+	t.Run("with provenance", func(t *testing.T) {
+		plugin := NewPlugin(&MockClient{}, mockConverter(newGraph()), "")
+		results, err := scatest.Run(t.Context(), plugin, testLogger, dir, ecosystems.NewPluginOptions().WithIncludeProvenance(true))
+		require.NoError(t, err)
+		assert.Equal(t, "pkg:pypi/django@3.1?checksum=sha256%3Aaaa%2Csha256%3Abbb", purlOf(results))
+	})
+
+	t.Run("without provenance", func(t *testing.T) {
+		plugin := NewPlugin(&MockClient{}, mockConverter(newGraph()), "")
+		results, err := scatest.Run(t.Context(), plugin, testLogger, dir, ecosystems.NewPluginOptions())
+		require.NoError(t, err)
+		assert.Equal(t, "pkg:pypi/django@3.1", purlOf(results))
+	})
+}
@@ -2,6 +2,7 @@ package uv
 
 import (
 	"bytes"
+	"context"
 	"fmt"
 	"os/exec"
 	"regexp"
@@ -11,19 +12,21 @@ import (
 	clierrors "github.com/snyk/error-catalog-golang-public/cli"
 	snykerrors "github.com/snyk/error-catalog-golang-public/snyk"
 	"github.com/snyk/error-catalog-golang-public/snyk_errors"
+
+	"github.com/snyk/cli-extension-dep-graph/v2/pkg/ecosystems"
 )
 
 var minVersion = Version{0, 9, 29}
 
 // cmdExecutor interface for executing commands mockable.
 type cmdExecutor interface {
-	Execute(binary, dir string, args ...string) ([]byte, error)
+	Execute(ctx context.Context, binary, dir string, args ...string) ([]byte, error)
 }
 
 // uvCmdExecutor is the cmdExecutor implementation for the uv command, handling its execution and output capture.
 type uvCmdExecutor struct{}
 
-func (e *uvCmdExecutor) Execute(binary, dir string, args ...string) ([]byte, error) {
+func (e *uvCmdExecutor) Execute(ctx context.Context, binary, dir string, args ...string) ([]byte, error) {
 	// Check if uv binary exists in PATH and resolve the full path
 	resolvedBinary, err := exec.LookPath(binary)
 	if err != nil {
@@ -34,18 +37,21 @@ func (e *uvCmdExecutor) Execute(binary, dir string, args ...string) ([]byte, err
 	}
 
 	//nolint:govet // Reassigning to err is fine
-	if err := checkVersion(resolvedBinary); err != nil {
+	if err := checkVersion(ctx, resolvedBinary); err != nil {
 		return nil, err
 	}
 
-	cmd := exec.Command(resolvedBinary, args...) //nolint:noctx // No context available in this function
+	cmdCtx, cancel := ecosystems.CommandContext(ctx, binary)
+	defer cancel()
+
+	cmd := exec.CommandContext(cmdCtx, resolvedBinary, args...)
 	cmd.Dir = dir
 
 	var stdout, stderr bytes.Buffer
 	cmd.Stdout = &stdout
 	cmd.Stderr = &stderr
 
-	err = cmd.Run()
+	err = ecosystems.CommandError(cmdCtx, cmd.Run())
 	if err != nil {
 		return nil, clierrors.NewGeneralSCAFailureError(
 			fmt.Sprintf("failed to execute uv export command: %v\nstdout: %s\nstderr: %s", err, stdout.String(), stderr.String()),
@@ -55,9 +61,13 @@ func (e *uvCmdExecutor) Execute(binary, dir string, args ...string) ([]byte, err
 	return stdout.Bytes(), nil
 }
 
-func checkVersion(binary string) error {
-	cmd := exec.Command(binary, "--version") //nolint:noctx // No context available in this function
+func checkVersion(ctx context.Context, binary string) error {
+	cmdCtx, cancel := ecosystems.CommandContext(ctx, binary)
+	defer cancel()
+
+	cmd := exec.CommandContext(cmdCtx, binary, "--version")
 	output, err := cmd.Output()
+	err = ecosystems.CommandError(cmdCtx, err)
 	if err != nil {
 		return clierrors.NewGeneralSCAFailureError(
 			fmt.Sprintf("failed to get %s version\noutput: %s", binary, string(output)),
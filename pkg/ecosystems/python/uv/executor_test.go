@@ -38,7 +38,7 @@ func TestDefaultCmdExecutor(t *testing.T) {
 		executor := &uvCmdExecutor{}
 
 		expectedOutput := `{"bomFormat":"CycloneDX","specVersion":"1.5"}`
-		output, err := executor.Execute(helperBin, ".", "-stdout", expectedOutput)
+		output, err := executor.Execute(t.Context(), helperBin, ".", "-stdout", expectedOutput)
 
 		require.NoError(t, err)
 		assert.Equal(t, expectedOutput, string(output))
@@ -48,7 +48,7 @@ func TestDefaultCmdExecutor(t *testing.T) {
 		executor := &uvCmdExecutor{}
 
 		stderrContent := "warning: some deprecation notice"
-		output, err := executor.Execute(helperBin, ".", "-stderr", stderrContent)
+		output, err := executor.Execute(t.Context(), helperBin, ".", "-stderr", stderrContent)
 
 		require.NoError(t, err)
 		assert.Empty(t, output)
@@ -60,7 +60,7 @@ func TestDefaultCmdExecutor(t *testing.T) {
 		stdoutContent := `{"valid":"json"}`
 		stderrContent := "warning: something"
 
-		output, err := executor.Execute(helperBin, ".",
+		output, err := executor.Execute(t.Context(), helperBin, ".",
 			"-stdout", stdoutContent,
 			"-stderr", stderrContent,
 		)
@@ -76,7 +76,7 @@ func TestDefaultCmdExecutor(t *testing.T) {
 
 		stdoutContent := "partial output before failure"
 		stderrContent := "error: something went wrong"
-		_, err := executor.Execute(helperBin, ".",
+		_, err := executor.Execute(t.Context(), helperBin, ".",
 			"-stdout", stdoutContent,
 			"-stderr", stderrContent,
 			"-exit", "1",
@@ -92,7 +92,7 @@ func TestDefaultCmdExecutor(t *testing.T) {
 	t.Run("returns error when binary not found", func(t *testing.T) {
 		executor := &uvCmdExecutor{}
 
-		_, err := executor.Execute("binary-that-does-not-exist", ".")
+		_, err := executor.Execute(t.Context(), "binary-that-does-not-exist", ".")
 
 		require.Error(t, err)
 		var catalogErr snyk_errors.Error
@@ -106,7 +106,7 @@ func TestDefaultCmdExecutor(t *testing.T) {
 		// Create a temp directory to use as working directory
 		tempDir := t.TempDir()
 
-		output, err := executor.Execute(helperBin, tempDir, "-stdout", "ok")
+		output, err := executor.Execute(t.Context(), helperBin, tempDir, "-stdout", "ok")
 
 		require.NoError(t, err)
 		assert.Equal(t, "ok", string(output))
@@ -115,7 +115,7 @@ func TestDefaultCmdExecutor(t *testing.T) {
 	t.Run("handles empty output", func(t *testing.T) {
 		executor := &uvCmdExecutor{}
 
-		output, err := executor.Execute(helperBin, ".")
+		output, err := executor.Execute(t.Context(), helperBin, ".")
 
 		require.NoError(t, err)
 		assert.Empty(t, output)
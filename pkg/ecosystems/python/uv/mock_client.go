@@ -1,6 +1,8 @@
 package uv
 
 import (
+	"context"
+
 	"github.com/snyk/cli-extension-dep-graph/v2/pkg/ecosystems"
 )
 
@@ -10,7 +12,7 @@ type MockClient struct {
 	ErrorDirs  map[string]error
 }
 
-func (m *MockClient) ExportSBOM(inputDir string, _ *ecosystems.SCAPluginOptions) (Sbom, error) {
+func (m *MockClient) ExportSBOM(_ context.Context, inputDir string, _ *ecosystems.SCAPluginOptions) (Sbom, error) {
 	m.CalledDirs = append(m.CalledDirs, inputDir)
 
 	if m.ErrorDirs != nil {
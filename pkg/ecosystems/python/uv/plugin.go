@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"io/fs"
 	"os"
 	"path/filepath"
 
@@ -60,6 +61,7 @@ func (p Plugin) BuildDepGraphsFromDir(
 	if err != nil {
 		return err
 	}
+	fsys := os.DirFS(inputDir)
 	if len(files) == 0 {
 		return nil
 	}
@@ -69,7 +71,7 @@ func (p Plugin) BuildDepGraphsFromDir(
 		lockFileDir := filepath.Dir(lockFilePath)
 		log.Info(ctx, "Building dependency graph", logger.Attr("lockFile", lockFilePath)) //nolint:goconst // logger key, not worth a constant
 
-		sbom, err := p.client.ExportSBOM(lockFileDir, options)
+		sbom, err := p.client.ExportSBOM(ctx, lockFileDir, options)
 		if err != nil {
 			log.Error(ctx, "Failed to build dependency graph", logger.Attr("lockFile", lockFilePath), logger.Err(err))
 			wrappedErr := fmt.Errorf("failed to build dependency graph for %s: %w", lockFilePath, err)
@@ -91,7 +93,11 @@ func (p Plugin) BuildDepGraphsFromDir(
 			}
 			continue
 		}
-		emitted, err := p.buildResults(ctx, sbom, lockFilePath, lockFileDir, options, log, onGraph)
+		emit := onGraph
+		if options.Global.IncludeProvenance {
+			emit = withLockChecksums(ctx, log, file.Path, onGraph)
+		}
+		emitted, err := p.buildResults(ctx, sbom, lockFilePath, lockFileDir, fsys, options, log, emit)
 		if err != nil {
 			return err
 		}
@@ -105,6 +111,53 @@ func (p Plugin) BuildDepGraphsFromDir(
 	return nil
 }
 
+// DetectProjects reports one project per uv.lock BuildDepGraphsFromDir would
+// export, without running uv. Workspace members are only known once the SBOM
+// is exported, so each lockfile is reported as its root project.
+func (p Plugin) DetectProjects(
+	ctx context.Context,
+	log logger.Logger,
+	inputDir string,
+	options *scaecosystems.SCAPluginOptions,
+	onProject scaecosystems.OnDetectFunc,
+) error {
+	var targetFile string
+	if options.Global.TargetFile != nil {
+		targetFile = *options.Global.TargetFile
+	}
+
+	if targetFile != "" && filepath.Base(targetFile) != LockFileName {
+		log.Info(ctx, "Skipping processing uv plugin", logger.Attr("targetFile", targetFile), logger.Attr("reason", "not a 'uv.lock' file"))
+		return nil
+	}
+
+	files, err := p.discoverLockFiles(ctx, inputDir, targetFile, options)
+	if err != nil {
+		return err
+	}
+	fsys := os.DirFS(inputDir)
+
+	for _, file := range files {
+		lockFileDir := filepath.Dir(file.RelPath)
+		manifestFile := PyprojectTomlFileName
+		if lockFileDir != "." {
+			manifestFile = filepath.Join(lockFileDir, PyprojectTomlFileName)
+		}
+		if err := onProject(scaecosystems.DetectedProject{
+			TargetFile:   manifestFile,
+			ProjectType:  "uv",
+			ClaimedFiles: claimedFiles(fsys, lockFileDir),
+		}); err != nil {
+			return err
+		}
+		if !options.Global.AllProjects {
+			break
+		}
+	}
+
+	return nil
+}
+
 // buildResults parses + converts the SBOM and emits one result per
 // dep-graph via onGraph. Returns the number emitted so the caller can
 // decide whether to break out of the !AllProjects single-project loop.
@@ -113,6 +166,7 @@ func (p Plugin) buildResults(
 	sbom Sbom,
 	lockFilePath string,
 	lockFileDir string,
+	fsys fs.FS,
 	options *scaecosystems.SCAPluginOptions,
 	log logger.Logger,
 	onGraph scaecosystems.OnGraphFunc,
@@ -164,7 +218,7 @@ func (p Plugin) buildResults(
 	emitted := 0
 	for _, depGraph := range depGraphs {
 		workspacePackage := findWorkspacePackage(depGraph, workspacePackages)
-		result := buildSCAResult(depGraph, lockFileDir, workspacePackage)
+		result := buildSCAResult(fsys, depGraph, lockFileDir, workspacePackage)
 		if emitErr := onGraph(result); emitErr != nil {
 			return emitted, emitErr
 		}
@@ -176,7 +230,8 @@ func (p Plugin) buildResults(
 
 // buildSCAResult constructs the SCAResult for a single dep-graph, deriving the
 // manifest path and processed files from the (optional) workspace package.
-func buildSCAResult(depGraph *depgraph.DepGraph, lockFileDir string, workspacePackage *WorkspacePackage) scaecosystems.SCAResult {
+// fsys is the input directory the files are looked up in.
+func buildSCAResult(fsys fs.FS, depGraph *depgraph.DepGraph, lockFileDir string, workspacePackage *WorkspacePackage) scaecosystems.SCAResult {
 	var manifestFile string
 	switch {
 	case workspacePackage != nil:
@@ -191,10 +246,7 @@ func buildSCAResult(depGraph *depgraph.DepGraph, lockFileDir string, workspacePa
 	if workspacePackage != nil {
 		packagePath = filepath.Join(packagePath, workspacePackage.Path)
 	}
-	processedFiles := make([]string, 0, 3)
-	for _, name := range []string{LockFileName, PyprojectTomlFileName, RequirementsTxtFileName} {
-		processedFiles = append(processedFiles, filepath.Join(packagePath, name))
-	}
+	processedFiles := claimedFiles(fsys, packagePath)
 
 	var rootName string
 	if rootPkg := depGraph.GetRootPkg(); rootPkg != nil {
@@ -219,6 +271,21 @@ func buildSCAResult(depGraph *depgraph.DepGraph, lockFileDir string, workspacePa
 	}
 }
 
+// claimedFiles lists the files a uv project rooted at packagePath claims.
+// Claiming the sibling Pipfile/Pipfile.lock and requirements.txt that exist
+// in fsys is what gives uv.lock precedence over the pipenv and pip plugins in
+// the same directory.
+func claimedFiles(fsys fs.FS, packagePath string) []string {
+	files := []string{filepath.Join(packagePath, LockFileName), filepath.Join(packagePath, PyprojectTomlFileName)}
+	for _, name := range []string{RequirementsTxtFileName, PipfileFileName, PipfileLockFileName} {
+		sibling := filepath.Join(packagePath, name)
+		if info, err := fs.Stat(fsys, filepath.ToSlash(sibling)); err == nil && !info.IsDir() {
+			files = append(files, sibling)
+		}
+	}
+	return files
+}
+
 // sbomMetadata is the minimal metadata needed to construct an empty dep-graph
 // fallback when an SBOM yields no dep-graphs from conversion.
 type sbomMetadata struct {
@@ -343,12 +410,7 @@ func (p Plugin) discoverLockFiles(
 			discovery.WithInclude(LockFileName),
 			discovery.WithCommonExcludes(),
 		}
-		if len(options.Global.Exclude) > 0 {
-			findOpts = append(findOpts, discovery.WithExcludes(options.Global.Exclude...))
-		}
-		if len(options.Global.ExcludePaths) > 0 {
-			findOpts = append(findOpts, discovery.WithExcludes(options.Global.ExcludePaths...))
-		}
+		findOpts = append(findOpts, options.DiscoveryOptions()...)
 	default:
 		if targetFile != "" {
 			findOpts = append(findOpts, discovery.WithTargetFile(targetFile))
@@ -383,4 +445,7 @@ func fileExists(path string) bool {
 	return !info.IsDir()
 }
 
-var _ scaecosystems.SCAPlugin = (*Plugin)(nil)
+var (
+	_ scaecosystems.SCAPlugin       = (*Plugin)(nil)
+	_ scaecosystems.ProjectDetector = (*Plugin)(nil)
+)
@@ -8,6 +8,7 @@ import (
 	"path/filepath"
 	"sort"
 	"testing"
+	"testing/fstest"
 
 	"github.com/snyk/dep-graph/go/pkg/depgraph"
 	"github.com/snyk/error-catalog-golang-public/snyk_errors"
@@ -301,6 +302,31 @@ func TestPlugin_ShouldNotSkipProcessingWhenUvLockFile(t *testing.T) {
 	require.NotEmpty(t, mockClient.CalledDirs, "Should have called the uv client")
 }
 
+func TestPlugin_DetectProjects(t *testing.T) {
+	tmpDir := createFiles(t, "uv.lock", "pyproject.toml", "svc/uv.lock", "svc/pyproject.toml")
+
+	mockClient := &MockClient{}
+	plugin := NewPlugin(mockClient, mockConverter(), "")
+
+	t.Run("all projects reports every lockfile", func(t *testing.T) {
+		got, err := scatest.Detect(t.Context(), plugin, testLogger, tmpDir, ecosystems.NewPluginOptions().WithAllProjects(true))
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []ecosystems.DetectedProject{
+			{TargetFile: "pyproject.toml", ProjectType: "uv", ClaimedFiles: claimedFiles(os.DirFS(tmpDir), ".")},
+			{TargetFile: filepath.Join("svc", "pyproject.toml"), ProjectType: "uv", ClaimedFiles: claimedFiles(os.DirFS(tmpDir), "svc")},
+		}, got)
+	})
+
+	t.Run("single project reports the root lockfile", func(t *testing.T) {
+		got, err := scatest.Detect(t.Context(), plugin, testLogger, tmpDir, ecosystems.NewPluginOptions())
+		require.NoError(t, err)
+		require.Len(t, got, 1)
+		assert.Equal(t, "pyproject.toml", got[0].TargetFile)
+	})
+
+	require.Empty(t, mockClient.CalledDirs, "Detection should not call the uv client")
+}
+
 func TestPlugin_SkipsProcessingWhenTargetFileIsNotUVFile(t *testing.T) {
 	tmpDir := createFiles(t, "uv.lock", "pyproject.toml", "package.json")
 
@@ -470,7 +496,30 @@ func TestBuildFindings_Success(t *testing.T) {
 	assert.NotNil(t, findings[0].DepGraph)
 	assert.Equal(t, "pyproject.toml", findings[0].ResolverMetadata.NormalisedTargetFile)
 	assert.Nil(t, findings[0].Error)
-	assert.Equal(t, []string{"uv.lock", "pyproject.toml", "requirements.txt"}, findings[0].ProcessedFiles)
+	assert.Equal(t, []string{"uv.lock", "pyproject.toml"}, findings[0].ProcessedFiles)
+}
+
+func TestBuildFindings_ClaimsExistingSiblingManifests(t *testing.T) {
+	sbom := Sbom(validSBOMJSON)
+	plugin := NewPlugin(&MockClient{}, mockConverter(createTestDepGraph("test-package", "1.0.0")), "")
+	fsys := fstest.MapFS{
+		"uv.lock":          {},
+		"pyproject.toml":   {},
+		"requirements.txt": {},
+		"Pipfile":          {},
+	}
+
+	var findings []ecosystems.SCAResult
+	_, err := plugin.buildResults(context.Background(), sbom, "uv.lock", ".", fsys, ecosystems.NewPluginOptions(), testLogger,
+		func(r ecosystems.SCAResult) error {
+			findings = append(findings, r)
+			return nil
+		})
+
+	require.NoError(t, err)
+	require.Len(t, findings, 1)
+	assert.Equal(t, []string{"uv.lock", "pyproject.toml", "requirements.txt", "Pipfile"}, findings[0].ProcessedFiles,
+		"only siblings that exist are claimed from pip and pipenv")
 }
 
 func TestBuildFindings_NoProjectRoot_ReturnsErrorFinding(t *testing.T) {
@@ -2,6 +2,7 @@ package uv
 
 import (
 	"context"
+	"testing/fstest"
 
 	scaecosystems "github.com/snyk/cli-extension-dep-graph/v2/pkg/ecosystems"
 	"github.com/snyk/cli-extension-dep-graph/v2/pkg/ecosystems/logger"
@@ -19,7 +20,7 @@ func collectBuildResults(
 	log logger.Logger,
 ) ([]scaecosystems.SCAResult, error) {
 	var results []scaecosystems.SCAResult
-	_, err := plugin.buildResults(ctx, sbom, lockFilePath, lockFileDir, opts, log, func(r scaecosystems.SCAResult) error {
+	_, err := plugin.buildResults(ctx, sbom, lockFilePath, lockFileDir, fstest.MapFS{}, opts, log, func(r scaecosystems.SCAResult) error {
 		results = append(results, r)
 		return nil
 	})
@@ -1,6 +1,7 @@
 package uv
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -17,12 +18,14 @@ const (
 	LockFileName            = "uv.lock"
 	RequirementsTxtFileName = "requirements.txt"
 	PyprojectTomlFileName   = "pyproject.toml"
+	PipfileFileName         = "Pipfile"
+	PipfileLockFileName     = "Pipfile.lock"
 	WorkspacePathProperty   = "uv:workspace:path"
 	IsProjectRootProperty   = "uv:package:is_project_root"
 )
 
 type Client interface {
-	ExportSBOM(inputDir string, opts *scaecosystems.SCAPluginOptions) (Sbom, error)
+	ExportSBOM(ctx context.Context, inputDir string, opts *scaecosystems.SCAPluginOptions) (Sbom, error)
 }
 
 type client struct {
@@ -57,7 +60,7 @@ type WorkspacePackage struct {
 }
 
 // ExportSBOM exports an SBOM in CycloneDX format using uv.
-func (c client) ExportSBOM(inputDir string, opts *scaecosystems.SCAPluginOptions) (Sbom, error) {
+func (c client) ExportSBOM(ctx context.Context, inputDir string, opts *scaecosystems.SCAPluginOptions) (Sbom, error) {
 	args := []string{"export", "--format", "cyclonedx1.5", "--preview"}
 	if opts.Global.AllowOutOfSync {
 		args = append(args, "--frozen")
@@ -70,7 +73,7 @@ func (c client) ExportSBOM(inputDir string, opts *scaecosystems.SCAPluginOptions
 	if !opts.Global.IncludeDev {
 		args = append(args, "--no-dev")
 	}
-	output, err := c.executor.Execute(c.uvBinary, inputDir, args...)
+	output, err := c.executor.Execute(ctx, c.uvBinary, inputDir, args...)
 	if err != nil {
 		if !opts.Global.AllowOutOfSync && isOutOfSyncLockfileError(err) {
 			return nil, clierrors.NewGeneralSCAFailureError(
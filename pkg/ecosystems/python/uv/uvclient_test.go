@@ -1,6 +1,7 @@
 package uv
 
 import (
+	"context"
 	"errors"
 	"testing"
 
@@ -16,7 +17,7 @@ type mockCmdExecutor struct {
 	executeFunc func(binary, dir string, args ...string) ([]byte, error)
 }
 
-func (m *mockCmdExecutor) Execute(binary, dir string, args ...string) ([]byte, error) {
+func (m *mockCmdExecutor) Execute(_ context.Context, binary, dir string, args ...string) ([]byte, error) {
 	if m.executeFunc != nil {
 		return m.executeFunc(binary, dir, args...)
 	}
@@ -49,7 +50,7 @@ func TestUVClient_ExportSBOM_Success(t *testing.T) {
 	}
 
 	client := NewClientWithExecutor("/path/to/uv", mockExecutor)
-	result, err := client.ExportSBOM("/test/dir", ecosystems.NewPluginOptions())
+	result, err := client.ExportSBOM(t.Context(), "/test/dir", ecosystems.NewPluginOptions())
 
 	assert.NoError(t, err)
 	require.NotNil(t, result)
@@ -83,7 +84,7 @@ func TestUVClient_ExportSBOM_AllProjects(t *testing.T) {
 	}
 
 	client := NewClientWithExecutor("/path/to/uv", mockExecutor)
-	result, err := client.ExportSBOM("/test/dir", ecosystems.NewPluginOptions().WithAllProjects(true))
+	result, err := client.ExportSBOM(t.Context(), "/test/dir", ecosystems.NewPluginOptions().WithAllProjects(true))
 
 	assert.NoError(t, err)
 	require.NotNil(t, result)
@@ -117,7 +118,7 @@ func TestUVClient_ExportSBOM_UvWorkspacePackages(t *testing.T) {
 	}
 
 	client := NewClientWithExecutor("/path/to/uv", mockExecutor)
-	result, err := client.ExportSBOM("/test/dir", ecosystems.NewPluginOptions().WithForceIncludeWorkspacePackages(true))
+	result, err := client.ExportSBOM(t.Context(), "/test/dir", ecosystems.NewPluginOptions().WithForceIncludeWorkspacePackages(true))
 
 	assert.NoError(t, err)
 	require.NotNil(t, result)
@@ -154,7 +155,7 @@ func TestUVClient_ExportSBOM_WorkspacePackage(t *testing.T) {
 	}
 
 	client := NewClientWithExecutor("/path/to/uv", mockExecutor)
-	result, err := client.ExportSBOM("/test/dir", ecosystems.NewPluginOptions().WithWorkspacePackage("package-b"))
+	result, err := client.ExportSBOM(t.Context(), "/test/dir", ecosystems.NewPluginOptions().WithWorkspacePackage("package-b"))
 
 	assert.NoError(t, err)
 	require.NotNil(t, result)
@@ -175,7 +176,7 @@ func TestUVClient_ExportSBOM_DevTrue_OmitsNoDevFlag(t *testing.T) {
 	}
 
 	client := NewClientWithExecutor("/path/to/uv", mockExecutor)
-	_, err := client.ExportSBOM("/test/dir", ecosystems.NewPluginOptions().WithIncludeDev(true))
+	_, err := client.ExportSBOM(t.Context(), "/test/dir", ecosystems.NewPluginOptions().WithIncludeDev(true))
 
 	assert.NoError(t, err)
 }
@@ -195,7 +196,7 @@ func TestUVClient_ExportSBOM_AllowOutOfSync_UsesFrozenFlag(t *testing.T) {
 	}
 
 	client := NewClientWithExecutor("/path/to/uv", mockExecutor)
-	_, err := client.ExportSBOM("/test/dir", ecosystems.NewPluginOptions().WithAllowOutOfSync(true))
+	_, err := client.ExportSBOM(t.Context(), "/test/dir", ecosystems.NewPluginOptions().WithAllowOutOfSync(true))
 
 	assert.NoError(t, err)
 }
@@ -213,7 +214,7 @@ func TestUVClient_ExportSBOM_OutOfSyncLockfile_ReturnsFriendlyError(t *testing.T
 	}
 
 	client := NewClientWithExecutor("/path/to/uv", mockExecutor)
-	result, err := client.ExportSBOM("/test/dir", ecosystems.NewPluginOptions())
+	result, err := client.ExportSBOM(t.Context(), "/test/dir", ecosystems.NewPluginOptions())
 
 	require.Error(t, err)
 	assert.Nil(t, result)
@@ -236,7 +237,7 @@ func TestUVClient_ExportSBOM_NonOutOfSyncExportError_ReturnsOriginalError(t *tes
 	}
 
 	client := NewClientWithExecutor("/path/to/uv", mockExecutor)
-	result, err := client.ExportSBOM("/test/dir", ecosystems.NewPluginOptions())
+	result, err := client.ExportSBOM(t.Context(), "/test/dir", ecosystems.NewPluginOptions())
 
 	require.Error(t, err)
 	assert.ErrorIs(t, err, expectedErr)
@@ -253,7 +254,7 @@ func TestUVClient_ExportSBOM_Error(t *testing.T) {
 	}
 
 	client := NewClientWithExecutor("/path/to/uv", mockExecutor)
-	result, err := client.ExportSBOM("/test/dir", ecosystems.NewPluginOptions())
+	result, err := client.ExportSBOM(t.Context(), "/test/dir", ecosystems.NewPluginOptions())
 
 	require.Error(t, err)
 	assert.ErrorIs(t, err, expectedErr)
@@ -275,7 +276,7 @@ func TestUVClient_ExportSBOM_InvalidSBOM(t *testing.T) {
 	}
 
 	client := NewClientWithExecutor("/path/to/uv", mockExecutor)
-	result, err := client.ExportSBOM("/test/dir", ecosystems.NewPluginOptions())
+	result, err := client.ExportSBOM(t.Context(), "/test/dir", ecosystems.NewPluginOptions())
 
 	// ExportSBOM should succeed - validation happens later in buildFindings
 	assert.NoError(t, err)
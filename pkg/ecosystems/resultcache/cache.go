@@ -0,0 +1,397 @@
+// Package resultcache caches the results of SCA plugin runs on disk, so a
+// re-run against unchanged lockfiles replays the stored dep-graphs instead of
+// invoking the package manager again.
+package resultcache
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime/debug"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/snyk/cli-extension-dep-graph/v2/pkg/ecosystems"
+	"github.com/snyk/cli-extension-dep-graph/v2/pkg/ecosystems/logger"
+)
+
+// formatVersion is part of every key. Bump it when the stored entry format
+// or the meaning of a key changes, to orphan every existing entry.
+const formatVersion = "2"
+
+// modulePath identifies this module in the binary's build info, whose
+// version is part of every key so an upgrade never replays stale graphs.
+// See moduleVersion.
+const modulePath = "github.com/snyk/cli-extension-dep-graph/v2"
+
+const entrySuffix = ".json"
+
+// Cache stores plugin results in a directory, one file per plugin run, and
+// evicts the least recently used entries once the directory grows past
+// maxBytes.
+type Cache struct {
+	dir      string
+	maxBytes int64
+	version  string
+	// toolVersion identifies the installed version of a tool binary.
+	// Overridden in tests.
+	toolVersion func(ctx context.Context, tool string) (string, error)
+
+	// toolIDs memoizes toolVersion, so each tool is asked for its version
+	// once per run however many projects it resolves.
+	toolMu  sync.Mutex
+	toolIDs map[string]toolID
+}
+
+type toolID struct {
+	id  string
+	err error
+}
+
+type entry struct {
+	Results []ecosystems.SCAResult `json:"results"`
+}
+
+// New returns a cache backed by dir, creating it if needed. maxBytes caps the
+// total size of the stored entries; 0 means no cap.
+func New(dir string, maxBytes int64) (*Cache, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("creating result cache directory: %w", err)
+	}
+	return &Cache{
+		dir:         dir,
+		maxBytes:    maxBytes,
+		version:     moduleVersion(),
+		toolVersion: versionOutput,
+		toolIDs:     map[string]toolID{},
+	}, nil
+}
+
+// Clear removes every stored entry.
+func (c *Cache) Clear() error {
+	entries, err := c.entries()
+	if err != nil {
+		return err
+	}
+	for _, e := range entries {
+		if err := os.Remove(filepath.Join(c.dir, e.Name())); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("clearing result cache: %w", err)
+		}
+	}
+	return nil
+}
+
+// Wrap returns a plugin that serves plugin's results from the cache when the
+// files it would process are unchanged. tool names the binary the plugin runs,
+// if any; a change in the version it reports invalidates the plugin's
+// entries. Only plugins that implement ecosystems.ProjectDetector can be
+// cached, since the key covers the files detection claims; others are
+// returned unchanged, as is every plugin when the running build cannot be
+// identified.
+func (c *Cache) Wrap(plugin ecosystems.SCAPlugin, tool string) ecosystems.SCAPlugin {
+	detector, ok := plugin.(ecosystems.ProjectDetector)
+	if !ok || c.version == "" {
+		return plugin
+	}
+	return &cachedPlugin{SCAPlugin: plugin, detector: detector, cache: c, tool: tool}
+}
+
+// key hashes everything a plugin run's results depend on: the plugin and the
+// tool it runs, the options, and the name and content of every file detection
+// claimed. It returns the claimed files too, so the caller can check the
+// run's ProcessedFiles against them before storing its results.
+func (c *Cache) key(
+	ctx context.Context,
+	log logger.Logger,
+	detector ecosystems.ProjectDetector,
+	name, tool, dir string,
+	opts *ecosystems.SCAPluginOptions,
+) (string, map[string]bool, error) {
+	h := sha256.New()
+	fmt.Fprintf(h, "format=%s\nmodule=%s\nplugin=%s\ndir=%s\n", formatVersion, c.version, name, dir)
+
+	if tool != "" {
+		id, err := c.toolID(ctx, tool)
+		if err != nil {
+			return "", nil, err
+		}
+		fmt.Fprintf(h, "tool=%s\n", id)
+	}
+
+	optsJSON, err := json.Marshal(keyOptions(opts))
+	if err != nil {
+		return "", nil, fmt.Errorf("encoding options: %w", err)
+	}
+	fmt.Fprintf(h, "options=%s\n", optsJSON)
+
+	claimed := make(map[string]bool)
+	err = detector.DetectProjects(ctx, log, dir, opts, func(project ecosystems.DetectedProject) error {
+		fmt.Fprintf(h, "project=%s\n", project.TargetFile)
+		for _, file := range project.ClaimedFiles {
+			claimed[file] = true
+			if err := hashFile(h, dir, file); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return "", nil, fmt.Errorf("detecting projects: %w", err)
+	}
+	if len(claimed) == 0 {
+		return "", nil, errNothingClaimed
+	}
+	return hex.EncodeToString(h.Sum(nil)), claimed, nil
+}
+
+var errNothingClaimed = errors.New("detection claimed no files")
+
+// keyOptions returns a copy of opts without the fields that control the
+// registry rather than what a plugin resolves.
+func keyOptions(opts *ecosystems.SCAPluginOptions) ecosystems.SCAPluginOptions {
+	keyed := *opts
+	keyed.Global.FailFast = false
+	keyed.Global.OnlyPlugins = nil
+	keyed.Global.SkipPlugins = nil
+	keyed.Global.PluginTimeout = 0
+	keyed.Global.ScanTimeout = 0
+	keyed.Global.CommandTimeout = 0
+	return keyed
+}
+
+func hashFile(w io.Writer, dir, file string) error {
+	f, err := os.Open(filepath.Join(dir, file))
+	if err != nil {
+		return fmt.Errorf("hashing claimed file: %w", err)
+	}
+	defer f.Close()
+
+	fmt.Fprintf(w, "file=%s\n", file)
+	if _, err := io.Copy(w, f); err != nil {
+		return fmt.Errorf("hashing %s: %w", file, err)
+	}
+	return nil
+}
+
+// load returns the results stored under key, or false on a miss. A hit
+// refreshes the entry's modification time, which eviction orders by.
+func (c *Cache) load(key string) ([]ecosystems.SCAResult, bool) {
+	path := c.path(key)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, false
+	}
+	var e entry
+	if err := json.Unmarshal(data, &e); err != nil {
+		return nil, false
+	}
+	now := time.Now()
+	_ = os.Chtimes(path, now, now) //nolint:errcheck // only affects eviction order
+	return e.Results, true
+}
+
+// store writes results under key, then evicts old entries if the cache is
+// over its size cap. The entry is written to a temporary file and renamed, so
+// concurrent runs never read a partial entry.
+func (c *Cache) store(key string, results []ecosystems.SCAResult) error {
+	data, err := json.Marshal(entry{Results: results})
+	if err != nil {
+		return fmt.Errorf("encoding results: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(c.dir, key+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("creating cache entry: %w", err)
+	}
+	_, writeErr := tmp.Write(data)
+	closeErr := tmp.Close()
+	if err := errors.Join(writeErr, closeErr); err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("writing cache entry: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), c.path(key)); err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("writing cache entry: %w", err)
+	}
+
+	return c.evict()
+}
+
+// evict removes the least recently used entries until the cache fits in
+// maxBytes.
+func (c *Cache) evict() error {
+	if c.maxBytes <= 0 {
+		return nil
+	}
+	entries, err := c.entries()
+	if err != nil {
+		return err
+	}
+
+	type stored struct {
+		name    string
+		size    int64
+		modTime time.Time
+	}
+	var (
+		files []stored
+		total int64
+	)
+	for _, e := range entries {
+		info, err := e.Info()
+		if err != nil {
+			continue // removed by a concurrent run
+		}
+		files = append(files, stored{name: e.Name(), size: info.Size(), modTime: info.ModTime()})
+		total += info.Size()
+	}
+
+	slices.SortFunc(files, func(a, b stored) int { return a.modTime.Compare(b.modTime) })
+	for _, f := range files {
+		if total <= c.maxBytes {
+			break
+		}
+		if err := os.Remove(filepath.Join(c.dir, f.name)); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("evicting cache entry: %w", err)
+		}
+		total -= f.size
+	}
+	return nil
+}
+
+func (c *Cache) entries() ([]os.DirEntry, error) {
+	all, err := os.ReadDir(c.dir)
+	if err != nil {
+		return nil, fmt.Errorf("reading result cache directory: %w", err)
+	}
+	entries := all[:0]
+	for _, e := range all {
+		if !e.IsDir() && strings.HasSuffix(e.Name(), entrySuffix) {
+			entries = append(entries, e)
+		}
+	}
+	return entries, nil
+}
+
+func (c *Cache) path(key string) string {
+	return filepath.Join(c.dir, key+entrySuffix)
+}
+
+// toolID returns the version of tool, asking it only the first time.
+func (c *Cache) toolID(ctx context.Context, tool string) (string, error) {
+	c.toolMu.Lock()
+	defer c.toolMu.Unlock()
+
+	if cached, ok := c.toolIDs[tool]; ok {
+		return cached.id, cached.err
+	}
+	id, err := c.toolVersion(ctx, tool)
+	c.toolIDs[tool] = toolID{id: id, err: err}
+	return id, err
+}
+
+// versionOutput identifies the installed tool by its path and what
+// "tool --version" prints, which an upgrade or a shim switching versions
+// changes.
+func versionOutput(ctx context.Context, tool string) (string, error) {
+	resolved, err := exec.LookPath(tool)
+	if err != nil {
+		return "", fmt.Errorf("finding %s: %w", tool, err)
+	}
+
+	cmdCtx, cancel := ecosystems.CommandContext(ctx, tool)
+	defer cancel()
+
+	out, err := exec.CommandContext(cmdCtx, resolved, "--version").Output()
+	if err != nil {
+		return "", fmt.Errorf("getting %s version: %w", tool, ecosystems.CommandError(cmdCtx, err))
+	}
+	return fmt.Sprintf("%s:%s", resolved, bytes.TrimSpace(out)), nil
+}
+
+// moduleVersion identifies the build of this module in the running binary:
+// its release version, or for a local build the clean VCS revision it was
+// built from. A build that carries neither, such as one from a modified
+// working tree, is identified by the content of its executable instead, so
+// rebuilding it after an edit never replays graphs the old code produced.
+// It returns "" if the build cannot be identified.
+func moduleVersion() string {
+	if info, ok := debug.ReadBuildInfo(); ok {
+		if version := buildVersion(info); version != "" {
+			return version
+		}
+	}
+	return executableHash()
+}
+
+// buildVersion returns the version of this module recorded in info, or "" if
+// it does not identify the code built: a "(devel)" or dirty version, or a
+// local replacement, without a clean VCS revision of the main module.
+func buildVersion(info *debug.BuildInfo) string {
+	if info.Main.Path == modulePath {
+		if isRelease(info.Main.Version) {
+			return info.Main.Version
+		}
+		var revision, modified string
+		for _, setting := range info.Settings {
+			switch setting.Key {
+			case "vcs.revision":
+				revision = setting.Value
+			case "vcs.modified":
+				modified = setting.Value
+			}
+		}
+		if revision != "" && modified == "false" {
+			return "vcs:" + revision
+		}
+		return ""
+	}
+	for _, dep := range info.Deps {
+		if dep.Path != modulePath {
+			continue
+		}
+		if dep.Replace != nil {
+			dep = dep.Replace
+		}
+		if isRelease(dep.Version) {
+			return dep.Version
+		}
+	}
+	return ""
+}
+
+// isRelease reports whether version names published code, as opposed to
+// being empty, "(devel)" or stamped from a modified working tree.
+func isRelease(version string) bool {
+	return version != "" && version != "(devel)" && !strings.HasSuffix(version, "+dirty")
+}
+
+// executableHash returns a hash of the running binary, or "" if it cannot
+// be read.
+func executableHash() string {
+	path, err := os.Executable()
+	if err != nil {
+		return ""
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return ""
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return ""
+	}
+	return "exe:" + hex.EncodeToString(h.Sum(nil))
+}
@@ -0,0 +1,346 @@
+package resultcache
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime/debug"
+	"testing"
+	"time"
+
+	"github.com/snyk/dep-graph/go/pkg/depgraph"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/snyk/cli-extension-dep-graph/v2/pkg/ecosystems"
+	"github.com/snyk/cli-extension-dep-graph/v2/pkg/ecosystems/logger"
+	"github.com/snyk/cli-extension-dep-graph/v2/pkg/ecosystems/scatest"
+	"github.com/snyk/cli-extension-dep-graph/v2/pkg/identity"
+)
+
+// lockfilePlugin resolves lock.json in the scanned directory, counting how
+// often it actually runs.
+type lockfilePlugin struct {
+	runs      int
+	resultErr error
+	processed []string
+}
+
+func (p *lockfilePlugin) GetName() string {
+	return "lockfile"
+}
+
+func (p *lockfilePlugin) DetectProjects(
+	_ context.Context,
+	_ logger.Logger,
+	_ string,
+	_ *ecosystems.SCAPluginOptions,
+	onProject ecosystems.OnDetectFunc,
+) error {
+	return onProject(ecosystems.DetectedProject{TargetFile: "lock.json", ProjectType: "lockfile", ClaimedFiles: []string{"lock.json"}})
+}
+
+func (p *lockfilePlugin) BuildDepGraphsFromDir(
+	_ context.Context,
+	_ logger.Logger,
+	dir string,
+	_ *ecosystems.SCAPluginOptions,
+	onGraph ecosystems.OnGraphFunc,
+) error {
+	p.runs++
+	content, err := os.ReadFile(filepath.Join(dir, "lock.json"))
+	if err != nil {
+		return err
+	}
+	builder, err := depgraph.NewBuilder(&depgraph.PkgManager{Name: "lockfile"}, &depgraph.PkgInfo{Name: string(content), Version: "1.0.0"})
+	if err != nil {
+		return err
+	}
+	processed := p.processed
+	if processed == nil {
+		processed = []string{"lock.json"}
+	}
+	return onGraph(ecosystems.SCAResult{
+		DepGraph:          builder.Build(),
+		ProjectDescriptor: identity.ProjectDescriptor{Identity: identity.ProjectIdentity{ProjectType: "lockfile", TargetFile: ptr("lock.json")}},
+		ProcessedFiles:    processed,
+		Error:             p.resultErr,
+	})
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+func newTestCache(t *testing.T, maxBytes int64) *Cache {
+	t.Helper()
+	cache, err := New(t.TempDir(), maxBytes)
+	require.NoError(t, err)
+	return cache
+}
+
+func writeLockfile(t *testing.T, dir, content string) {
+	t.Helper()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "lock.json"), []byte(content), 0o600))
+}
+
+func run(t *testing.T, plugin ecosystems.SCAPlugin, dir string, opts *ecosystems.SCAPluginOptions) []ecosystems.SCAResult {
+	t.Helper()
+	results, err := scatest.Run(context.Background(), plugin, logger.Nop(), dir, opts)
+	require.NoError(t, err)
+	return results
+}
+
+func TestCache_ReplaysUnchangedLockfile(t *testing.T) {
+	cache := newTestCache(t, 0)
+	dir := t.TempDir()
+	writeLockfile(t, dir, "app")
+	inner := &lockfilePlugin{}
+	plugin := cache.Wrap(inner, "")
+
+	first := run(t, plugin, dir, ecosystems.NewPluginOptions())
+	second := run(t, plugin, dir, ecosystems.NewPluginOptions())
+
+	assert.Equal(t, 1, inner.runs, "the second run must be served from the cache")
+	require.Len(t, second, 1)
+	assert.Equal(t, "app", second[0].DepGraph.GetRootPkg().Info.Name)
+	assert.Equal(t, first[0].ProjectDescriptor.GetTargetFile(), second[0].ProjectDescriptor.GetTargetFile())
+	assert.Equal(t, []string{"lock.json"}, second[0].ProcessedFiles)
+	assert.Equal(t, "lockfile", plugin.GetName())
+}
+
+func TestCache_InvalidatedBy(t *testing.T) {
+	t.Run("a changed lockfile", func(t *testing.T) {
+		cache := newTestCache(t, 0)
+		dir := t.TempDir()
+		inner := &lockfilePlugin{}
+		plugin := cache.Wrap(inner, "")
+
+		writeLockfile(t, dir, "app")
+		run(t, plugin, dir, ecosystems.NewPluginOptions())
+		writeLockfile(t, dir, "app-v2")
+		results := run(t, plugin, dir, ecosystems.NewPluginOptions())
+
+		assert.Equal(t, 2, inner.runs)
+		assert.Equal(t, "app-v2", results[0].DepGraph.GetRootPkg().Info.Name)
+	})
+
+	t.Run("different options", func(t *testing.T) {
+		cache := newTestCache(t, 0)
+		dir := t.TempDir()
+		writeLockfile(t, dir, "app")
+		inner := &lockfilePlugin{}
+		plugin := cache.Wrap(inner, "")
+
+		run(t, plugin, dir, ecosystems.NewPluginOptions())
+		run(t, plugin, dir, ecosystems.NewPluginOptions().WithIncludeDev(true))
+
+		assert.Equal(t, 2, inner.runs)
+	})
+
+	t.Run("a different tool version", func(t *testing.T) {
+		cacheDir := t.TempDir()
+		dir := t.TempDir()
+		writeLockfile(t, dir, "app")
+		inner := &lockfilePlugin{}
+
+		for _, version := range []string{"1.0.0", "2.0.0"} {
+			// Each run opens the cache anew, and asks the tool again.
+			cache, err := New(cacheDir, 0)
+			require.NoError(t, err)
+			cache.toolVersion = func(context.Context, string) (string, error) { return version, nil }
+			run(t, cache.Wrap(inner, "lockfile-tool"), dir, ecosystems.NewPluginOptions())
+		}
+
+		assert.Equal(t, 2, inner.runs)
+	})
+
+	t.Run("Clear", func(t *testing.T) {
+		cache := newTestCache(t, 0)
+		dir := t.TempDir()
+		writeLockfile(t, dir, "app")
+		inner := &lockfilePlugin{}
+		plugin := cache.Wrap(inner, "")
+
+		run(t, plugin, dir, ecosystems.NewPluginOptions())
+		require.NoError(t, cache.Clear())
+		run(t, plugin, dir, ecosystems.NewPluginOptions())
+
+		assert.Equal(t, 2, inner.runs)
+	})
+}
+
+func TestCache_IgnoresSelectionAndTimeoutOptions(t *testing.T) {
+	cache := newTestCache(t, 0)
+	dir := t.TempDir()
+	writeLockfile(t, dir, "app")
+	inner := &lockfilePlugin{}
+	plugin := cache.Wrap(inner, "")
+
+	run(t, plugin, dir, ecosystems.NewPluginOptions())
+	run(t, plugin, dir, ecosystems.NewPluginOptions().WithSkipPlugins([]string{"pip"}).WithFailFast(true).WithCommandTimeout(time.Minute))
+
+	assert.Equal(t, 1, inner.runs)
+}
+
+func TestCache_DoesNotStore(t *testing.T) {
+	tests := map[string]*lockfilePlugin{
+		"errored results":               {resultErr: errors.New("bad lockfile")},
+		"files detection did not claim": {processed: []string{"lock.json", "package.json"}},
+	}
+
+	for name, inner := range tests {
+		t.Run(name, func(t *testing.T) {
+			cache := newTestCache(t, 0)
+			dir := t.TempDir()
+			writeLockfile(t, dir, "app")
+			plugin := cache.Wrap(inner, "")
+
+			run(t, plugin, dir, ecosystems.NewPluginOptions())
+			run(t, plugin, dir, ecosystems.NewPluginOptions())
+
+			assert.Equal(t, 2, inner.runs)
+		})
+	}
+}
+
+func TestCache_AsksToolVersionOncePerRun(t *testing.T) {
+	cache := newTestCache(t, 0)
+	calls := 0
+	cache.toolVersion = func(context.Context, string) (string, error) {
+		calls++
+		return "1.0.0", nil
+	}
+	inner := &lockfilePlugin{}
+	plugin := cache.Wrap(inner, "lockfile-tool")
+
+	for _, project := range []string{"app", "lib"} {
+		dir := t.TempDir()
+		writeLockfile(t, dir, project)
+		run(t, plugin, dir, ecosystems.NewPluginOptions())
+	}
+
+	assert.Equal(t, 2, inner.runs)
+	assert.Equal(t, 1, calls)
+}
+
+func TestCache_UnavailableToolRunsPlugin(t *testing.T) {
+	cache := newTestCache(t, 0)
+	cache.toolVersion = func(context.Context, string) (string, error) { return "", errors.New("not found") }
+	dir := t.TempDir()
+	writeLockfile(t, dir, "app")
+	inner := &lockfilePlugin{}
+
+	results := run(t, cache.Wrap(inner, "lockfile-tool"), dir, ecosystems.NewPluginOptions())
+
+	assert.Equal(t, 1, inner.runs)
+	assert.Len(t, results, 1)
+}
+
+func TestCache_EvictsLeastRecentlyUsed(t *testing.T) {
+	cache := newTestCache(t, 0)
+	dirA, dirB, dirC := t.TempDir(), t.TempDir(), t.TempDir()
+	writeLockfile(t, dirA, "a")
+	writeLockfile(t, dirB, "b")
+	writeLockfile(t, dirC, "c")
+	inner := &lockfilePlugin{}
+	plugin := cache.Wrap(inner, "")
+
+	run(t, plugin, dirA, ecosystems.NewPluginOptions())
+	entries, err := cache.entries()
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	info, err := entries[0].Info()
+	require.NoError(t, err)
+	// Room for two entries, not three.
+	cache.maxBytes = info.Size()*2 + info.Size()/2
+
+	run(t, plugin, dirB, ecosystems.NewPluginOptions())
+	run(t, plugin, dirA, ecosystems.NewPluginOptions()) // hit, so A is now more recent than B
+	run(t, plugin, dirC, ecosystems.NewPluginOptions())
+	require.Equal(t, 3, inner.runs)
+
+	run(t, plugin, dirA, ecosystems.NewPluginOptions())
+	assert.Equal(t, 3, inner.runs, "A was used recently and must be kept")
+	run(t, plugin, dirB, ecosystems.NewPluginOptions())
+	assert.Equal(t, 4, inner.runs, "B was the least recently used and must be evicted")
+}
+
+func TestCache_WrapLeavesNonDetectorsAlone(t *testing.T) {
+	cache := newTestCache(t, 0)
+	var plugin ecosystems.SCAPlugin = buildOnlyPlugin{}
+
+	assert.Equal(t, plugin, cache.Wrap(plugin, ""))
+}
+
+func TestCache_WrapLeavesPluginsAloneForUnidentifiedBuild(t *testing.T) {
+	cache := newTestCache(t, 0)
+	cache.version = ""
+	plugin := &lockfilePlugin{}
+
+	assert.Equal(t, ecosystems.SCAPlugin(plugin), cache.Wrap(plugin, ""))
+}
+
+func TestBuildVersion(t *testing.T) {
+	vcs := func(revision, modified string) []debug.BuildSetting {
+		return []debug.BuildSetting{{Key: "vcs.revision", Value: revision}, {Key: "vcs.modified", Value: modified}}
+	}
+	tests := map[string]struct {
+		info debug.BuildInfo
+		want string
+	}{
+		"released main module": {
+			info: debug.BuildInfo{Main: debug.Module{Path: modulePath, Version: "v2.3.0"}},
+			want: "v2.3.0",
+		},
+		"dev build from a clean checkout": {
+			info: debug.BuildInfo{Main: debug.Module{Path: modulePath, Version: "(devel)"}, Settings: vcs("abc123", "false")},
+			want: "vcs:abc123",
+		},
+		"dev build from a modified checkout": {
+			info: debug.BuildInfo{Main: debug.Module{Path: modulePath, Version: "(devel)"}, Settings: vcs("abc123", "true")},
+		},
+		"dirty pseudo-version": {
+			info: debug.BuildInfo{
+				Main:     debug.Module{Path: modulePath, Version: "v2.3.1-0.20260101000000-abc123+dirty"},
+				Settings: vcs("abc123", "true"),
+			},
+		},
+		"dev build without VCS information": {
+			info: debug.BuildInfo{Main: debug.Module{Path: modulePath, Version: "(devel)"}},
+		},
+		"dependency": {
+			info: debug.BuildInfo{
+				Main: debug.Module{Path: "github.com/snyk/cli", Version: "(devel)"},
+				Deps: []*debug.Module{{Path: modulePath, Version: "v2.3.0"}},
+			},
+			want: "v2.3.0",
+		},
+		"dependency replaced by a local directory": {
+			info: debug.BuildInfo{
+				Main: debug.Module{Path: "github.com/snyk/cli", Version: "(devel)"},
+				Deps: []*debug.Module{{Path: modulePath, Version: "v2.3.0", Replace: &debug.Module{Path: "../cli-extension-dep-graph"}}},
+			},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tt.want, buildVersion(&tt.info))
+		})
+	}
+}
+
+// buildOnlyPlugin does not implement ecosystems.ProjectDetector.
+type buildOnlyPlugin struct{}
+
+func (buildOnlyPlugin) GetName() string {
+	return "build-only"
+}
+
+func (buildOnlyPlugin) BuildDepGraphsFromDir(
+	context.Context, logger.Logger, string, *ecosystems.SCAPluginOptions, ecosystems.OnGraphFunc,
+) error {
+	return nil
+}
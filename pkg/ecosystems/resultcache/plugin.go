@@ -0,0 +1,97 @@
+package resultcache
+
+import (
+	"context"
+
+	"github.com/snyk/cli-extension-dep-graph/v2/pkg/ecosystems"
+	"github.com/snyk/cli-extension-dep-graph/v2/pkg/ecosystems/logger"
+)
+
+// cachedPlugin serves a plugin's results from the cache. It keeps the
+// wrapped plugin's name and detection, so the registry treats it like the
+// plugin itself.
+type cachedPlugin struct {
+	ecosystems.SCAPlugin
+	detector ecosystems.ProjectDetector
+	cache    *Cache
+	tool     string
+}
+
+var (
+	_ ecosystems.SCAPlugin       = (*cachedPlugin)(nil)
+	_ ecosystems.ProjectDetector = (*cachedPlugin)(nil)
+)
+
+func (p *cachedPlugin) DetectProjects(
+	ctx context.Context,
+	log logger.Logger,
+	dir string,
+	options *ecosystems.SCAPluginOptions,
+	onProject ecosystems.OnDetectFunc,
+) error {
+	//nolint:wrapcheck // transparent wrapper, the plugin's errors are returned as-is
+	return p.detector.DetectProjects(ctx, log, dir, options, onProject)
+}
+
+// BuildDepGraphsFromDir replays the stored results on a hit. On a miss it runs
+// the plugin, passing its results straight through, and stores them if the
+// run is safe to replay: it succeeded, no result errored, and every file it
+// processed was covered by the key.
+func (p *cachedPlugin) BuildDepGraphsFromDir(
+	ctx context.Context,
+	log logger.Logger,
+	dir string,
+	options *ecosystems.SCAPluginOptions,
+	onGraph ecosystems.OnGraphFunc,
+) error {
+	name := p.GetName()
+	key, claimed, err := p.cache.key(ctx, log, p.detector, name, p.tool, dir, options)
+	if err != nil {
+		log.Debug(ctx, "Result cache unavailable for plugin", logger.Attr("plugin", name), logger.Err(err))
+		//nolint:wrapcheck // transparent wrapper, the plugin's errors are returned as-is
+		return p.SCAPlugin.BuildDepGraphsFromDir(ctx, log, dir, options, onGraph)
+	}
+
+	if results, ok := p.cache.load(key); ok {
+		log.Debug(ctx, "Replaying cached results", logger.Attr("plugin", name), logger.Attr("results", len(results)))
+		for _, result := range results {
+			if err := onGraph(result); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	var (
+		results   []ecosystems.SCAResult
+		cacheable = true
+	)
+	err = p.SCAPlugin.BuildDepGraphsFromDir(ctx, log, dir, options, func(result ecosystems.SCAResult) error {
+		if result.Error != nil || !coveredBy(result.ProcessedFiles, claimed) {
+			cacheable = false
+		}
+		if cacheable {
+			results = append(results, result)
+		}
+		return onGraph(result)
+	})
+	if err != nil {
+		return err //nolint:wrapcheck // transparent wrapper, the plugin's errors are returned as-is
+	}
+
+	if cacheable && len(results) > 0 {
+		if err := p.cache.store(key, results); err != nil {
+			log.Debug(ctx, "Failed to store results in the result cache", logger.Attr("plugin", name), logger.Err(err))
+		}
+	}
+	return nil
+}
+
+func coveredBy(files []string, claimed map[string]bool) bool {
+	for _, file := range files {
+		if !claimed[file] {
+			return false
+		}
+	}
+	return true
+}
@@ -7,6 +7,8 @@ import (
 	"fmt"
 	"io"
 	"os/exec"
+
+	"github.com/snyk/cli-extension-dep-graph/v2/pkg/ecosystems"
 )
 
 // errCargoNotFound is returned when the cargo binary is not in PATH.
@@ -141,7 +143,9 @@ func (e *cargoCmdExecutor) run(
 		return nil, errCargoNotFound //nolint:wrapcheck // sentinel error, intentionally returned unwrapped
 	}
 
-	cmd := exec.CommandContext(ctx, resolved, args...)
+	cmdCtx, cancel := ecosystems.CommandContext(ctx, PluginName)
+
+	cmd := exec.CommandContext(cmdCtx, resolved, args...)
 	cmd.Dir = dir
 	cmd.Env = append(cmd.Environ(), "NO_COLOR=1")
 
@@ -154,13 +158,15 @@ func (e *cargoCmdExecutor) run(
 	cmd.Stdout = pw
 
 	if err := cmd.Start(); err != nil {
+		cancel()
 		pr.Close()
 		pw.Close()
 		return nil, fmt.Errorf("starting %s: %w", subcommandLabel, err)
 	}
 
 	go func() {
-		waitErr := cmd.Wait()
+		defer cancel()
+		waitErr := ecosystems.CommandError(cmdCtx, cmd.Wait())
 		if waitErr != nil {
 			pw.CloseWithError(fmt.Errorf("%s failed: %w\nstderr: %s", subcommandLabel, waitErr, stderr.String()))
 		} else {
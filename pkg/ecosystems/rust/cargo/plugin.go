@@ -33,7 +33,10 @@ type Plugin struct {
 	executor cargoRunner
 }
 
-var _ ecosystems.SCAPlugin = (*Plugin)(nil)
+var (
+	_ ecosystems.SCAPlugin       = (*Plugin)(nil)
+	_ ecosystems.ProjectDetector = (*Plugin)(nil)
+)
 
 func (p Plugin) GetName() string {
 	return PluginName
@@ -102,6 +105,34 @@ func (p Plugin) BuildDepGraphsFromDir(
 	return nil
 }
 
+// DetectProjects reports one project per Cargo.lock that BuildDepGraphsFromDir
+// would resolve, rooted at the Cargo.toml next to it. Workspace members are
+// only known after running `cargo metadata`, so they are not listed.
+func (p Plugin) DetectProjects(
+	ctx context.Context,
+	_ logger.Logger,
+	dir string,
+	options *ecosystems.SCAPluginOptions,
+	onProject ecosystems.OnDetectFunc,
+) error {
+	files, err := p.discoverLockFiles(ctx, dir, options)
+	if err != nil {
+		return err
+	}
+
+	for _, file := range files {
+		targetFile := filepath.Join(filepath.Dir(file.RelPath), cargoTomlFile)
+		if err := onProject(ecosystems.DetectedProject{
+			TargetFile:   targetFile,
+			ProjectType:  pkgManager,
+			ClaimedFiles: []string{file.RelPath, targetFile},
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (p Plugin) buildResults(
 	ctx context.Context,
 	log logger.Logger,
@@ -324,13 +355,7 @@ func (p Plugin) discoverLockFiles(
 			discovery.WithInclude(cargoLockFile),
 			discovery.WithCommonExcludes(),
 		}
-
-		if len(options.Global.Exclude) > 0 {
-			findOpts = append(findOpts, discovery.WithExcludes(options.Global.Exclude...))
-		}
-		if len(options.Global.ExcludePaths) > 0 {
-			findOpts = append(findOpts, discovery.WithExcludes(options.Global.ExcludePaths...))
-		}
+		findOpts = append(findOpts, options.DiscoveryOptions()...)
 
 		files, err := discovery.FindFiles(ctx, dir, findOpts...)
 		if err != nil {
@@ -146,6 +146,21 @@ func TestBuildDepGraphsFromDir_TargetFileNotCargoLock(t *testing.T) {
 	assert.Empty(t, results, "non-Cargo.lock target file should produce no results")
 }
 
+func TestDetectProjects_ReportsLockfilesWithoutRunningCargo(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, cargoLockFile), []byte(""), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, cargoTomlFile), []byte(""), 0o600))
+
+	plugin := Plugin{executor: &fakeExecutor{metadataErr: errCargoNotFound}}
+	projects, err := scatest.Detect(context.Background(), plugin, logger.Nop(), dir, &ecosystems.SCAPluginOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, []ecosystems.DetectedProject{{
+		TargetFile:   cargoTomlFile,
+		ProjectType:  pkgManager,
+		ClaimedFiles: []string{cargoLockFile, cargoTomlFile},
+	}}, projects)
+}
+
 func TestBuildDepGraphsFromDir_Workspace_TwoMembers(t *testing.T) {
 	// Workspace with members a and b, where a depends on b.
 	// Expect two SCAResults — one per member — and a's graph should
@@ -1,7 +1,8 @@
 // Package scatest provides shared helpers for SCAPlugin tests across
 // pkg/ecosystems/* — chiefly Run, which drives a plugin's
 // BuildDepGraphsFromDir and returns every emitted SCAResult as a
-// slice for the test body to inspect.
+// slice for the test body to inspect, and Detect, its counterpart for
+// ProjectDetector.DetectProjects.
 package scatest
 
 import (
@@ -32,3 +33,23 @@ func Run(
 	}
 	return results, nil
 }
+
+// Detect drives detector.DetectProjects and returns every reported
+// DetectedProject for the test to inspect.
+func Detect(
+	ctx context.Context,
+	detector ecosystems.ProjectDetector,
+	log logger.Logger,
+	dir string,
+	opts *ecosystems.SCAPluginOptions,
+) ([]ecosystems.DetectedProject, error) {
+	var projects []ecosystems.DetectedProject
+	err := detector.DetectProjects(ctx, log, dir, opts, func(p ecosystems.DetectedProject) error {
+		projects = append(projects, p)
+		return nil
+	})
+	if err != nil {
+		return projects, fmt.Errorf("scatest.Detect: %w", err)
+	}
+	return projects, nil
+}
@@ -0,0 +1,149 @@
+package ecosystems
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/snyk/dep-graph/go/pkg/depgraph"
+)
+
+// GraphIssueKind names a class of problem ValidateDepGraph reports.
+type GraphIssueKind string
+
+const (
+	// IssueDanglingReference is a node depending on a node, or referencing
+	// a package, the graph does not contain.
+	IssueDanglingReference GraphIssueKind = "dangling-reference"
+	// IssueUnreachableNode is a node no path from the root leads to.
+	IssueUnreachableNode GraphIssueKind = "unreachable-node"
+	// IssueMissingVersion is a package whose version is empty or "?".
+	IssueMissingVersion GraphIssueKind = "missing-version"
+	// IssueConflictingPackage is a package ID listed more than once with
+	// different names or versions.
+	IssueConflictingPackage GraphIssueKind = "conflicting-package"
+	// IssueCycle is a dependency edge leading back to one of its ancestors.
+	IssueCycle GraphIssueKind = "cycle"
+)
+
+// unknownVersion is the placeholder some plugins use for a version they
+// could not determine.
+const unknownVersion = "?"
+
+// GraphIssue is one problem found in a dep-graph.
+type GraphIssue struct {
+	Kind    GraphIssueKind
+	Message string
+}
+
+func (i GraphIssue) String() string {
+	return fmt.Sprintf("%s: %s", i.Kind, i.Message)
+}
+
+// GraphValidationError is the error a result carries when its dep-graph
+// failed validation in strict mode.
+type GraphValidationError struct {
+	Issues []GraphIssue
+}
+
+func (e *GraphValidationError) Error() string {
+	messages := make([]string, 0, len(e.Issues))
+	for _, issue := range e.Issues {
+		messages = append(messages, issue.String())
+	}
+	return fmt.Sprintf("invalid dependency graph: %s", strings.Join(messages, "; "))
+}
+
+// ValidateDepGraph checks dg for structural problems that would otherwise
+// only show up downstream, such as in vulnerability matching. The root
+// package is exempt from the version check, as projects often have none.
+func ValidateDepGraph(dg *depgraph.DepGraph) []GraphIssue {
+	v := &validator{
+		nodes: make(map[string]*depgraph.Node, len(dg.Graph.Nodes)),
+		pkgs:  make(map[string]*depgraph.Pkg, len(dg.Pkgs)),
+		state: make(map[string]visitState, len(dg.Graph.Nodes)),
+	}
+	v.checkPkgs(dg)
+	for i := range dg.Graph.Nodes {
+		node := &dg.Graph.Nodes[i]
+		v.nodes[node.NodeID] = node
+		if _, ok := v.pkgs[node.PkgID]; !ok {
+			v.report(IssueDanglingReference, "node %q references unknown package %q", node.NodeID, node.PkgID)
+		}
+	}
+
+	root, ok := v.nodes[dg.Graph.RootNodeID]
+	if !ok {
+		v.report(IssueDanglingReference, "root node %q not found", dg.Graph.RootNodeID)
+		return v.issues
+	}
+	v.visit(root)
+
+	for i := range dg.Graph.Nodes {
+		node := &dg.Graph.Nodes[i]
+		if v.state[node.NodeID] == unvisited {
+			v.report(IssueUnreachableNode, "node %q is not reachable from the root", node.NodeID)
+		}
+		if node.NodeID == dg.Graph.RootNodeID {
+			continue
+		}
+		if pkg, ok := v.pkgs[node.PkgID]; ok && (pkg.Info.Version == "" || pkg.Info.Version == unknownVersion) {
+			v.report(IssueMissingVersion, "package %q of node %q has no version", pkg.Info.Name, node.NodeID)
+		}
+	}
+	return v.issues
+}
+
+type visitState int
+
+const (
+	unvisited visitState = iota
+	inProgress
+	done
+)
+
+type validator struct {
+	nodes  map[string]*depgraph.Node
+	pkgs   map[string]*depgraph.Pkg
+	state  map[string]visitState
+	issues []GraphIssue
+}
+
+func (v *validator) report(kind GraphIssueKind, format string, args ...any) {
+	v.issues = append(v.issues, GraphIssue{Kind: kind, Message: fmt.Sprintf(format, args...)})
+}
+
+func (v *validator) checkPkgs(dg *depgraph.DepGraph) {
+	for i := range dg.Pkgs {
+		pkg := &dg.Pkgs[i]
+		existing, ok := v.pkgs[pkg.ID]
+		if !ok {
+			v.pkgs[pkg.ID] = pkg
+			continue
+		}
+		if existing.Info.Name != pkg.Info.Name || existing.Info.Version != pkg.Info.Version {
+			v.report(IssueConflictingPackage, "package ID %q is used for both %s@%s and %s@%s",
+				pkg.ID, existing.Info.Name, existing.Info.Version, pkg.Info.Name, pkg.Info.Version)
+		}
+	}
+}
+
+// visit walks the dependencies of node depth-first, reporting dependencies
+// on unknown nodes and edges back to a node still being visited.
+func (v *validator) visit(node *depgraph.Node) {
+	v.state[node.NodeID] = inProgress
+	for _, dep := range node.Deps {
+		child, ok := v.nodes[dep.NodeID]
+		if !ok {
+			v.report(IssueDanglingReference, "node %q depends on unknown node %q", node.NodeID, dep.NodeID)
+			continue
+		}
+		switch v.state[child.NodeID] {
+		case inProgress:
+			v.report(IssueCycle, "node %q depends on its ancestor %q", node.NodeID, child.NodeID)
+		case unvisited:
+			v.visit(child)
+		case done:
+		}
+	}
+	v.state[node.NodeID] = done
+}
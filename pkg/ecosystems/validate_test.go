@@ -0,0 +1,96 @@
+package ecosystems
+
+import (
+	"testing"
+
+	"github.com/snyk/dep-graph/go/pkg/depgraph"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func kinds(issues []GraphIssue) []GraphIssueKind {
+	out := make([]GraphIssueKind, 0, len(issues))
+	for _, issue := range issues {
+		out = append(out, issue.Kind)
+	}
+	return out
+}
+
+func TestValidateDepGraph_ValidGraph(t *testing.T) {
+	builder, err := depgraph.NewBuilder(&depgraph.PkgManager{Name: "npm"}, &depgraph.PkgInfo{Name: "app"})
+	require.NoError(t, err)
+	root := builder.GetRootNode().NodeID
+	builder.AddNode("a@1.0.0", &depgraph.PkgInfo{Name: "a", Version: "1.0.0"})
+	builder.AddNode("b@1.0.0", &depgraph.PkgInfo{Name: "b", Version: "1.0.0"})
+	require.NoError(t, builder.ConnectNodes(root, "a@1.0.0"))
+	require.NoError(t, builder.ConnectNodes(root, "b@1.0.0"))
+	require.NoError(t, builder.ConnectNodes("a@1.0.0", "b@1.0.0"))
+
+	assert.Empty(t, ValidateDepGraph(builder.Build()), "the root package may have no version")
+}
+
+func TestValidateDepGraph_BuilderFindings(t *testing.T) {
+	builder, err := depgraph.NewBuilder(&depgraph.PkgManager{Name: "pip"}, &depgraph.PkgInfo{Name: "app", Version: "1.0.0"})
+	require.NoError(t, err)
+	root := builder.GetRootNode().NodeID
+	builder.AddNode("a", &depgraph.PkgInfo{Name: "a", Version: "?"})
+	builder.AddNode("b", &depgraph.PkgInfo{Name: "b", Version: "1.0.0"})
+	builder.AddNode("c", &depgraph.PkgInfo{Name: "c", Version: "1.0.0"})
+	builder.AddNode("orphan", &depgraph.PkgInfo{Name: "orphan", Version: "1.0.0"})
+	require.NoError(t, builder.ConnectNodes(root, "a"))
+	require.NoError(t, builder.ConnectNodes("a", "b"))
+	require.NoError(t, builder.ConnectNodes("b", "c"))
+	require.NoError(t, builder.ConnectNodes("c", "a"))
+
+	issues := ValidateDepGraph(builder.Build())
+
+	assert.Equal(t, []GraphIssueKind{IssueCycle, IssueMissingVersion, IssueUnreachableNode}, kinds(issues))
+	assert.Equal(t, `cycle: node "c" depends on its ancestor "a"`, issues[0].String())
+	assert.Contains(t, issues[1].Message, `package "a"`)
+	assert.Contains(t, issues[2].Message, `"orphan"`)
+}
+
+func TestValidateDepGraph_MalformedGraph(t *testing.T) {
+	dg := &depgraph.DepGraph{
+		PkgManager: depgraph.PkgManager{Name: "cargo"},
+		Pkgs: []depgraph.Pkg{
+			{ID: "app@1.0.0", Info: depgraph.PkgInfo{Name: "app", Version: "1.0.0"}},
+			{ID: "serde@1.0.0", Info: depgraph.PkgInfo{Name: "serde", Version: "1.0.0"}},
+			{ID: "serde@1.0.0", Info: depgraph.PkgInfo{Name: "serde-fork", Version: "1.0.0"}},
+			{ID: "empty@", Info: depgraph.PkgInfo{Name: "empty"}},
+		},
+		Graph: depgraph.Graph{
+			RootNodeID: "root-node",
+			Nodes: []depgraph.Node{
+				{NodeID: "root-node", PkgID: "app@1.0.0", Deps: []depgraph.Dependency{{NodeID: "serde"}, {NodeID: "missing"}, {NodeID: "empty"}}},
+				{NodeID: "serde", PkgID: "serde@1.0.0"},
+				{NodeID: "empty", PkgID: "empty@", Deps: []depgraph.Dependency{{NodeID: "ghost"}}},
+				{NodeID: "ghost", PkgID: "ghost@1.0.0"},
+			},
+		},
+	}
+
+	issues := ValidateDepGraph(dg)
+
+	assert.ElementsMatch(t, []GraphIssueKind{
+		IssueConflictingPackage,
+		IssueDanglingReference, // ghost's package
+		IssueDanglingReference, // root's dependency on missing
+		IssueMissingVersion,
+	}, kinds(issues))
+}
+
+func TestValidateDepGraph_MissingRoot(t *testing.T) {
+	dg := &depgraph.DepGraph{Graph: depgraph.Graph{RootNodeID: "root-node"}}
+
+	assert.Equal(t, []GraphIssueKind{IssueDanglingReference}, kinds(ValidateDepGraph(dg)))
+}
+
+func TestGraphValidationError(t *testing.T) {
+	err := &GraphValidationError{Issues: []GraphIssue{
+		{Kind: IssueCycle, Message: "a depends on b"},
+		{Kind: IssueMissingVersion, Message: "c has no version"},
+	}}
+
+	assert.Equal(t, "invalid dependency graph: cycle: a depends on b; missing-version: c has no version", err.Error())
+}
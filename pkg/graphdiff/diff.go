@@ -0,0 +1,315 @@
+package graphdiff
+
+import (
+	"cmp"
+	"maps"
+	"slices"
+
+	"github.com/snyk/dep-graph/go/pkg/depgraph"
+)
+
+// Status summarises how a project changed between the two scans.
+type Status string
+
+const (
+	StatusAdded     Status = "added"
+	StatusRemoved   Status = "removed"
+	StatusChanged   Status = "changed"
+	StatusUnchanged Status = "unchanged"
+	// StatusUnresolved marks a project either scan failed to resolve, so it
+	// cannot be compared.
+	StatusUnresolved Status = "unresolved"
+)
+
+// Report is the difference between two scans.
+type Report struct {
+	Projects []ProjectDiff `json:"projects"`
+}
+
+// ProjectDiff is the difference between one project's dep-graphs. A project
+// only one scan has lists all its packages as added or removed.
+type ProjectDiff struct {
+	Project ProjectKey      `json:"project"`
+	Status  Status          `json:"status"`
+	Error   string          `json:"error,omitempty"`
+	Added   []Package       `json:"added,omitempty"`
+	Removed []Package       `json:"removed,omitempty"`
+	Changed []VersionChange `json:"changed,omitempty"`
+	// NewPaths holds, for every dependency edge the newer graph adds, a
+	// shortest path from the root through it, as name@version.
+	NewPaths [][]string `json:"newPaths,omitempty"`
+}
+
+// Package is a package present in only one of the scans.
+type Package struct {
+	Name    string `json:"name"`
+	Version string `json:"version,omitempty"`
+}
+
+// VersionChange is a package both scans have, at different versions.
+type VersionChange struct {
+	Name string   `json:"name"`
+	From []string `json:"from"`
+	To   []string `json:"to"`
+}
+
+// Diff compares the projects of two scans, matching them by key. Projects
+// keep the order of the newer scan, followed by those it no longer has.
+func Diff(from, to []Project) Report {
+	olderOf := match(from, to)
+	matched := make(map[int]bool, len(olderOf))
+
+	report := Report{Projects: []ProjectDiff{}}
+	for j := range to {
+		i, ok := olderOf[j]
+		if !ok {
+			report.Projects = append(report.Projects, diffProjects(nil, &to[j]))
+			continue
+		}
+		matched[i] = true
+		report.Projects = append(report.Projects, diffProjects(&from[i], &to[j]))
+	}
+	for i := range from {
+		if !matched[i] {
+			report.Projects = append(report.Projects, diffProjects(&from[i], nil))
+		}
+	}
+	return report
+}
+
+// match maps the index of each project in to onto the index of the same
+// project in from. A project that failed to resolve has no dep-graph to take
+// its type and root component from, so it is matched by target file alone.
+func match(from, to []Project) map[int]int {
+	olderOf := make(map[int]int)
+	used := make(map[int]bool)
+
+	byKey := make(map[ProjectKey][]int)
+	for i, p := range from {
+		byKey[p.Key] = append(byKey[p.Key], i)
+	}
+	for j, p := range to {
+		if queue := byKey[p.Key]; len(queue) > 0 {
+			olderOf[j], used[queue[0]] = queue[0], true
+			byKey[p.Key] = queue[1:]
+		}
+	}
+
+	for j, newer := range to {
+		if _, ok := olderOf[j]; ok || newer.Key.NormalisedTargetFile == "" {
+			continue
+		}
+		for i, older := range from {
+			if !used[i] && (older.Error != "" || newer.Error != "") &&
+				older.Key.NormalisedTargetFile == newer.Key.NormalisedTargetFile {
+				olderOf[j], used[i] = i, true
+				break
+			}
+		}
+	}
+	return olderOf
+}
+
+func diffProjects(older, newer *Project) ProjectDiff {
+	var diff ProjectDiff
+	switch {
+	case older == nil:
+		diff.Project, diff.Status = newer.Key, StatusAdded
+	case newer == nil:
+		diff.Project, diff.Status = older.Key, StatusRemoved
+	default:
+		diff.Project, diff.Status = newer.Key, StatusUnchanged
+	}
+
+	for _, p := range []*Project{older, newer} {
+		if p != nil && p.Error != "" {
+			diff.Status, diff.Error = StatusUnresolved, p.Error
+			return diff
+		}
+	}
+
+	var before, after *graph
+	if older != nil {
+		before = newGraph(older.DepGraph)
+	}
+	if newer != nil {
+		after = newGraph(newer.DepGraph)
+	}
+	diff.Added, diff.Removed, diff.Changed = diffPackages(before.versions(), after.versions())
+	if before != nil && after != nil {
+		diff.NewPaths = after.newPaths(before)
+		if len(diff.Added) > 0 || len(diff.Removed) > 0 || len(diff.Changed) > 0 || len(diff.NewPaths) > 0 {
+			diff.Status = StatusChanged
+		}
+	}
+	return diff
+}
+
+// diffPackages compares two name to versions maps.
+func diffPackages(before, after map[string][]string) (added, removed []Package, changed []VersionChange) {
+	for _, name := range slices.Sorted(maps.Keys(after)) {
+		old, ok := before[name]
+		switch {
+		case !ok:
+			for _, version := range after[name] {
+				added = append(added, Package{Name: name, Version: version})
+			}
+		case !slices.Equal(old, after[name]):
+			changed = append(changed, VersionChange{Name: name, From: old, To: after[name]})
+		}
+	}
+	for _, name := range slices.Sorted(maps.Keys(before)) {
+		if _, ok := after[name]; ok {
+			continue
+		}
+		for _, version := range before[name] {
+			removed = append(removed, Package{Name: name, Version: version})
+		}
+	}
+	return added, removed, changed
+}
+
+// graph is a dep-graph reduced to packages and the edges between them. The
+// root is keyed as rootKey rather than by ID, so a project whose own version
+// changed still lines up with its previous graph.
+type graph struct {
+	root  depgraph.Pkg
+	pkgs  map[string]depgraph.Pkg
+	edges map[string][]string
+}
+
+const rootKey = ""
+
+func newGraph(dg *depgraph.DepGraph) *graph {
+	g := &graph{pkgs: make(map[string]depgraph.Pkg), edges: make(map[string][]string)}
+	if dg == nil {
+		return g
+	}
+
+	keyByNode := make(map[string]string, len(dg.Graph.Nodes))
+	for _, node := range dg.Graph.Nodes {
+		keyByNode[node.NodeID] = node.PkgID
+	}
+	rootPkgID := keyByNode[dg.Graph.RootNodeID]
+	for nodeID, pkgID := range keyByNode {
+		if pkgID == rootPkgID {
+			keyByNode[nodeID] = rootKey
+		}
+	}
+
+	for _, pkg := range dg.Pkgs {
+		if pkg.ID == rootPkgID {
+			g.root = pkg
+			continue
+		}
+		g.pkgs[pkg.ID] = pkg
+	}
+
+	seen := make(map[[2]string]bool)
+	for _, node := range dg.Graph.Nodes {
+		from := keyByNode[node.NodeID]
+		for _, dep := range node.Deps {
+			to, ok := keyByNode[dep.NodeID]
+			if !ok || seen[[2]string{from, to}] {
+				continue
+			}
+			seen[[2]string{from, to}] = true
+			g.edges[from] = append(g.edges[from], to)
+		}
+	}
+	for from := range g.edges {
+		slices.Sort(g.edges[from])
+	}
+	return g
+}
+
+// versions maps every package name but the root's to its sorted versions.
+func (g *graph) versions() map[string][]string {
+	versions := make(map[string][]string)
+	if g == nil {
+		return versions
+	}
+	for _, pkg := range g.pkgs {
+		versions[pkg.Info.Name] = append(versions[pkg.Info.Name], pkg.Info.Version)
+	}
+	for name := range versions {
+		slices.Sort(versions[name])
+	}
+	return versions
+}
+
+// newPaths returns a shortest path from the root through every edge g has
+// and before does not, sorted. Edges are compared by package name, so a
+// version bump is a change rather than a new path.
+func (g *graph) newPaths(before *graph) [][]string {
+	old := make(map[[2]string]bool)
+	for from, tos := range before.edges {
+		for _, to := range tos {
+			old[[2]string{before.name(from), before.name(to)}] = true
+		}
+	}
+
+	parents := g.shortestPathTree()
+	var paths [][]string
+	for _, from := range slices.Sorted(maps.Keys(g.edges)) {
+		if _, reachable := parents[from]; !reachable {
+			continue
+		}
+		for _, to := range g.edges[from] {
+			if old[[2]string{g.name(from), g.name(to)}] {
+				continue
+			}
+			path := []string{g.label(to)}
+			for key := from; ; key = parents[key] {
+				path = append(path, g.label(key))
+				if key == rootKey {
+					break
+				}
+			}
+			slices.Reverse(path)
+			paths = append(paths, path)
+		}
+	}
+	slices.SortFunc(paths, func(a, b []string) int {
+		return cmp.Or(cmp.Compare(len(a), len(b)), slices.Compare(a, b))
+	})
+	return paths
+}
+
+// shortestPathTree maps every package reachable from the root to its parent
+// on a shortest path from the root.
+func (g *graph) shortestPathTree() map[string]string {
+	parents := map[string]string{rootKey: rootKey}
+	queue := []string{rootKey}
+	for len(queue) > 0 {
+		key := queue[0]
+		queue = queue[1:]
+		for _, dep := range g.edges[key] {
+			if _, seen := parents[dep]; seen {
+				continue
+			}
+			parents[dep] = key
+			queue = append(queue, dep)
+		}
+	}
+	return parents
+}
+
+// name returns the name of the package at key, or rootKey for the root.
+func (g *graph) name(key string) string {
+	if key == rootKey {
+		return rootKey
+	}
+	return g.pkgs[key].Info.Name
+}
+
+func (g *graph) label(key string) string {
+	pkg := g.root
+	if key != rootKey {
+		pkg = g.pkgs[key]
+	}
+	if pkg.Info.Version == "" {
+		return pkg.Info.Name
+	}
+	return pkg.Info.Name + "@" + pkg.Info.Version
+}
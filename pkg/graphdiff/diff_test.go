@@ -0,0 +1,131 @@
+package graphdiff
+
+import (
+	"testing"
+
+	"github.com/snyk/dep-graph/go/pkg/depgraph"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newDepGraph builds a graph rooted at root, with edges given as
+// "from>to" pairs of name@version IDs.
+func newDepGraph(t *testing.T, root string, edges ...string) *depgraph.DepGraph {
+	t.Helper()
+	name, version := splitID(root)
+	builder, err := depgraph.NewBuilder(&depgraph.PkgManager{Name: "npm"}, &depgraph.PkgInfo{Name: name, Version: version})
+	require.NoError(t, err)
+
+	for _, e := range edges {
+		from, to, ok := cut(e, ">")
+		require.True(t, ok, e)
+		for _, id := range []string{from, to} {
+			if id != root {
+				name, version := splitID(id)
+				builder.AddNode(id, &depgraph.PkgInfo{Name: name, Version: version})
+			}
+		}
+		if from == root {
+			from = builder.GetRootNode().NodeID
+		}
+		require.NoError(t, builder.ConnectNodes(from, to))
+	}
+	return builder.Build()
+}
+
+func splitID(id string) (name, version string) {
+	for i := len(id) - 1; i > 0; i-- {
+		if id[i] == '@' {
+			return id[:i], id[i+1:]
+		}
+	}
+	return id, ""
+}
+
+func cut(s, sep string) (before, after string, found bool) {
+	for i := 0; i+len(sep) <= len(s); i++ {
+		if s[i:i+len(sep)] == sep {
+			return s[:i], s[i+len(sep):], true
+		}
+	}
+	return s, "", false
+}
+
+func project(file string, dg *depgraph.DepGraph) Project {
+	return Project{Key: ProjectKey{Type: "npm", NormalisedTargetFile: file}, DepGraph: dg}
+}
+
+func TestDiff_ChangedProject(t *testing.T) {
+	before := newDepGraph(t, "app@1.0.0",
+		"app@1.0.0>express@4.0.0",
+		"express@4.0.0>debug@2.0.0",
+		"app@1.0.0>left-pad@1.0.0",
+	)
+	after := newDepGraph(t, "app@1.1.0",
+		"app@1.1.0>express@4.1.0",
+		"express@4.1.0>debug@2.0.0",
+		"express@4.1.0>ms@2.1.0",
+		"app@1.1.0>debug@2.0.0",
+	)
+
+	report := Diff(
+		[]Project{project("package-lock.json", before)},
+		[]Project{project("package-lock.json", after)},
+	)
+
+	require.Len(t, report.Projects, 1)
+	diff := report.Projects[0]
+	assert.Equal(t, StatusChanged, diff.Status)
+	assert.Equal(t, []Package{{Name: "ms", Version: "2.1.0"}}, diff.Added)
+	assert.Equal(t, []Package{{Name: "left-pad", Version: "1.0.0"}}, diff.Removed)
+	assert.Equal(t, []VersionChange{{Name: "express", From: []string{"4.0.0"}, To: []string{"4.1.0"}}}, diff.Changed)
+	assert.Equal(t, [][]string{
+		{"app@1.1.0", "debug@2.0.0"},
+		{"app@1.1.0", "express@4.1.0", "ms@2.1.0"},
+	}, diff.NewPaths, "a version bump is not a new path, and the root's own version does not matter")
+}
+
+func TestDiff_MatchesProjectsByKey(t *testing.T) {
+	graph := newDepGraph(t, "app@1.0.0", "app@1.0.0>express@4.0.0")
+	from := []Project{
+		project("a/package-lock.json", graph),
+		project("b/package-lock.json", graph),
+		{Key: ProjectKey{NormalisedTargetFile: "c/package-lock.json"}, Error: "failed to resolve"},
+	}
+	to := []Project{
+		project("c/package-lock.json", graph),
+		project("a/package-lock.json", graph),
+		project("d/package-lock.json", graph),
+	}
+
+	report := Diff(from, to)
+
+	statuses := make(map[string]Status)
+	for _, diff := range report.Projects {
+		statuses[diff.Project.NormalisedTargetFile] = diff.Status
+	}
+	assert.Equal(t, map[string]Status{
+		"a/package-lock.json": StatusUnchanged,
+		"b/package-lock.json": StatusRemoved,
+		"c/package-lock.json": StatusUnresolved,
+		"d/package-lock.json": StatusAdded,
+	}, statuses, "c failed to resolve, so it is matched by target file")
+
+	require.Len(t, report.Projects, 4)
+	assert.Equal(t, "b/package-lock.json", report.Projects[3].Project.NormalisedTargetFile, "removed projects come last")
+	assert.Equal(t, []Package{{Name: "express", Version: "4.0.0"}}, report.Projects[3].Removed)
+	assert.Empty(t, report.Projects[2].NewPaths, "added projects have no new paths")
+}
+
+func TestDiff_UnresolvedProject(t *testing.T) {
+	key := ProjectKey{Type: "npm", NormalisedTargetFile: "package-lock.json"}
+
+	report := Diff(
+		[]Project{{Key: key, DepGraph: newDepGraph(t, "app@1.0.0")}},
+		[]Project{{Key: key, Error: "lockfile out of sync"}},
+	)
+
+	require.Len(t, report.Projects, 1)
+	assert.Equal(t, StatusUnresolved, report.Projects[0].Status)
+	assert.Equal(t, "lockfile out of sync", report.Projects[0].Error)
+}
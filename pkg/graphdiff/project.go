@@ -0,0 +1,119 @@
+// Package graphdiff compares the dep-graphs of two scans project by project,
+// reporting the packages each project gained, lost or changed the version of,
+// and the dependency paths it gained.
+package graphdiff
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/snyk/dep-graph/go/pkg/depgraph"
+	"github.com/snyk/error-catalog-golang-public/snyk_errors"
+
+	"github.com/snyk/cli-extension-dep-graph/v2/pkg/depgraph/parsers"
+)
+
+// ProjectKey identifies a project across scans: the identity its plugin
+// reported and its normalised target file.
+type ProjectKey struct {
+	Type                 string `json:"type,omitempty"`
+	TargetFile           string `json:"targetFile,omitempty"`
+	TargetRuntime        string `json:"targetRuntime,omitempty"`
+	RootComponentName    string `json:"rootComponentName,omitempty"`
+	NormalisedTargetFile string `json:"normalisedTargetFile,omitempty"`
+}
+
+// String names the project the way a reader would look for it: by its target
+// file when it has one, otherwise by its root component.
+func (k ProjectKey) String() string {
+	name := k.NormalisedTargetFile
+	if name == "" {
+		name = k.TargetFile
+	}
+	if name == "" {
+		name = k.RootComponentName
+	}
+	if k.Type == "" {
+		return name
+	}
+	return fmt.Sprintf("%s (%s)", name, k.Type)
+}
+
+// Project is one project of a scan: its dep-graph, or why it has none.
+type Project struct {
+	Key      ProjectKey
+	DepGraph *depgraph.DepGraph
+	Error    string
+}
+
+// ProjectsFromOutputs converts dep-graph outputs, as the depgraph workflow
+// returns them and --print-output-jsonl-with-errors saves them, to projects.
+func ProjectsFromOutputs(outputs []parsers.DepGraphOutput) ([]Project, error) {
+	projects := make([]Project, 0, len(outputs))
+	for i := range outputs {
+		output := &outputs[i]
+		project := Project{Key: ProjectKey{NormalisedTargetFile: output.NormalisedTargetFile}}
+		if output.TargetFileFromPlugin != nil {
+			project.Key.TargetFile = *output.TargetFileFromPlugin
+		}
+		if output.TargetRuntime != nil {
+			project.Key.TargetRuntime = *output.TargetRuntime
+		}
+
+		if len(output.DepGraph) > 0 && string(output.DepGraph) != "null" {
+			dg, err := depgraph.UnmarshalJSON(output.DepGraph)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse dep-graph for %q: %w", output.NormalisedTargetFile, err)
+			}
+			project.DepGraph = dg
+			project.Key.Type = dg.PkgManager.Name
+			if root, ok := rootPkg(dg); ok {
+				project.Key.RootComponentName = root.Info.Name
+			}
+		}
+
+		if len(output.Error) > 0 && string(output.Error) != "null" {
+			project.Error = errorMessage(output.Error)
+		} else if project.DepGraph == nil {
+			project.Error = "no dependency graph"
+		}
+		projects = append(projects, project)
+	}
+	return projects, nil
+}
+
+// ProjectsFromJSONL parses a saved JSONL dep-graph output into projects.
+func ProjectsFromJSONL(data []byte) ([]Project, error) {
+	outputs, err := parsers.NewJSONL().ParseOutput(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse JSONL output: %w", err)
+	}
+	return ProjectsFromOutputs(outputs)
+}
+
+// errorMessage returns the detail of a serialized error catalog error, or
+// the raw JSON if it is not one.
+func errorMessage(raw json.RawMessage) string {
+	errs, err := snyk_errors.FromJSONAPIErrorBytes(raw)
+	if err != nil || len(errs) == 0 {
+		return string(raw)
+	}
+	if errs[0].Detail != "" {
+		return errs[0].Detail
+	}
+	return errs[0].Title
+}
+
+func rootPkg(dg *depgraph.DepGraph) (depgraph.Pkg, bool) {
+	for _, node := range dg.Graph.Nodes {
+		if node.NodeID != dg.Graph.RootNodeID {
+			continue
+		}
+		for _, pkg := range dg.Pkgs {
+			if pkg.ID == node.PkgID {
+				return pkg, true
+			}
+		}
+	}
+	return depgraph.Pkg{}, false
+}
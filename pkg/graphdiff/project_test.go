@@ -0,0 +1,44 @@
+package graphdiff
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProjectsFromJSONL(t *testing.T) {
+	depGraph, err := json.Marshal(newDepGraph(t, "app@1.0.0", "app@1.0.0>express@4.0.0"))
+	require.NoError(t, err)
+	line, err := json.Marshal(map[string]any{
+		"depGraph":             json.RawMessage(depGraph),
+		"normalisedTargetFile": "package-lock.json",
+		"targetFileFromPlugin": "package-lock.json",
+	})
+	require.NoError(t, err)
+	errorLine := `{"normalisedTargetFile":"lib/package-lock.json","error":{"errors":[{"status":"422","detail":"lockfile out of sync"}]}}`
+
+	projects, err := ProjectsFromJSONL([]byte(string(line) + "\n" + errorLine + "\n"))
+	require.NoError(t, err)
+
+	require.Len(t, projects, 2)
+	assert.Equal(t, ProjectKey{
+		Type:                 "npm",
+		TargetFile:           "package-lock.json",
+		RootComponentName:    "app",
+		NormalisedTargetFile: "package-lock.json",
+	}, projects[0].Key)
+	assert.NotNil(t, projects[0].DepGraph)
+	assert.Empty(t, projects[0].Error)
+
+	assert.Equal(t, ProjectKey{NormalisedTargetFile: "lib/package-lock.json"}, projects[1].Key)
+	assert.Nil(t, projects[1].DepGraph)
+	assert.NotEmpty(t, projects[1].Error)
+}
+
+func TestProjectKey_String(t *testing.T) {
+	assert.Equal(t, "lib/package-lock.json (npm)", ProjectKey{Type: "npm", NormalisedTargetFile: "lib/package-lock.json", RootComponentName: "lib"}.String())
+	assert.Equal(t, "app (npm)", ProjectKey{Type: "npm", RootComponentName: "app"}.String())
+	assert.Equal(t, "Pipfile", ProjectKey{TargetFile: "Pipfile"}.String())
+}
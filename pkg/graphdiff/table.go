@@ -0,0 +1,60 @@
+package graphdiff
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+)
+
+const pathSeparator = " > "
+
+// WriteTable writes report for people: one section per project, listing its
+// package changes as a table followed by its new paths, and a summary line.
+func WriteTable(w io.Writer, report Report) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	counts := make(map[Status]int)
+
+	for i, diff := range report.Projects {
+		counts[diff.Status]++
+		if i > 0 {
+			fmt.Fprintln(tw)
+		}
+		fmt.Fprintf(tw, "%s: %s\n", diff.Project, diff.Status)
+		if diff.Error != "" {
+			fmt.Fprintf(tw, "  %s\n", diff.Error)
+			continue
+		}
+
+		if len(diff.Added)+len(diff.Removed)+len(diff.Changed) > 0 {
+			fmt.Fprintln(tw, "  CHANGE\tPACKAGE\tFROM\tTO")
+			for _, pkg := range diff.Added {
+				fmt.Fprintf(tw, "  added\t%s\t\t%s\n", pkg.Name, pkg.Version)
+			}
+			for _, pkg := range diff.Removed {
+				fmt.Fprintf(tw, "  removed\t%s\t%s\t\n", pkg.Name, pkg.Version)
+			}
+			for _, change := range diff.Changed {
+				fmt.Fprintf(tw, "  changed\t%s\t%s\t%s\n", change.Name, strings.Join(change.From, ", "), strings.Join(change.To, ", "))
+			}
+		}
+
+		if len(diff.NewPaths) > 0 {
+			fmt.Fprintln(tw, "  New paths:")
+			for _, path := range diff.NewPaths {
+				fmt.Fprintf(tw, "    %s\n", strings.Join(path, pathSeparator))
+			}
+		}
+	}
+
+	if len(report.Projects) > 0 {
+		fmt.Fprintln(tw)
+	}
+	fmt.Fprintf(tw, "%d projects: %d changed, %d added, %d removed, %d unchanged, %d unresolved\n",
+		len(report.Projects), counts[StatusChanged], counts[StatusAdded], counts[StatusRemoved], counts[StatusUnchanged], counts[StatusUnresolved])
+
+	if err := tw.Flush(); err != nil {
+		return fmt.Errorf("failed to write diff table: %w", err)
+	}
+	return nil
+}
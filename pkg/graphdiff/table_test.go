@@ -0,0 +1,46 @@
+package graphdiff
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteTable(t *testing.T) {
+	report := Report{Projects: []ProjectDiff{
+		{
+			Project: ProjectKey{Type: "npm", NormalisedTargetFile: "package-lock.json"},
+			Status:  StatusChanged,
+			Added:   []Package{{Name: "ms", Version: "2.1.0"}},
+			Removed: []Package{{Name: "left-pad", Version: "1.0.0"}},
+			Changed: []VersionChange{{Name: "express", From: []string{"4.0.0"}, To: []string{"4.1.0"}}},
+			NewPaths: [][]string{
+				{"app@1.1.0", "express@4.1.0", "ms@2.1.0"},
+			},
+		},
+		{
+			Project: ProjectKey{Type: "pip", NormalisedTargetFile: "requirements.txt"},
+			Status:  StatusUnresolved,
+			Error:   "python not found",
+		},
+	}}
+
+	var buf bytes.Buffer
+	require.NoError(t, WriteTable(&buf, report))
+
+	assert.Equal(t, `package-lock.json (npm): changed
+  CHANGE   PACKAGE   FROM   TO
+  added    ms               2.1.0
+  removed  left-pad  1.0.0  
+  changed  express   4.0.0  4.1.0
+  New paths:
+    app@1.1.0 > express@4.1.0 > ms@2.1.0
+
+requirements.txt (pip): unresolved
+  python not found
+
+2 projects: 1 changed, 0 added, 0 removed, 0 unchanged, 1 unresolved
+`, buf.String())
+}
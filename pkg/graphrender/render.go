@@ -0,0 +1,120 @@
+package graphrender
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/snyk/dep-graph/go/pkg/depgraph"
+)
+
+const highlightColor = "#d62728"
+
+// Render writes dg to w as a diagram in format.
+func Render(w io.Writer, dg *depgraph.DepGraph, format Format, opts Options) error {
+	v, err := newView(dg, opts)
+	if err != nil {
+		return err
+	}
+
+	bw := bufio.NewWriter(w)
+	switch format {
+	case FormatDOT:
+		writeDOT(bw, v)
+	case FormatMermaid:
+		writeMermaid(bw, v)
+	default:
+		return fmt.Errorf("unsupported graph format %q", format)
+	}
+	if err := bw.Flush(); err != nil {
+		return fmt.Errorf("failed to write %s graph: %w", format, err)
+	}
+	return nil
+}
+
+func writeDOT(w io.Writer, v *view) {
+	fmt.Fprintf(w, "digraph %s {\n", strconv.Quote(v.nodes[0].label))
+	fmt.Fprintln(w, "  rankdir=LR;")
+	fmt.Fprintln(w, "  node [shape=box];")
+	for i, node := range v.nodes {
+		attrs := []string{"label=" + strconv.Quote(node.label)}
+		var styles []string
+		if node.root {
+			styles = append(styles, "bold")
+		}
+		if node.workspace {
+			attrs = append(attrs, "shape=component")
+		}
+		if node.truncated {
+			styles = append(styles, "dashed")
+		}
+		if node.highlighted {
+			styles = append(styles, "filled")
+			attrs = append(attrs, "fillcolor="+strconv.Quote(highlightColor), "fontcolor=white")
+		}
+		if len(styles) > 0 {
+			attrs = append(attrs, "style="+strconv.Quote(strings.Join(styles, ",")))
+		}
+		fmt.Fprintf(w, "  n%d [%s];\n", i, strings.Join(attrs, ", "))
+	}
+	for _, edge := range v.edges {
+		if edge.highlighted {
+			fmt.Fprintf(w, "  n%d -> n%d [color=%s, penwidth=2];\n", edge.from, edge.to, strconv.Quote(highlightColor))
+		} else {
+			fmt.Fprintf(w, "  n%d -> n%d;\n", edge.from, edge.to)
+		}
+	}
+	fmt.Fprintln(w, "}")
+}
+
+func writeMermaid(w io.Writer, v *view) {
+	fmt.Fprintln(w, "graph LR")
+	var workspace, truncated, highlighted []string
+	for i, node := range v.nodes {
+		id := fmt.Sprintf("n%d", i)
+		label := mermaidLabel(node.label)
+		if node.workspace {
+			fmt.Fprintf(w, "  %s[[%s]]\n", id, label)
+			workspace = append(workspace, id)
+		} else {
+			fmt.Fprintf(w, "  %s[%s]\n", id, label)
+		}
+		if node.truncated {
+			truncated = append(truncated, id)
+		}
+		if node.highlighted {
+			highlighted = append(highlighted, id)
+		}
+	}
+
+	var highlightedEdges []string
+	for i, edge := range v.edges {
+		fmt.Fprintf(w, "  n%d --> n%d\n", edge.from, edge.to)
+		if edge.highlighted {
+			highlightedEdges = append(highlightedEdges, strconv.Itoa(i))
+		}
+	}
+
+	writeMermaidClass(w, "workspace", "stroke-width:2px", workspace)
+	writeMermaidClass(w, "truncated", "stroke-dasharray:4 4", truncated)
+	writeMermaidClass(w, "highlight", "fill:"+highlightColor+",color:#fff", highlighted)
+	if len(highlightedEdges) > 0 {
+		fmt.Fprintf(w, "  linkStyle %s stroke:%s,stroke-width:2px\n", strings.Join(highlightedEdges, ","), highlightColor)
+	}
+}
+
+func writeMermaidClass(w io.Writer, name, style string, ids []string) {
+	if len(ids) == 0 {
+		return
+	}
+	fmt.Fprintf(w, "  classDef %s %s\n", name, style)
+	fmt.Fprintf(w, "  class %s %s\n", strings.Join(ids, ","), name)
+}
+
+// mermaidLabel quotes label so characters such as "@" and "/" in package
+// names are not read as Mermaid syntax.
+func mermaidLabel(label string) string {
+	return `"` + strings.ReplaceAll(label, `"`, "#quot;") + `"`
+}
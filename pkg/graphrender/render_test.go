@@ -0,0 +1,143 @@
+package graphrender
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/snyk/dep-graph/go/pkg/depgraph"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/snyk/cli-extension-dep-graph/v2/pkg/graphwhy"
+)
+
+// newMonorepoGraph returns the graph of app@1.0.0, which depends on its
+// workspace sibling @acme/utils and on express, which depends on ms.
+func newMonorepoGraph(t *testing.T) *depgraph.DepGraph {
+	t.Helper()
+	builder, err := depgraph.NewBuilder(&depgraph.PkgManager{Name: "pnpm"}, &depgraph.PkgInfo{Name: "app", Version: "1.0.0"})
+	require.NoError(t, err)
+	root := builder.GetRootNode().NodeID
+	builder.AddNode("@acme/utils@2.0.0", &depgraph.PkgInfo{Name: "@acme/utils", Version: "2.0.0"})
+	builder.AddNode("express@4.0.0", &depgraph.PkgInfo{Name: "express", Version: "4.0.0"})
+	builder.AddNode("ms@2.0.0", &depgraph.PkgInfo{Name: "ms", Version: "2.0.0"})
+	require.NoError(t, builder.ConnectNodes(root, "@acme/utils@2.0.0"))
+	require.NoError(t, builder.ConnectNodes(root, "express@4.0.0"))
+	require.NoError(t, builder.ConnectNodes("@acme/utils@2.0.0", "ms@2.0.0"))
+	require.NoError(t, builder.ConnectNodes("express@4.0.0", "ms@2.0.0"))
+	return builder.Build()
+}
+
+func render(t *testing.T, dg *depgraph.DepGraph, format Format, opts Options) string {
+	t.Helper()
+	var buf bytes.Buffer
+	require.NoError(t, Render(&buf, dg, format, opts))
+	return buf.String()
+}
+
+func TestRender_DOT(t *testing.T) {
+	got := render(t, newMonorepoGraph(t), FormatDOT, Options{})
+
+	assert.Equal(t, `digraph "app@1.0.0" {
+  rankdir=LR;
+  node [shape=box];
+  n0 [label="app@1.0.0", style="bold"];
+  n1 [label="@acme/utils@2.0.0"];
+  n2 [label="express@4.0.0"];
+  n3 [label="ms@2.0.0"];
+  n0 -> n1;
+  n0 -> n2;
+  n1 -> n3;
+  n2 -> n3;
+}
+`, got)
+}
+
+func TestRender_DOTWithOptions(t *testing.T) {
+	got := render(t, newMonorepoGraph(t), FormatDOT, Options{
+		Workspace:         map[string]bool{"app@1.0.0": true, "@acme/utils@2.0.0": true},
+		CollapseWorkspace: true,
+		Highlight:         &graphwhy.Query{Name: "ms"},
+	})
+
+	assert.Equal(t, `digraph "app@1.0.0" {
+  rankdir=LR;
+  node [shape=box];
+  n0 [label="app@1.0.0", style="bold"];
+  n1 [label="@acme/utils@2.0.0", shape=component, style="dashed"];
+  n2 [label="express@4.0.0"];
+  n3 [label="ms@2.0.0", fillcolor="#d62728", fontcolor=white, style="filled"];
+  n0 -> n1 [color="#d62728", penwidth=2];
+  n0 -> n2 [color="#d62728", penwidth=2];
+  n2 -> n3 [color="#d62728", penwidth=2];
+}
+`, got)
+}
+
+func TestRender_Mermaid(t *testing.T) {
+	got := render(t, newMonorepoGraph(t), FormatMermaid, Options{
+		MaxDepth:  1,
+		Workspace: map[string]bool{"@acme/utils@2.0.0": true},
+		Highlight: &graphwhy.Query{Name: "express", Version: "4.0.0"},
+	})
+
+	assert.Equal(t, `graph LR
+  n0["app@1.0.0"]
+  n1[["@acme/utils@2.0.0"]]
+  n2["express@4.0.0"]
+  n0 --> n1
+  n0 --> n2
+  classDef workspace stroke-width:2px
+  class n1 workspace
+  classDef truncated stroke-dasharray:4 4
+  class n1,n2 truncated
+  classDef highlight fill:#d62728,color:#fff
+  class n2 highlight
+  linkStyle 1 stroke:#d62728,stroke-width:2px
+`, got)
+}
+
+func TestRender_FollowsCyclesOnce(t *testing.T) {
+	builder, err := depgraph.NewBuilder(&depgraph.PkgManager{Name: "npm"}, &depgraph.PkgInfo{Name: "app", Version: "1.0.0"})
+	require.NoError(t, err)
+	builder.AddNode("a@1.0.0", &depgraph.PkgInfo{Name: "a", Version: "1.0.0"})
+	builder.AddNode("b@1.0.0", &depgraph.PkgInfo{Name: "b", Version: "1.0.0"})
+	require.NoError(t, builder.ConnectNodes(builder.GetRootNode().NodeID, "a@1.0.0"))
+	require.NoError(t, builder.ConnectNodes("a@1.0.0", "b@1.0.0"))
+	require.NoError(t, builder.ConnectNodes("b@1.0.0", "a@1.0.0"))
+
+	got := render(t, builder.Build(), FormatMermaid, Options{})
+
+	assert.Equal(t, `graph LR
+  n0["app@1.0.0"]
+  n1["a@1.0.0"]
+  n2["b@1.0.0"]
+  n0 --> n1
+  n1 --> n2
+  n2 --> n1
+`, got)
+}
+
+func TestWorkspacePackages(t *testing.T) {
+	bun, err := depgraph.NewBuilder(&depgraph.PkgManager{Name: "bun"}, &depgraph.PkgInfo{Name: "web", Version: "0.1.0"})
+	require.NoError(t, err)
+	bun.AddNode("@acme/logger@workspace:packages/logger", &depgraph.PkgInfo{Name: "@acme/logger", Version: "workspace:packages/logger"})
+	bun.AddNode("react@18.0.0", &depgraph.PkgInfo{Name: "react", Version: "18.0.0"})
+
+	workspace := WorkspacePackages([]*depgraph.DepGraph{newMonorepoGraph(t), bun.Build()})
+
+	assert.Equal(t, map[string]bool{
+		"app@1.0.0":                              true,
+		"web@0.1.0":                              true,
+		"@acme/logger@workspace:packages/logger": true,
+	}, workspace)
+}
+
+func TestParseFormat(t *testing.T) {
+	format, err := ParseFormat("DOT")
+	require.NoError(t, err)
+	assert.Equal(t, FormatDOT, format)
+
+	_, err = ParseFormat("png")
+	assert.ErrorContains(t, err, `unsupported graph format "png"`)
+}
@@ -0,0 +1,196 @@
+// Package graphrender draws dep-graphs as Graphviz DOT or Mermaid diagrams,
+// optionally limited in depth, with workspace-internal packages collapsed and
+// the paths to a chosen package highlighted.
+package graphrender
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/snyk/dep-graph/go/pkg/depgraph"
+
+	"github.com/snyk/cli-extension-dep-graph/v2/pkg/graphwhy"
+)
+
+// Format is a diagram language Render can write.
+type Format string
+
+// Formats Render supports.
+const (
+	FormatDOT     Format = "dot"
+	FormatMermaid Format = "mermaid"
+)
+
+// ParseFormat returns the format name selects.
+func ParseFormat(name string) (Format, error) {
+	switch format := Format(strings.ToLower(name)); format {
+	case FormatDOT, FormatMermaid:
+		return format, nil
+	default:
+		return "", fmt.Errorf("unsupported graph format %q, expected one of: %s, %s", name, FormatDOT, FormatMermaid)
+	}
+}
+
+// Options control what Render draws.
+type Options struct {
+	// MaxDepth hides the packages more than this many dependencies away from
+	// the root. Zero or less means no limit.
+	MaxDepth int
+	// Workspace lists the name@version of the packages that are part of the
+	// scanned workspace rather than installed from a registry. See
+	// WorkspacePackages.
+	Workspace map[string]bool
+	// CollapseWorkspace draws each workspace package without its
+	// dependencies, which belong to the package's own graph.
+	CollapseWorkspace bool
+	// Highlight, when set, marks the packages it matches and the edges
+	// leading to them.
+	Highlight *graphwhy.Query
+}
+
+// Version prefixes package managers give workspace packages they do not
+// resolve to a version of their own.
+var workspaceVersionPrefixes = []string{"workspace:", "link:"}
+
+// WorkspacePackages returns the name@version of the packages in graphs that
+// belong to the workspace: those whose version is a workspace or link
+// reference, as bun reports them, and the roots of the graphs, which pnpm
+// and other workspace-aware plugins emit one per member.
+func WorkspacePackages(graphs []*depgraph.DepGraph) map[string]bool {
+	workspace := make(map[string]bool)
+	for _, dg := range graphs {
+		if root := dg.GetRootPkg(); root != nil {
+			workspace[pkgLabel(root.Info)] = true
+		}
+		for _, pkg := range dg.Pkgs {
+			for _, prefix := range workspaceVersionPrefixes {
+				if strings.HasPrefix(pkg.Info.Version, prefix) {
+					workspace[pkgLabel(pkg.Info)] = true
+				}
+			}
+		}
+	}
+	return workspace
+}
+
+func pkgLabel(info depgraph.PkgInfo) string {
+	if info.Version == "" {
+		return info.Name
+	}
+	return info.Name + "@" + info.Version
+}
+
+// view is the part of a dep-graph a diagram shows, with nodes numbered in
+// breadth-first order from the root.
+type view struct {
+	nodes []viewNode
+	edges []viewEdge
+}
+
+type viewNode struct {
+	label     string
+	root      bool
+	workspace bool
+	// truncated is set when some of the node's dependencies are hidden by
+	// the depth limit or by collapsing it.
+	truncated   bool
+	highlighted bool
+}
+
+type viewEdge struct {
+	from, to    int
+	highlighted bool
+}
+
+func newView(dg *depgraph.DepGraph, opts Options) (*view, error) {
+	nodes := make(map[string]*depgraph.Node, len(dg.Graph.Nodes))
+	for i := range dg.Graph.Nodes {
+		nodes[dg.Graph.Nodes[i].NodeID] = &dg.Graph.Nodes[i]
+	}
+	pkgs := make(map[string]*depgraph.Pkg, len(dg.Pkgs))
+	for i := range dg.Pkgs {
+		pkgs[dg.Pkgs[i].ID] = &dg.Pkgs[i]
+	}
+	root, ok := nodes[dg.Graph.RootNodeID]
+	if !ok {
+		return nil, fmt.Errorf("root node %q not found", dg.Graph.RootNodeID)
+	}
+
+	var highlighted map[string]bool
+	if opts.Highlight != nil {
+		highlighted = leadingTo(dg, func(node *depgraph.Node) bool {
+			pkg, ok := pkgs[node.PkgID]
+			return ok && opts.Highlight.Matches(pkg)
+		})
+	}
+
+	v := &view{}
+	index := map[string]int{}
+	add := func(node *depgraph.Node) int {
+		if i, ok := index[node.NodeID]; ok {
+			return i
+		}
+		vn := viewNode{label: node.PkgID, root: node == root}
+		if pkg, ok := pkgs[node.PkgID]; ok {
+			vn.label = pkgLabel(pkg.Info)
+			vn.workspace = !vn.root && opts.Workspace[vn.label]
+			vn.highlighted = opts.Highlight != nil && opts.Highlight.Matches(pkg)
+		}
+		index[node.NodeID] = len(v.nodes)
+		v.nodes = append(v.nodes, vn)
+		return index[node.NodeID]
+	}
+
+	add(root)
+	depth := map[string]int{root.NodeID: 0}
+	for queue := []*depgraph.Node{root}; len(queue) > 0; queue = queue[1:] {
+		node := queue[0]
+		from := index[node.NodeID]
+		if len(node.Deps) == 0 {
+			continue
+		}
+		if (opts.MaxDepth > 0 && depth[node.NodeID] >= opts.MaxDepth) || (opts.CollapseWorkspace && v.nodes[from].workspace) {
+			v.nodes[from].truncated = true
+			continue
+		}
+		for _, dep := range node.Deps {
+			child, ok := nodes[dep.NodeID]
+			if !ok {
+				return nil, fmt.Errorf("node %q depends on unknown node %q", node.NodeID, dep.NodeID)
+			}
+			if _, seen := depth[child.NodeID]; !seen {
+				depth[child.NodeID] = depth[node.NodeID] + 1
+				queue = append(queue, child)
+			}
+			v.edges = append(v.edges, viewEdge{from: from, to: add(child), highlighted: highlighted[child.NodeID]})
+		}
+	}
+	return v, nil
+}
+
+// leadingTo returns the IDs of the nodes matches selects and of every node
+// they can be reached from.
+func leadingTo(dg *depgraph.DepGraph, matches func(*depgraph.Node) bool) map[string]bool {
+	parents := make(map[string][]string)
+	found := make(map[string]bool)
+	var queue []string
+	for i := range dg.Graph.Nodes {
+		node := &dg.Graph.Nodes[i]
+		for _, dep := range node.Deps {
+			parents[dep.NodeID] = append(parents[dep.NodeID], node.NodeID)
+		}
+		if matches(node) {
+			found[node.NodeID] = true
+			queue = append(queue, node.NodeID)
+		}
+	}
+	for ; len(queue) > 0; queue = queue[1:] {
+		for _, parentID := range parents[queue[0]] {
+			if !found[parentID] {
+				found[parentID] = true
+				queue = append(queue, parentID)
+			}
+		}
+	}
+	return found
+}
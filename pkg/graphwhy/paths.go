@@ -0,0 +1,198 @@
+package graphwhy
+
+import (
+	"github.com/snyk/dep-graph/go/pkg/depgraph"
+
+	"github.com/snyk/cli-extension-dep-graph/v2/pkg/graphdiff"
+)
+
+// Report lists, per project, the paths from its root to the queried package.
+type Report struct {
+	Query    Query          `json:"query"`
+	Projects []ProjectPaths `json:"projects"`
+	// Truncated is set when the search stopped at the path limit, so some
+	// paths are missing.
+	Truncated bool `json:"truncated,omitempty"`
+}
+
+// ProjectPaths is the paths to the queried package in one project, or why
+// the project could not be searched.
+type ProjectPaths struct {
+	Project graphdiff.ProjectKey `json:"project"`
+	Paths   []Path               `json:"paths,omitempty"`
+	Error   string               `json:"error,omitempty"`
+}
+
+// Path is the packages from a project's root to the queried package.
+type Path []Step
+
+// Step is one package on a path. Labels are those of its dep-graph node,
+// such as whether its dependencies were pruned or it failed to resolve.
+type Step struct {
+	Name    string            `json:"name"`
+	Version string            `json:"version,omitempty"`
+	Labels  map[string]string `json:"labels,omitempty"`
+}
+
+// PathCount returns the number of paths in the report.
+func (r Report) PathCount() int {
+	count := 0
+	for _, project := range r.Projects {
+		count += len(project.Paths)
+	}
+	return count
+}
+
+// Explain searches the projects for paths to the package q names. Projects
+// the package is not in are left out, and those without a dep-graph are
+// listed with their error. The search stops after maxPaths paths; zero or
+// less means no limit.
+func Explain(projects []graphdiff.Project, q Query, maxPaths int) Report {
+	report := Report{Query: q, Projects: []ProjectPaths{}}
+	remaining := maxPaths
+	for _, project := range projects {
+		if project.DepGraph == nil {
+			report.Projects = append(report.Projects, ProjectPaths{Project: project.Key, Error: project.Error})
+			continue
+		}
+		if maxPaths > 0 && remaining == 0 {
+			report.Truncated = report.Truncated || hasMatch(project.DepGraph, q)
+			continue
+		}
+
+		paths, truncated := FindPaths(project.DepGraph, q, remaining)
+		report.Truncated = report.Truncated || truncated
+		if maxPaths > 0 {
+			remaining -= len(paths)
+		}
+		if len(paths) > 0 {
+			report.Projects = append(report.Projects, ProjectPaths{Project: project.Key, Paths: paths})
+		}
+	}
+	return report
+}
+
+// FindPaths returns the paths from the root of dg to every node of a package
+// q matches, in depth-first order, and whether it stopped after maxPaths of
+// them. Zero or less means no limit. Paths never visit a node twice, so
+// cycles are followed once.
+func FindPaths(dg *depgraph.DepGraph, q Query, maxPaths int) ([]Path, bool) {
+	f := newFinder(dg, q, maxPaths)
+	root, ok := f.nodes[dg.Graph.RootNodeID]
+	if !ok || !f.leadsToMatch[root.NodeID] {
+		return nil, false
+	}
+	f.walk(root)
+	return f.paths, f.truncated
+}
+
+func hasMatch(dg *depgraph.DepGraph, q Query) bool {
+	for i := range dg.Pkgs {
+		if q.Matches(&dg.Pkgs[i]) {
+			return true
+		}
+	}
+	return false
+}
+
+type finder struct {
+	nodes    map[string]*depgraph.Node
+	pkgs     map[string]*depgraph.Pkg
+	query    Query
+	maxPaths int
+	// leadsToMatch records the nodes a matching node can be reached from,
+	// itself included, so the walk skips subtrees without one.
+	leadsToMatch map[string]bool
+	onPath       map[string]bool
+	path         Path
+	paths        []Path
+	truncated    bool
+}
+
+func newFinder(dg *depgraph.DepGraph, q Query, maxPaths int) *finder {
+	f := &finder{
+		nodes:        make(map[string]*depgraph.Node, len(dg.Graph.Nodes)),
+		pkgs:         make(map[string]*depgraph.Pkg, len(dg.Pkgs)),
+		query:        q,
+		maxPaths:     maxPaths,
+		leadsToMatch: map[string]bool{},
+		onPath:       map[string]bool{},
+	}
+	for i := range dg.Pkgs {
+		f.pkgs[dg.Pkgs[i].ID] = &dg.Pkgs[i]
+	}
+
+	parents := make(map[string][]string)
+	var queue []string
+	for i := range dg.Graph.Nodes {
+		node := &dg.Graph.Nodes[i]
+		f.nodes[node.NodeID] = node
+		for _, dep := range node.Deps {
+			parents[dep.NodeID] = append(parents[dep.NodeID], node.NodeID)
+		}
+		if f.matches(node) {
+			f.leadsToMatch[node.NodeID] = true
+			queue = append(queue, node.NodeID)
+		}
+	}
+	for len(queue) > 0 {
+		nodeID := queue[0]
+		queue = queue[1:]
+		for _, parentID := range parents[nodeID] {
+			if !f.leadsToMatch[parentID] {
+				f.leadsToMatch[parentID] = true
+				queue = append(queue, parentID)
+			}
+		}
+	}
+	return f
+}
+
+func (f *finder) matches(node *depgraph.Node) bool {
+	pkg, ok := f.pkgs[node.PkgID]
+	return ok && f.query.Matches(pkg)
+}
+
+// walk extends the current path with node, recording it if node matches,
+// and returns false once the path limit is reached.
+func (f *finder) walk(node *depgraph.Node) bool {
+	f.onPath[node.NodeID] = true
+	f.path = append(f.path, f.step(node))
+	defer func() {
+		f.path = f.path[:len(f.path)-1]
+		delete(f.onPath, node.NodeID)
+	}()
+
+	if f.matches(node) {
+		if f.maxPaths > 0 && len(f.paths) == f.maxPaths {
+			f.truncated = true
+			return false
+		}
+		f.paths = append(f.paths, append(Path(nil), f.path...))
+	}
+
+	for _, dep := range node.Deps {
+		child, ok := f.nodes[dep.NodeID]
+		if !ok || f.onPath[child.NodeID] || !f.leadsToMatch[child.NodeID] {
+			continue
+		}
+		if !f.walk(child) {
+			return false
+		}
+	}
+	return true
+}
+
+func (f *finder) step(node *depgraph.Node) Step {
+	var step Step
+	if pkg, ok := f.pkgs[node.PkgID]; ok {
+		step.Name = pkg.Info.Name
+		step.Version = pkg.Info.Version
+	} else {
+		step.Name = node.PkgID
+	}
+	if node.Info != nil && len(node.Info.Labels) > 0 {
+		step.Labels = node.Info.Labels
+	}
+	return step
+}
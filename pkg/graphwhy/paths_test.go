@@ -0,0 +1,154 @@
+package graphwhy
+
+import (
+	"testing"
+
+	"github.com/snyk/dep-graph/go/pkg/depgraph"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/snyk/cli-extension-dep-graph/v2/pkg/graphdiff"
+)
+
+// newGraph builds a dep-graph rooted at app@1.0.0 from edges between
+// name@version IDs, with the node infos given per ID.
+func newGraph(t *testing.T, edges [][2]string, infos map[string]*depgraph.NodeInfo) *depgraph.DepGraph {
+	t.Helper()
+	builder, err := depgraph.NewBuilder(&depgraph.PkgManager{Name: "npm"}, &depgraph.PkgInfo{Name: "app", Version: "1.0.0"})
+	require.NoError(t, err)
+	root := builder.GetRootNode().NodeID
+
+	added := map[string]bool{}
+	for _, edge := range edges {
+		for _, id := range edge {
+			if id == root || added[id] {
+				continue
+			}
+			added[id] = true
+			pkg, err := ParseQuery(id)
+			require.NoError(t, err)
+			if info, ok := infos[id]; ok {
+				builder.AddNode(id, &depgraph.PkgInfo{Name: pkg.Name, Version: pkg.Version}, depgraph.WithNodeInfo(info))
+			} else {
+				builder.AddNode(id, &depgraph.PkgInfo{Name: pkg.Name, Version: pkg.Version})
+			}
+		}
+	}
+	for _, edge := range edges {
+		require.NoError(t, builder.ConnectNodes(edge[0], edge[1]))
+	}
+	return builder.Build()
+}
+
+func names(paths []Path) [][]string {
+	out := make([][]string, 0, len(paths))
+	for _, path := range paths {
+		var steps []string
+		for _, step := range path {
+			steps = append(steps, step.Name+"@"+step.Version)
+		}
+		out = append(out, steps)
+	}
+	return out
+}
+
+const root = "root-node"
+
+func TestFindPaths(t *testing.T) {
+	dg := newGraph(t, [][2]string{
+		{root, "express@4.0.0"}, {root, "debug@2.0.0"},
+		{"express@4.0.0", "debug@2.0.0"}, {"express@4.0.0", "body-parser@1.0.0"},
+		{"body-parser@1.0.0", "ms@2.0.0"}, {"debug@2.0.0", "ms@2.0.0"},
+		{"body-parser@1.0.0", "ms@2.1.0"},
+	}, nil)
+
+	t.Run("every version", func(t *testing.T) {
+		paths, truncated := FindPaths(dg, Query{Name: "ms"}, 0)
+		assert.False(t, truncated)
+		assert.Equal(t, [][]string{
+			{"app@1.0.0", "express@4.0.0", "debug@2.0.0", "ms@2.0.0"},
+			{"app@1.0.0", "express@4.0.0", "body-parser@1.0.0", "ms@2.0.0"},
+			{"app@1.0.0", "express@4.0.0", "body-parser@1.0.0", "ms@2.1.0"},
+			{"app@1.0.0", "debug@2.0.0", "ms@2.0.0"},
+		}, names(paths))
+	})
+
+	t.Run("one version", func(t *testing.T) {
+		paths, _ := FindPaths(dg, Query{Name: "ms", Version: "2.1.0"}, 0)
+		assert.Equal(t, [][]string{
+			{"app@1.0.0", "express@4.0.0", "body-parser@1.0.0", "ms@2.1.0"},
+		}, names(paths))
+	})
+
+	t.Run("stops at the path limit", func(t *testing.T) {
+		paths, truncated := FindPaths(dg, Query{Name: "ms"}, 2)
+		assert.True(t, truncated)
+		assert.Len(t, paths, 2)
+	})
+
+	t.Run("limit equal to the path count is not truncated", func(t *testing.T) {
+		paths, truncated := FindPaths(dg, Query{Name: "ms"}, 4)
+		assert.False(t, truncated)
+		assert.Len(t, paths, 4)
+	})
+
+	t.Run("absent package", func(t *testing.T) {
+		paths, truncated := FindPaths(dg, Query{Name: "lodash"}, 0)
+		assert.Empty(t, paths)
+		assert.False(t, truncated)
+	})
+}
+
+func TestFindPaths_FollowsCyclesOnce(t *testing.T) {
+	dg := newGraph(t, [][2]string{
+		{root, "a@1.0.0"}, {"a@1.0.0", "b@1.0.0"}, {"b@1.0.0", "a@1.0.0"}, {"b@1.0.0", "c@1.0.0"},
+	}, nil)
+
+	paths, _ := FindPaths(dg, Query{Name: "c"}, 0)
+	assert.Equal(t, [][]string{{"app@1.0.0", "a@1.0.0", "b@1.0.0", "c@1.0.0"}}, names(paths))
+}
+
+func TestFindPaths_KeepsNodeLabels(t *testing.T) {
+	dg := newGraph(t, [][2]string{{root, "a@1.0.0"}, {"a@1.0.0", "b@1.0.0"}}, map[string]*depgraph.NodeInfo{
+		"b@1.0.0": {Labels: map[string]string{"unresolved": "true"}},
+	})
+
+	paths, _ := FindPaths(dg, Query{Name: "b"}, 0)
+	require.Len(t, paths, 1)
+	assert.Nil(t, paths[0][1].Labels)
+	assert.Equal(t, map[string]string{"unresolved": "true"}, paths[0][2].Labels)
+}
+
+func TestExplain(t *testing.T) {
+	withMs := newGraph(t, [][2]string{{root, "debug@2.0.0"}, {"debug@2.0.0", "ms@2.0.0"}, {root, "ms@2.0.0"}}, nil)
+	withoutMs := newGraph(t, [][2]string{{root, "left-pad@1.0.0"}}, nil)
+	projects := []graphdiff.Project{
+		{Key: graphdiff.ProjectKey{NormalisedTargetFile: "a/package.json"}, DepGraph: withMs},
+		{Key: graphdiff.ProjectKey{NormalisedTargetFile: "b/package.json"}, DepGraph: withoutMs},
+		{Key: graphdiff.ProjectKey{NormalisedTargetFile: "c/package.json"}, Error: "lockfile missing"},
+		{Key: graphdiff.ProjectKey{NormalisedTargetFile: "d/package.json"}, DepGraph: withMs},
+	}
+
+	t.Run("lists matching and failed projects", func(t *testing.T) {
+		report := Explain(projects, Query{Name: "ms"}, 0)
+		assert.False(t, report.Truncated)
+		require.Len(t, report.Projects, 3)
+		assert.Equal(t, "a/package.json", report.Projects[0].Project.NormalisedTargetFile)
+		assert.Len(t, report.Projects[0].Paths, 2)
+		assert.Equal(t, "lockfile missing", report.Projects[1].Error)
+		assert.Equal(t, "d/package.json", report.Projects[2].Project.NormalisedTargetFile)
+		assert.Equal(t, 4, report.PathCount())
+	})
+
+	t.Run("the path limit spans projects", func(t *testing.T) {
+		report := Explain(projects, Query{Name: "ms"}, 3)
+		assert.True(t, report.Truncated)
+		assert.Equal(t, 3, report.PathCount())
+	})
+
+	t.Run("a limit reached exactly is not truncated", func(t *testing.T) {
+		report := Explain(projects[:3], Query{Name: "ms"}, 2)
+		assert.False(t, report.Truncated)
+		assert.Equal(t, 2, report.PathCount())
+	})
+}
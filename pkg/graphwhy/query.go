@@ -0,0 +1,48 @@
+// Package graphwhy explains why a package is in a scan: it lists the paths
+// from the root of each project's dep-graph to every occurrence of the
+// package.
+package graphwhy
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/snyk/dep-graph/go/pkg/depgraph"
+)
+
+// Query names the package to explain, optionally pinned to one version.
+type Query struct {
+	Name    string `json:"name"`
+	Version string `json:"version,omitempty"`
+}
+
+// ParseQuery parses a `<name>[@version]` query. A leading "@", as in scoped
+// npm packages, is part of the name.
+func ParseQuery(s string) (Query, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return Query{}, errors.New("package name is empty")
+	}
+	i := strings.LastIndex(s, "@")
+	if i <= 0 {
+		return Query{Name: s}, nil
+	}
+	q := Query{Name: s[:i], Version: s[i+1:]}
+	if q.Version == "" {
+		return Query{}, errors.New("version after @ is empty")
+	}
+	return q, nil
+}
+
+// String returns the query in the form ParseQuery reads.
+func (q Query) String() string {
+	if q.Version == "" {
+		return q.Name
+	}
+	return q.Name + "@" + q.Version
+}
+
+// Matches reports whether pkg is the package q names.
+func (q Query) Matches(pkg *depgraph.Pkg) bool {
+	return pkg.Info.Name == q.Name && (q.Version == "" || pkg.Info.Version == q.Version)
+}
@@ -0,0 +1,35 @@
+package graphwhy
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseQuery(t *testing.T) {
+	for _, tc := range []struct {
+		input string
+		want  Query
+	}{
+		{input: "lodash", want: Query{Name: "lodash"}},
+		{input: "lodash@4.17.21", want: Query{Name: "lodash", Version: "4.17.21"}},
+		{input: "@babel/core", want: Query{Name: "@babel/core"}},
+		{input: "@babel/core@7.0.0", want: Query{Name: "@babel/core", Version: "7.0.0"}},
+		{input: "com.google.guava:guava@32.1.0-jre", want: Query{Name: "com.google.guava:guava", Version: "32.1.0-jre"}},
+	} {
+		t.Run(tc.input, func(t *testing.T) {
+			q, err := ParseQuery(tc.input)
+			require.NoError(t, err)
+			assert.Equal(t, tc.want, q)
+			assert.Equal(t, tc.input, q.String())
+		})
+	}
+}
+
+func TestParseQuery_Invalid(t *testing.T) {
+	for _, input := range []string{"", "  ", "lodash@"} {
+		_, err := ParseQuery(input)
+		assert.Error(t, err, "input %q", input)
+	}
+}
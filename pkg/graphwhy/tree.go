@@ -0,0 +1,104 @@
+package graphwhy
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// WriteTree writes report for people: per project, its paths merged into a
+// tree rooted at the project's root package, and a summary line.
+func WriteTree(w io.Writer, report Report) error {
+	bw := bufio.NewWriter(w)
+	searched := 0
+	for _, project := range report.Projects {
+		if project.Error != "" {
+			fmt.Fprintf(bw, "%s: not searched: %s\n\n", project.Project, project.Error)
+			continue
+		}
+		searched++
+		fmt.Fprintf(bw, "%s: %s\n", project.Project, plural(len(project.Paths), "path"))
+		root := &treeNode{}
+		for _, path := range project.Paths {
+			root.insert(path)
+		}
+		for _, child := range root.children {
+			child.write(bw, "  ", "  ")
+		}
+		fmt.Fprintln(bw)
+	}
+
+	if searched == 0 {
+		fmt.Fprintf(bw, "%s is not a dependency of any project\n", report.Query)
+	} else {
+		fmt.Fprintf(bw, "%s to %s in %s\n", plural(report.PathCount(), "path"), report.Query, plural(searched, "project"))
+	}
+	if report.Truncated {
+		fmt.Fprintln(bw, "Stopped at the path limit; raise --max-paths to list more.")
+	}
+
+	if err := bw.Flush(); err != nil {
+		return fmt.Errorf("failed to write why tree: %w", err)
+	}
+	return nil
+}
+
+// treeNode merges paths sharing a prefix, so each package is written once
+// per distinct route to it.
+type treeNode struct {
+	label    string
+	children []*treeNode
+}
+
+func (n *treeNode) insert(path Path) {
+	if len(path) == 0 {
+		return
+	}
+	label := stepLabel(path[0])
+	for _, child := range n.children {
+		if child.label == label {
+			child.insert(path[1:])
+			return
+		}
+	}
+	child := &treeNode{label: label}
+	n.children = append(n.children, child)
+	child.insert(path[1:])
+}
+
+func (n *treeNode) write(w io.Writer, prefix, childPrefix string) {
+	fmt.Fprintf(w, "%s%s\n", prefix, n.label)
+	for i, child := range n.children {
+		if i == len(n.children)-1 {
+			child.write(w, childPrefix+"└─ ", childPrefix+"   ")
+		} else {
+			child.write(w, childPrefix+"├─ ", childPrefix+"│  ")
+		}
+	}
+}
+
+// stepLabel returns step as name@version followed by its node labels.
+func stepLabel(step Step) string {
+	label := step.Name
+	if step.Version != "" {
+		label += "@" + step.Version
+	}
+	if len(step.Labels) == 0 {
+		return label
+	}
+	labels := make([]string, 0, len(step.Labels))
+	for _, key := range slices.Sorted(maps.Keys(step.Labels)) {
+		labels = append(labels, key+"="+step.Labels[key])
+	}
+	return fmt.Sprintf("%s [%s]", label, strings.Join(labels, ", "))
+}
+
+func plural(n int, noun string) string {
+	if n == 1 {
+		return fmt.Sprintf("1 %s", noun)
+	}
+	return fmt.Sprintf("%d %ss", n, noun)
+}
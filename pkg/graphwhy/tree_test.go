@@ -0,0 +1,88 @@
+package graphwhy
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/snyk/dep-graph/go/pkg/depgraph"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/snyk/cli-extension-dep-graph/v2/pkg/graphdiff"
+)
+
+func TestWriteTree(t *testing.T) {
+	app := Step{Name: "app", Version: "1.0.0"}
+	express := Step{Name: "express", Version: "4.0.0"}
+	ms := Step{Name: "ms", Version: "2.0.0"}
+	report := Report{
+		Query: Query{Name: "ms"},
+		Projects: []ProjectPaths{
+			{
+				Project: graphdiff.ProjectKey{Type: "npm", NormalisedTargetFile: "package.json"},
+				Paths: []Path{
+					{app, express, {Name: "debug", Version: "2.0.0"}, ms},
+					{app, express, {Name: "body-parser", Version: "1.0.0", Labels: map[string]string{"pruned": "true", "scope": "dev"}}, ms},
+					{app, ms},
+				},
+			},
+			{
+				Project: graphdiff.ProjectKey{Type: "pip", NormalisedTargetFile: "requirements.txt"},
+				Error:   "python not found",
+			},
+		},
+		Truncated: true,
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, WriteTree(&buf, report))
+
+	assert.Equal(t, `package.json (npm): 3 paths
+  app@1.0.0
+  ├─ express@4.0.0
+  │  ├─ debug@2.0.0
+  │  │  └─ ms@2.0.0
+  │  └─ body-parser@1.0.0 [pruned=true, scope=dev]
+  │     └─ ms@2.0.0
+  └─ ms@2.0.0
+
+requirements.txt (pip): not searched: python not found
+
+3 paths to ms in 1 project
+Stopped at the path limit; raise --max-paths to list more.
+`, buf.String())
+}
+
+func TestWriteTree_NoPaths(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, WriteTree(&buf, Report{Query: Query{Name: "ms", Version: "2.0.0"}}))
+
+	assert.Equal(t, "ms@2.0.0 is not a dependency of any project\n", buf.String())
+}
+
+func TestWriteTree_ShowsPluginLabels(t *testing.T) {
+	// Gradle labels unresolved dependencies with why they failed, and bun
+	// labels packages with their dependents, for the why workflow.
+	dg := newGraph(t, [][2]string{{root, "lib@1.0.0"}, {"lib@1.0.0", "missing@2.0.0"}}, map[string]*depgraph.NodeInfo{
+		"lib@1.0.0":     {Labels: map[string]string{"dependents": "other@1.0.0"}},
+		"missing@2.0.0": {Labels: map[string]string{"unresolved": "true", "reason": "Could not find missing-2.0.0.jar"}},
+	})
+	report := Explain([]graphdiff.Project{{Key: graphdiff.ProjectKey{Type: "gradle", NormalisedTargetFile: "build.gradle"}, DepGraph: dg}},
+		Query{Name: "missing"}, 0)
+
+	var buf bytes.Buffer
+	require.NoError(t, WriteTree(&buf, report))
+	assert.Equal(t, `build.gradle (gradle): 1 path
+  app@1.0.0
+  └─ lib@1.0.0 [dependents=other@1.0.0]
+     └─ missing@2.0.0 [reason=Could not find missing-2.0.0.jar, unresolved=true]
+
+1 path to missing in 1 project
+`, buf.String())
+
+	reportJSON, err := json.Marshal(report)
+	require.NoError(t, err)
+	assert.Contains(t, string(reportJSON), `"labels":{"reason":"Could not find missing-2.0.0.jar","unresolved":"true"}`)
+	assert.Contains(t, string(reportJSON), `"labels":{"dependents":"other@1.0.0"}`)
+}
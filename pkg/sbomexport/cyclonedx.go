@@ -0,0 +1,265 @@
+package sbomexport
+
+import (
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/snyk/dep-graph/go/pkg/depgraph"
+
+	"github.com/snyk/cli-extension-dep-graph/v2/pkg/ecosystems"
+)
+
+// CycloneDX spec versions WriteCycloneDX can produce.
+const (
+	CycloneDX15 = "1.5"
+	CycloneDX16 = "1.6"
+)
+
+// Property names recorded on project root components.
+const (
+	propertyProjectType            = "snyk:projectType"
+	propertyTargetFile             = "snyk:targetFile"
+	propertyPluginName             = "snyk:pluginName"
+	propertyProcessedFile          = "snyk:processedFile"
+	propertyVersionBuildInfoPrefix = "snyk:versionBuildInfo:"
+)
+
+const toolName = "snyk-cli-extension-dep-graph"
+
+// cdxAlgorithms maps purl checksum algorithms to CycloneDX hash algorithms.
+// Checksums with other algorithms are left out.
+var cdxAlgorithms = map[string]string{
+	"md5":    "MD5",
+	"sha1":   "SHA-1",
+	"sha256": "SHA-256",
+	"sha384": "SHA-384",
+	"sha512": "SHA-512",
+}
+
+type cdxBOM struct {
+	BOMFormat    string          `json:"bomFormat"`
+	SpecVersion  string          `json:"specVersion"`
+	SerialNumber string          `json:"serialNumber,omitempty"`
+	Version      int             `json:"version"`
+	Metadata     cdxMetadata     `json:"metadata"`
+	Components   []cdxComponent  `json:"components"`
+	Dependencies []cdxDependency `json:"dependencies"`
+}
+
+type cdxMetadata struct {
+	Timestamp string        `json:"timestamp,omitempty"`
+	Tools     *cdxTools     `json:"tools,omitempty"`
+	Component *cdxComponent `json:"component,omitempty"`
+}
+
+type cdxTools struct {
+	Components []cdxComponent `json:"components"`
+}
+
+type cdxComponent struct {
+	Type       string         `json:"type"`
+	BOMRef     string         `json:"bom-ref,omitempty"`
+	Name       string         `json:"name"`
+	Version    string         `json:"version,omitempty"`
+	PURL       string         `json:"purl,omitempty"`
+	Hashes     []cdxHash      `json:"hashes,omitempty"`
+	Properties []cdxProperty  `json:"properties,omitempty"`
+	Components []cdxComponent `json:"components,omitempty"`
+}
+
+type cdxHash struct {
+	Alg     string `json:"alg"`
+	Content string `json:"content"`
+}
+
+type cdxProperty struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type cdxDependency struct {
+	Ref       string   `json:"ref"`
+	DependsOn []string `json:"dependsOn,omitempty"`
+}
+
+// WriteCycloneDX writes one CycloneDX JSON document covering the dep-graphs
+// of every result that has one. Each project's root package becomes a
+// metadata component carrying the project's descriptor, processed files and
+// version build info as properties; every other package becomes a component
+// identified by its purl, and every edge a dependency. With more than one
+// project, the roots are grouped under a metadata component named subject.
+func WriteCycloneDX(w io.Writer, results []ecosystems.SCAResult, specVersion, subject string) error {
+	if specVersion != CycloneDX15 && specVersion != CycloneDX16 {
+		return fmt.Errorf("unsupported CycloneDX spec version %q", specVersion)
+	}
+
+	bom := newCycloneDX(newProjects(results), specVersion, subject)
+	bom.SerialNumber = "urn:uuid:" + newUUID()
+	bom.Metadata.Timestamp = time.Now().UTC().Format(time.RFC3339)
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(bom); err != nil {
+		return fmt.Errorf("failed to encode CycloneDX document: %w", err)
+	}
+	return nil
+}
+
+func newCycloneDX(projects []project, specVersion, subject string) cdxBOM {
+	bom := cdxBOM{
+		BOMFormat:    "CycloneDX",
+		SpecVersion:  specVersion,
+		Version:      1,
+		Metadata:     cdxMetadata{Tools: &cdxTools{Components: []cdxComponent{{Type: "application", Name: toolName}}}},
+		Components:   []cdxComponent{},
+		Dependencies: []cdxDependency{},
+	}
+
+	deps := newDependencySet()
+	componentRefs := make(map[string]bool)
+	rootRefs := make(map[string]bool)
+	roots := make([]cdxComponent, 0, len(projects))
+
+	for i, p := range projects {
+		rootRef := "project:" + p.name()
+		if rootRefs[rootRef] {
+			rootRef += "#" + strconv.Itoa(i)
+		}
+		rootRefs[rootRef] = true
+		roots = append(roots, cdxComponent{
+			Type:       "application",
+			BOMRef:     rootRef,
+			Name:       p.root.Info.Name,
+			Version:    p.root.Info.Version,
+			PURL:       purlOf(p.pkgManager, p.root.Info),
+			Properties: projectProperties(p.result),
+		})
+
+		refs := map[string]string{p.root.ID: rootRef}
+		for _, pkg := range p.packages {
+			ref := purlOf(p.pkgManager, pkg.Info)
+			refs[pkg.ID] = ref
+			if componentRefs[ref] {
+				continue
+			}
+			componentRefs[ref] = true
+			bom.Components = append(bom.Components, cdxComponent{
+				Type:    "library",
+				BOMRef:  ref,
+				Name:    pkg.Info.Name,
+				Version: pkg.Info.Version,
+				PURL:    ref,
+				Hashes:  hashesOf(ref),
+			})
+		}
+
+		for _, pkg := range append([]depgraph.Pkg{p.root}, p.packages...) {
+			dependsOn := make([]string, 0, len(p.dependsOn[pkg.ID]))
+			for _, id := range p.dependsOn[pkg.ID] {
+				dependsOn = append(dependsOn, refs[id])
+			}
+			deps.add(refs[pkg.ID], dependsOn...)
+		}
+	}
+
+	switch len(roots) {
+	case 0:
+	case 1:
+		bom.Metadata.Component = &roots[0]
+	default:
+		aggregate := cdxComponent{Type: "application", BOMRef: "scan:" + subject, Name: subject, Components: roots}
+		bom.Metadata.Component = &aggregate
+		for _, root := range roots {
+			deps.add(aggregate.BOMRef, root.BOMRef)
+		}
+	}
+
+	bom.Dependencies = deps.list()
+	return bom
+}
+
+// projectProperties records what the plugin reported about a project beyond
+// its dep-graph.
+func projectProperties(result ecosystems.SCAResult) []cdxProperty {
+	var props []cdxProperty
+	if projectType := result.ProjectDescriptor.Identity.ProjectType; projectType != "" {
+		props = append(props, cdxProperty{Name: propertyProjectType, Value: projectType})
+	}
+	if targetFile := result.ProjectDescriptor.GetTargetFile(); targetFile != "" {
+		props = append(props, cdxProperty{Name: propertyTargetFile, Value: targetFile})
+	}
+	for _, file := range result.ProcessedFiles {
+		props = append(props, cdxProperty{Name: propertyProcessedFile, Value: file})
+	}
+	if metadata := result.ResolverMetadata; metadata != nil {
+		if metadata.PluginName != "" {
+			props = append(props, cdxProperty{Name: propertyPluginName, Value: metadata.PluginName})
+		}
+		for _, key := range slices.Sorted(maps.Keys(metadata.VersionBuildInfo)) {
+			props = append(props, cdxProperty{Name: propertyVersionBuildInfoPrefix + key, Value: metadata.VersionBuildInfo[key]})
+		}
+	}
+	return props
+}
+
+// hashesOf returns the checksums the plugin recorded on purl as CycloneDX
+// hashes.
+func hashesOf(purl string) []cdxHash {
+	var hashes []cdxHash
+	for _, sum := range checksumsOf(purl) {
+		if alg, ok := cdxAlgorithms[sum.algorithm]; ok {
+			hashes = append(hashes, cdxHash{Alg: alg, Content: sum.value})
+		}
+	}
+	return hashes
+}
+
+// dependencySet accumulates CycloneDX dependencies, keeping the order refs
+// were first added in and deduping edges, so a package shared by several
+// projects gets one entry with the union of its dependencies.
+type dependencySet struct {
+	order []string
+	edges map[string][]string
+	seen  map[[2]string]bool
+}
+
+func newDependencySet() *dependencySet {
+	return &dependencySet{edges: make(map[string][]string), seen: make(map[[2]string]bool)}
+}
+
+func (s *dependencySet) add(ref string, dependsOn ...string) {
+	if _, ok := s.edges[ref]; !ok {
+		s.order = append(s.order, ref)
+		s.edges[ref] = nil
+	}
+	for _, dep := range dependsOn {
+		if s.seen[[2]string{ref, dep}] {
+			continue
+		}
+		s.seen[[2]string{ref, dep}] = true
+		s.edges[ref] = append(s.edges[ref], dep)
+	}
+}
+
+func (s *dependencySet) list() []cdxDependency {
+	deps := make([]cdxDependency, 0, len(s.order))
+	for _, ref := range s.order {
+		deps = append(deps, cdxDependency{Ref: ref, DependsOn: s.edges[ref]})
+	}
+	return deps
+}
+
+// newUUID returns a random (version 4) UUID.
+func newUUID() string {
+	var b [16]byte
+	_, _ = rand.Read(b[:]) //nolint:errcheck // crypto/rand.Read never returns an error
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
@@ -0,0 +1,106 @@
+package sbomexport
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/snyk/dep-graph/go/pkg/depgraph"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/snyk/cli-extension-dep-graph/v2/pkg/ecosystems"
+)
+
+func TestNewCycloneDX_SingleProject(t *testing.T) {
+	result := newResult(t, "pnpm", "pnpm-lock.yaml", depgraph.PkgInfo{Name: "app", Version: "1.0.0"},
+		edge{"app@1.0.0", "express@4.0.0"},
+		edge{"express@4.0.0", "debug@2.0.0"},
+		edge{"app@1.0.0", "debug@2.0.0"},
+	)
+	result.ResolverMetadata.VersionBuildInfo = map[string]string{"pnpmVersion": "9.0.0"}
+
+	bom := newCycloneDX(newProjects([]ecosystems.SCAResult{result}), CycloneDX16, "repo")
+
+	assert.Equal(t, "CycloneDX", bom.BOMFormat)
+	assert.Equal(t, "1.6", bom.SpecVersion)
+
+	require.NotNil(t, bom.Metadata.Component)
+	root := *bom.Metadata.Component
+	assert.Equal(t, "project:pnpm-lock.yaml", root.BOMRef)
+	assert.Equal(t, "app", root.Name)
+	assert.Equal(t, "pkg:npm/app@1.0.0", root.PURL)
+	assert.Equal(t, []cdxProperty{
+		{Name: "snyk:projectType", Value: "pnpm"},
+		{Name: "snyk:targetFile", Value: "pnpm-lock.yaml"},
+		{Name: "snyk:processedFile", Value: "pnpm-lock.yaml"},
+		{Name: "snyk:pluginName", Value: "pnpm"},
+		{Name: "snyk:versionBuildInfo:pnpmVersion", Value: "9.0.0"},
+	}, root.Properties)
+
+	assert.Equal(t, []cdxComponent{
+		{Type: "library", BOMRef: "pkg:npm/express@4.0.0", Name: "express", Version: "4.0.0", PURL: "pkg:npm/express@4.0.0"},
+		{Type: "library", BOMRef: "pkg:npm/debug@2.0.0", Name: "debug", Version: "2.0.0", PURL: "pkg:npm/debug@2.0.0"},
+	}, bom.Components)
+
+	assert.Equal(t, []cdxDependency{
+		{Ref: "project:pnpm-lock.yaml", DependsOn: []string{"pkg:npm/express@4.0.0", "pkg:npm/debug@2.0.0"}},
+		{Ref: "pkg:npm/express@4.0.0", DependsOn: []string{"pkg:npm/debug@2.0.0"}},
+		{Ref: "pkg:npm/debug@2.0.0"},
+	}, bom.Dependencies)
+}
+
+func TestNewCycloneDX_MultipleProjectsShareComponents(t *testing.T) {
+	results := []ecosystems.SCAResult{
+		newResult(t, "cargo", "Cargo.lock", depgraph.PkgInfo{Name: "cli", Version: "0.1.0"}, edge{"cli@0.1.0", "serde@1.0.0"}),
+		newResult(t, "cargo", "lib/Cargo.lock", depgraph.PkgInfo{Name: "lib", Version: "0.1.0"}, edge{"lib@0.1.0", "serde@1.0.0"}),
+		{Error: errors.New("failed")},
+	}
+
+	bom := newCycloneDX(newProjects(results), CycloneDX15, "repo")
+
+	require.NotNil(t, bom.Metadata.Component)
+	assert.Equal(t, "repo", bom.Metadata.Component.Name)
+	require.Len(t, bom.Metadata.Component.Components, 2)
+	assert.Equal(t, "project:Cargo.lock", bom.Metadata.Component.Components[0].BOMRef)
+	assert.Equal(t, "project:lib/Cargo.lock", bom.Metadata.Component.Components[1].BOMRef)
+
+	require.Len(t, bom.Components, 1, "a package shared by two projects is one component")
+	assert.Equal(t, "pkg:cargo/serde@1.0.0", bom.Components[0].PURL)
+
+	assert.Contains(t, bom.Dependencies, cdxDependency{
+		Ref:       "scan:repo",
+		DependsOn: []string{"project:Cargo.lock", "project:lib/Cargo.lock"},
+	})
+	assert.Contains(t, bom.Dependencies, cdxDependency{Ref: "project:lib/Cargo.lock", DependsOn: []string{"pkg:cargo/serde@1.0.0"}})
+}
+
+func TestWriteCycloneDX(t *testing.T) {
+	result := newResult(t, "uv", "uv.lock", depgraph.PkgInfo{Name: "app", Version: "1.0.0"}, edge{"app@1.0.0", "idna@3.6"})
+
+	var buf bytes.Buffer
+	require.NoError(t, WriteCycloneDX(&buf, []ecosystems.SCAResult{result}, CycloneDX16, "repo"))
+
+	var doc map[string]any
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &doc))
+	assert.Equal(t, "CycloneDX", doc["bomFormat"])
+	assert.Equal(t, "1.6", doc["specVersion"])
+	assert.Regexp(t, `^urn:uuid:[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`, doc["serialNumber"])
+	assert.NotEmpty(t, doc["metadata"].(map[string]any)["timestamp"])
+}
+
+func TestWriteCycloneDX_UnsupportedVersion(t *testing.T) {
+	err := WriteCycloneDX(&bytes.Buffer{}, nil, "1.2", "repo")
+	assert.ErrorContains(t, err, "unsupported CycloneDX spec version")
+}
+
+func TestNewCycloneDX_RecordsChecksumsAsHashes(t *testing.T) {
+	result := newResult(t, "gradle", "build.gradle", depgraph.PkgInfo{Name: "app", Version: "1.0.0"}, edge{"app@1.0.0", "org.example:lib@2.0.0"})
+	result = withPURL(t, result, "org.example:lib@2.0.0", "pkg:maven/org.example/lib@2.0.0?checksum=sha1%3Aabcd%2Cblake3%3Aeeee")
+
+	bom := newCycloneDX(newProjects([]ecosystems.SCAResult{result}), CycloneDX16, "repo")
+
+	require.Len(t, bom.Components, 1)
+	assert.Equal(t, []cdxHash{{Alg: "SHA-1", Content: "abcd"}}, bom.Components[0].Hashes)
+}
@@ -0,0 +1,84 @@
+// Package sbomexport renders the dep-graphs that SCA plugins resolve as
+// standard SBOM documents.
+package sbomexport
+
+import (
+	"github.com/snyk/dep-graph/go/pkg/depgraph"
+
+	"github.com/snyk/cli-extension-dep-graph/v2/pkg/ecosystems"
+)
+
+// project is one resolved dep-graph flattened into packages and edges, the
+// shape every SBOM format is built from.
+type project struct {
+	result     ecosystems.SCAResult
+	pkgManager string
+	root       depgraph.Pkg
+	// packages is every package in the graph except the root, in dep-graph
+	// order.
+	packages []depgraph.Pkg
+	// dependsOn maps a package ID to the IDs of the packages it depends on,
+	// deduped across the nodes that share the package.
+	dependsOn map[string][]string
+}
+
+// newProjects flattens the dep-graphs of results. Results without a
+// dep-graph, errored ones included, have nothing to export and are skipped.
+func newProjects(results []ecosystems.SCAResult) []project {
+	projects := make([]project, 0, len(results))
+	for _, result := range results {
+		if result.Error != nil || result.DepGraph == nil {
+			continue
+		}
+		projects = append(projects, newProject(result))
+	}
+	return projects
+}
+
+func newProject(result ecosystems.SCAResult) project {
+	dg := result.DepGraph
+	p := project{
+		result:     result,
+		pkgManager: dg.PkgManager.Name,
+		dependsOn:  make(map[string][]string),
+	}
+
+	pkgIDByNode := make(map[string]string, len(dg.Graph.Nodes))
+	for _, node := range dg.Graph.Nodes {
+		pkgIDByNode[node.NodeID] = node.PkgID
+	}
+	rootPkgID := pkgIDByNode[dg.Graph.RootNodeID]
+
+	for _, pkg := range dg.Pkgs {
+		if pkg.ID == rootPkgID {
+			p.root = pkg
+			continue
+		}
+		p.packages = append(p.packages, pkg)
+	}
+
+	seen := make(map[[2]string]bool)
+	for _, node := range dg.Graph.Nodes {
+		for _, dep := range node.Deps {
+			edge := [2]string{node.PkgID, pkgIDByNode[dep.NodeID]}
+			if edge[1] == "" || seen[edge] {
+				continue
+			}
+			seen[edge] = true
+			p.dependsOn[edge[0]] = append(p.dependsOn[edge[0]], edge[1])
+		}
+	}
+	return p
+}
+
+// name returns the most specific name for the project: its target file if
+// the plugin reported one, otherwise its root package.
+func (p project) name() string {
+	if p.result.ResolverMetadata != nil && p.result.ResolverMetadata.NormalisedTargetFile != "" {
+		return p.result.ResolverMetadata.NormalisedTargetFile
+	}
+	if targetFile := p.result.ProjectDescriptor.GetTargetFile(); targetFile != "" {
+		return targetFile
+	}
+	return p.root.Info.Name
+}
@@ -0,0 +1,102 @@
+package sbomexport
+
+import (
+	"strings"
+
+	"github.com/package-url/packageurl-go"
+	"github.com/snyk/dep-graph/go/pkg/depgraph"
+)
+
+// purlTypes maps dep-graph package manager names to package URL types.
+// Managers missing from the map produce generic purls.
+var purlTypes = map[string]string{
+	"bun":       packageurl.TypeNPM,
+	"npm":       packageurl.TypeNPM,
+	"pnpm":      packageurl.TypeNPM,
+	"yarn":      packageurl.TypeNPM,
+	"pip":       packageurl.TypePyPi,
+	"pipenv":    packageurl.TypePyPi,
+	"poetry":    packageurl.TypePyPi,
+	"uv":        packageurl.TypePyPi,
+	"cargo":     packageurl.TypeCargo,
+	"gradle":    packageurl.TypeMaven,
+	"maven":     packageurl.TypeMaven,
+	"gomodules": packageurl.TypeGolang,
+	"golang":    packageurl.TypeGolang,
+	"nuget":     packageurl.TypeNuget,
+	"rubygems":  packageurl.TypeGem,
+	"composer":  packageurl.TypeComposer,
+}
+
+// purlOf returns the package URL for a dep-graph package, preferring the one
+// the plugin recorded.
+func purlOf(pkgManager string, info depgraph.PkgInfo) string {
+	if info.PackageURL != "" {
+		return info.PackageURL
+	}
+
+	purlType, ok := purlTypes[pkgManager]
+	if !ok {
+		purlType = packageurl.TypeGeneric
+	}
+	namespace, name := splitName(purlType, info.Name)
+	return packageurl.NewPackageURL(purlType, namespace, name, info.Version, nil, "").ToString()
+}
+
+// splitName splits a dep-graph package name into a purl namespace and name,
+// following each type's naming convention.
+func splitName(purlType, fullName string) (namespace, name string) {
+	switch purlType {
+	case packageurl.TypeMaven:
+		// group:artifact
+		if group, artifact, ok := strings.Cut(fullName, ":"); ok {
+			return group, artifact
+		}
+	case packageurl.TypeNPM:
+		// @scope/name
+		if strings.HasPrefix(fullName, "@") {
+			if scope, pkg, ok := strings.Cut(fullName, "/"); ok {
+				return scope, pkg
+			}
+		}
+	case packageurl.TypeGolang:
+		// module paths: everything up to the last element is the namespace
+		if i := strings.LastIndex(fullName, "/"); i >= 0 {
+			return fullName[:i], fullName[i+1:]
+		}
+	}
+	return "", fullName
+}
+
+// checksumQualifier is the purl qualifier plugins record package checksums
+// in, as comma-separated "algorithm:hex" values.
+const checksumQualifier = "checksum"
+
+// checksum is one digest of a package's artifact.
+type checksum struct {
+	algorithm string
+	value     string
+}
+
+// checksumsOf returns the checksums recorded on a purl. Plugins only record
+// them when asked to include provenance, so most purls have none.
+func checksumsOf(purl string) []checksum {
+	parsed, err := packageurl.FromString(purl)
+	if err != nil {
+		return nil
+	}
+	qualifier := parsed.Qualifiers.Map()[checksumQualifier]
+	if qualifier == "" {
+		return nil
+	}
+
+	var sums []checksum
+	for _, sum := range strings.Split(qualifier, ",") {
+		algorithm, value, ok := strings.Cut(sum, ":")
+		if !ok || value == "" {
+			continue
+		}
+		sums = append(sums, checksum{algorithm: strings.ToLower(algorithm), value: value})
+	}
+	return sums
+}
@@ -0,0 +1,47 @@
+package sbomexport
+
+import (
+	"testing"
+
+	"github.com/snyk/dep-graph/go/pkg/depgraph"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPurlOf(t *testing.T) {
+	tests := []struct {
+		pkgManager string
+		info       depgraph.PkgInfo
+		want       string
+	}{
+		{"pnpm", depgraph.PkgInfo{Name: "lodash", Version: "4.17.21"}, "pkg:npm/lodash@4.17.21"},
+		{"bun", depgraph.PkgInfo{Name: "@types/node", Version: "20.1.0"}, "pkg:npm/%40types/node@20.1.0"},
+		{"uv", depgraph.PkgInfo{Name: "django", Version: "3.1"}, "pkg:pypi/django@3.1"},
+		{"cargo", depgraph.PkgInfo{Name: "serde", Version: "1.0.0"}, "pkg:cargo/serde@1.0.0"},
+		{"gradle", depgraph.PkgInfo{Name: "com.google.guava:guava", Version: "33.0.0"}, "pkg:maven/com.google.guava/guava@33.0.0"},
+		{"gomodules", depgraph.PkgInfo{Name: "github.com/pkg/errors", Version: "v0.9.1"}, "pkg:golang/github.com/pkg/errors@v0.9.1"},
+		{"unknown", depgraph.PkgInfo{Name: "thing", Version: "1"}, "pkg:generic/thing@1"},
+		{"gradle", depgraph.PkgInfo{Name: "a:b", Version: "1", PackageURL: "pkg:maven/a/b@1?type=jar"}, "pkg:maven/a/b@1?type=jar"},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.want, func(t *testing.T) {
+			assert.Equal(t, tc.want, purlOf(tc.pkgManager, tc.info))
+		})
+	}
+}
+
+func TestChecksumsOf(t *testing.T) {
+	tests := map[string][]checksum{
+		"pkg:maven/org.example/artifact@1.0.0?checksum=sha1%3Aabcd":       {{"sha1", "abcd"}},
+		"pkg:pypi/django@3.1?checksum=sha256%3Aaaa%2CSHA256%3Abbb":        {{"sha256", "aaa"}, {"sha256", "bbb"}},
+		"pkg:pypi/django@3.1?checksum=sha256:aaa,garbage&repository_url=": {{"sha256", "aaa"}},
+		"pkg:npm/lodash@4.17.21": nil,
+		"not a purl":             nil,
+	}
+
+	for purl, want := range tests {
+		t.Run(purl, func(t *testing.T) {
+			assert.Equal(t, want, checksumsOf(purl))
+		})
+	}
+}
@@ -0,0 +1,60 @@
+package sbomexport
+
+import (
+	"testing"
+
+	"github.com/snyk/dep-graph/go/pkg/depgraph"
+	"github.com/stretchr/testify/require"
+
+	"github.com/snyk/cli-extension-dep-graph/v2/pkg/ecosystems"
+	"github.com/snyk/cli-extension-dep-graph/v2/pkg/identity"
+)
+
+// edge connects two packages, identified as name@version, in a test graph.
+type edge struct {
+	from, to string
+}
+
+// newResult builds a successful result for a graph rooted at root whose
+// packages are connected by edges. Packages are added in the order they first
+// appear in edges.
+func newResult(t *testing.T, pkgManager, targetFile string, root depgraph.PkgInfo, edges ...edge) ecosystems.SCAResult {
+	t.Helper()
+	builder, err := depgraph.NewBuilder(&depgraph.PkgManager{Name: pkgManager}, &root)
+	require.NoError(t, err)
+
+	rootID := root.Name + "@" + root.Version
+	for _, e := range edges {
+		for _, id := range []string{e.from, e.to} {
+			if id == rootID {
+				continue
+			}
+			name, version := splitID(id)
+			builder.AddNode(id, &depgraph.PkgInfo{Name: name, Version: version})
+		}
+		from := e.from
+		if from == rootID {
+			from = builder.GetRootNode().NodeID
+		}
+		require.NoError(t, builder.ConnectNodes(from, e.to))
+	}
+
+	return ecosystems.SCAResult{
+		DepGraph: builder.Build(),
+		ProjectDescriptor: identity.ProjectDescriptor{Identity: identity.ProjectIdentity{
+			ProjectType: pkgManager,
+			TargetFile:  &targetFile,
+		}},
+		ResolverMetadata: &ecosystems.ResolverMetadata{PluginName: pkgManager, NormalisedTargetFile: targetFile},
+		ProcessedFiles:   []string{targetFile},
+	}
+}
+
+func splitID(id string) (name, version string) {
+	for i := len(id) - 1; i > 0; i-- {
+		if id[i] == '@' {
+			return id[:i], id[i+1:]
+		}
+	}
+	return id, ""
+}
@@ -0,0 +1,388 @@
+package sbomexport
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/snyk/dep-graph/go/pkg/depgraph"
+
+	"github.com/snyk/cli-extension-dep-graph/v2/pkg/ecosystems"
+)
+
+// SPDX spec versions WriteSPDX can produce.
+const (
+	SPDX23 = "2.3"
+	SPDX30 = "3.0"
+)
+
+const spdxNamespacePrefix = "https://snyk.io/spdx/"
+
+// spdxAlgorithms maps purl checksum algorithms to SPDX 2.3 checksum
+// algorithms. SPDX 3.0 uses the keys as they are. Checksums with other
+// algorithms are left out.
+var spdxAlgorithms = map[string]string{
+	"md5":    "MD5",
+	"sha1":   "SHA1",
+	"sha256": "SHA256",
+	"sha384": "SHA384",
+	"sha512": "SHA512",
+}
+
+// spdxPackage is a package in either SPDX version's terms.
+type spdxPackage struct {
+	id         string
+	name       string
+	version    string
+	purl       string
+	checksums  []checksum
+	properties []cdxProperty
+	root       bool
+}
+
+// spdxGraph is the packages and DEPENDS_ON edges of one SPDX document,
+// identified by SPDX 2.3 element IDs.
+type spdxGraph struct {
+	name      string
+	roots     []spdxPackage
+	packages  []spdxPackage
+	dependsOn *dependencySet
+}
+
+// WriteSPDX writes one SPDX JSON document covering the dep-graphs of every
+// result that has one. Each project's root package is a package the document
+// describes, carrying the project's descriptor, processed files and version
+// build info as annotations; every other package is identified by its purl,
+// with any checksums the plugin recorded, and every edge is a DEPENDS_ON
+// relationship. A document covering one project is named after it, otherwise
+// after subject.
+func WriteSPDX(w io.Writer, results []ecosystems.SCAResult, specVersion, subject string) error {
+	graph := newSPDXGraph(newProjects(results), subject)
+	namespace := spdxNamespacePrefix + url.PathEscape(graph.name) + "-" + newUUID()
+	created := time.Now().UTC().Format(time.RFC3339)
+
+	var doc any
+	switch specVersion {
+	case SPDX23:
+		doc = newSPDX23(graph, namespace, created)
+	case SPDX30:
+		doc = newSPDX30(graph, namespace, created)
+	default:
+		return fmt.Errorf("unsupported SPDX spec version %q", specVersion)
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return fmt.Errorf("failed to encode SPDX document: %w", err)
+	}
+	return nil
+}
+
+func newSPDXGraph(projects []project, subject string) spdxGraph {
+	graph := spdxGraph{name: subject, dependsOn: newDependencySet()}
+	if len(projects) == 1 {
+		graph.name = projects[0].root.Info.Name
+	}
+
+	packageIDs := make(map[string]string)
+	for i, p := range projects {
+		rootID := "SPDXRef-Project-" + strconv.Itoa(i+1)
+		rootPURL := purlOf(p.pkgManager, p.root.Info)
+		graph.roots = append(graph.roots, spdxPackage{
+			id:         rootID,
+			name:       p.root.Info.Name,
+			version:    p.root.Info.Version,
+			purl:       rootPURL,
+			checksums:  checksumsOf(rootPURL),
+			properties: projectProperties(p.result),
+			root:       true,
+		})
+
+		ids := map[string]string{p.root.ID: rootID}
+		for _, pkg := range p.packages {
+			purl := purlOf(p.pkgManager, pkg.Info)
+			id, ok := packageIDs[purl]
+			if !ok {
+				id = "SPDXRef-Package-" + strconv.Itoa(len(packageIDs)+1)
+				packageIDs[purl] = id
+				graph.packages = append(graph.packages, spdxPackage{
+					id:        id,
+					name:      pkg.Info.Name,
+					version:   pkg.Info.Version,
+					purl:      purl,
+					checksums: checksumsOf(purl),
+				})
+			}
+			ids[pkg.ID] = id
+		}
+
+		for _, pkg := range append([]depgraph.Pkg{p.root}, p.packages...) {
+			dependsOn := make([]string, 0, len(p.dependsOn[pkg.ID]))
+			for _, id := range p.dependsOn[pkg.ID] {
+				dependsOn = append(dependsOn, ids[id])
+			}
+			graph.dependsOn.add(ids[pkg.ID], dependsOn...)
+		}
+	}
+	return graph
+}
+
+// SPDX 2.3 JSON.
+
+type spdx23Document struct {
+	SPDXVersion       string               `json:"spdxVersion"`
+	DataLicense       string               `json:"dataLicense"`
+	SPDXID            string               `json:"SPDXID"`
+	Name              string               `json:"name"`
+	DocumentNamespace string               `json:"documentNamespace"`
+	CreationInfo      spdx23CreationInfo   `json:"creationInfo"`
+	Packages          []spdx23Package      `json:"packages"`
+	Relationships     []spdx23Relationship `json:"relationships"`
+}
+
+type spdx23CreationInfo struct {
+	Created  string   `json:"created"`
+	Creators []string `json:"creators"`
+}
+
+type spdx23Package struct {
+	SPDXID                string              `json:"SPDXID"`
+	Name                  string              `json:"name"`
+	VersionInfo           string              `json:"versionInfo,omitempty"`
+	DownloadLocation      string              `json:"downloadLocation"`
+	FilesAnalyzed         bool                `json:"filesAnalyzed"`
+	PrimaryPackagePurpose string              `json:"primaryPackagePurpose,omitempty"`
+	Checksums             []spdx23Checksum    `json:"checksums,omitempty"`
+	ExternalRefs          []spdx23ExternalRef `json:"externalRefs,omitempty"`
+	Annotations           []spdx23Annotation  `json:"annotations,omitempty"`
+}
+
+type spdx23Checksum struct {
+	Algorithm     string `json:"algorithm"`
+	ChecksumValue string `json:"checksumValue"`
+}
+
+type spdx23ExternalRef struct {
+	ReferenceCategory string `json:"referenceCategory"`
+	ReferenceType     string `json:"referenceType"`
+	ReferenceLocator  string `json:"referenceLocator"`
+}
+
+type spdx23Annotation struct {
+	AnnotationDate string `json:"annotationDate"`
+	AnnotationType string `json:"annotationType"`
+	Annotator      string `json:"annotator"`
+	Comment        string `json:"comment"`
+}
+
+type spdx23Relationship struct {
+	SPDXElementID      string `json:"spdxElementId"`
+	RelationshipType   string `json:"relationshipType"`
+	RelatedSPDXElement string `json:"relatedSpdxElement"`
+}
+
+func newSPDX23(graph spdxGraph, namespace, created string) spdx23Document {
+	creator := "Tool: " + toolName
+	doc := spdx23Document{
+		SPDXVersion:       "SPDX-2.3",
+		DataLicense:       "CC0-1.0",
+		SPDXID:            "SPDXRef-DOCUMENT",
+		Name:              graph.name,
+		DocumentNamespace: namespace,
+		CreationInfo:      spdx23CreationInfo{Created: created, Creators: []string{creator}},
+		Packages:          []spdx23Package{},
+		Relationships:     []spdx23Relationship{},
+	}
+
+	for _, pkg := range append(append([]spdxPackage{}, graph.roots...), graph.packages...) {
+		out := spdx23Package{
+			SPDXID:                pkg.id,
+			Name:                  pkg.name,
+			VersionInfo:           pkg.version,
+			DownloadLocation:      "NOASSERTION",
+			PrimaryPackagePurpose: "LIBRARY",
+		}
+		if pkg.root {
+			out.PrimaryPackagePurpose = "APPLICATION"
+		}
+		for _, sum := range pkg.checksums {
+			if algorithm, ok := spdxAlgorithms[sum.algorithm]; ok {
+				out.Checksums = append(out.Checksums, spdx23Checksum{Algorithm: algorithm, ChecksumValue: sum.value})
+			}
+		}
+		if pkg.purl != "" {
+			out.ExternalRefs = []spdx23ExternalRef{{ReferenceCategory: "PACKAGE-MANAGER", ReferenceType: "purl", ReferenceLocator: pkg.purl}}
+		}
+		for _, prop := range pkg.properties {
+			out.Annotations = append(out.Annotations, spdx23Annotation{
+				AnnotationDate: created,
+				AnnotationType: "OTHER",
+				Annotator:      creator,
+				Comment:        prop.Name + "=" + prop.Value,
+			})
+		}
+		doc.Packages = append(doc.Packages, out)
+	}
+
+	for _, root := range graph.roots {
+		doc.Relationships = append(doc.Relationships, spdx23Relationship{
+			SPDXElementID: doc.SPDXID, RelationshipType: "DESCRIBES", RelatedSPDXElement: root.id,
+		})
+	}
+	for _, dep := range graph.dependsOn.list() {
+		for _, to := range dep.DependsOn {
+			doc.Relationships = append(doc.Relationships, spdx23Relationship{
+				SPDXElementID: dep.Ref, RelationshipType: "DEPENDS_ON", RelatedSPDXElement: to,
+			})
+		}
+	}
+	return doc
+}
+
+// SPDX 3.0 JSON-LD.
+
+const (
+	spdx30Context      = "https://spdx.org/rdf/3.0.1/spdx-context.jsonld"
+	spdx30SpecVersion  = "3.0.1"
+	spdx30CreationInfo = "_:creationinfo"
+)
+
+type spdx30Document struct {
+	Context string `json:"@context"`
+	Graph   []any  `json:"@graph"`
+}
+
+type spdx30CreationInfoElement struct {
+	Type         string   `json:"type"`
+	ID           string   `json:"@id"`
+	SpecVersion  string   `json:"specVersion"`
+	Created      string   `json:"created"`
+	CreatedBy    []string `json:"createdBy"`
+	CreatedUsing []string `json:"createdUsing"`
+}
+
+// spdx30Element holds the properties SPDX 3.0 elements share; the fields
+// each type adds are omitted when empty.
+type spdx30Element struct {
+	Type               string       `json:"type"`
+	SPDXID             string       `json:"spdxId"`
+	CreationInfo       string       `json:"creationInfo"`
+	Name               string       `json:"name,omitempty"`
+	ProfileConformance []string     `json:"profileConformance,omitempty"`
+	RootElement        []string     `json:"rootElement,omitempty"`
+	Element            []string     `json:"element,omitempty"`
+	SBOMType           []string     `json:"software_sbomType,omitempty"`
+	PackageVersion     string       `json:"software_packageVersion,omitempty"`
+	PackageURL         string       `json:"software_packageUrl,omitempty"`
+	PrimaryPurpose     string       `json:"software_primaryPurpose,omitempty"`
+	VerifiedUsing      []spdx30Hash `json:"verifiedUsing,omitempty"`
+	From               string       `json:"from,omitempty"`
+	RelationshipType   string       `json:"relationshipType,omitempty"`
+	To                 []string     `json:"to,omitempty"`
+	AnnotationType     string       `json:"annotationType,omitempty"`
+	Subject            string       `json:"subject,omitempty"`
+	Statement          string       `json:"statement,omitempty"`
+}
+
+type spdx30Hash struct {
+	Type      string `json:"type"`
+	Algorithm string `json:"algorithm"`
+	HashValue string `json:"hashValue"`
+}
+
+func newSPDX30(graph spdxGraph, namespace, created string) spdx30Document {
+	ref := func(id string) string { return namespace + "#" + id }
+	toolID, agentID, sbomID := ref("SPDXRef-Tool"), ref("SPDXRef-Snyk"), ref("SPDXRef-SBOM")
+	element := func(elementType, id string) spdx30Element {
+		return spdx30Element{Type: elementType, SPDXID: id, CreationInfo: spdx30CreationInfo}
+	}
+
+	var (
+		elements   []spdx30Element
+		rootIDs    []string
+		sbomIDs    []string
+		annotation int
+	)
+	for _, pkg := range append(append([]spdxPackage{}, graph.roots...), graph.packages...) {
+		out := element("software_Package", ref(pkg.id))
+		out.Name = pkg.name
+		out.PackageVersion = pkg.version
+		out.PackageURL = pkg.purl
+		out.PrimaryPurpose = "library"
+		if pkg.root {
+			out.PrimaryPurpose = "application"
+			rootIDs = append(rootIDs, out.SPDXID)
+		}
+		for _, sum := range pkg.checksums {
+			if _, ok := spdxAlgorithms[sum.algorithm]; ok {
+				out.VerifiedUsing = append(out.VerifiedUsing, spdx30Hash{Type: "Hash", Algorithm: sum.algorithm, HashValue: sum.value})
+			}
+		}
+		elements = append(elements, out)
+
+		for _, prop := range pkg.properties {
+			annotation++
+			note := element("Annotation", ref("SPDXRef-Annotation-"+strconv.Itoa(annotation)))
+			note.AnnotationType = "other"
+			note.Subject = out.SPDXID
+			note.Statement = prop.Name + "=" + prop.Value
+			elements = append(elements, note)
+		}
+	}
+
+	for i, dep := range graph.dependsOn.list() {
+		if len(dep.DependsOn) == 0 {
+			continue
+		}
+		rel := element("Relationship", ref("SPDXRef-Relationship-"+strconv.Itoa(i+1)))
+		rel.From = ref(dep.Ref)
+		rel.RelationshipType = "dependsOn"
+		for _, to := range dep.DependsOn {
+			rel.To = append(rel.To, ref(to))
+		}
+		elements = append(elements, rel)
+	}
+
+	for _, e := range elements {
+		sbomIDs = append(sbomIDs, e.SPDXID)
+	}
+
+	sbom := element("software_Sbom", sbomID)
+	sbom.SBOMType = []string{"build"}
+	sbom.RootElement = rootIDs
+	sbom.Element = sbomIDs
+
+	doc := element("SpdxDocument", namespace)
+	doc.Name = graph.name
+	doc.ProfileConformance = []string{"core", "software"}
+	doc.RootElement = []string{sbomID}
+	doc.Element = append([]string{toolID, agentID, sbomID}, sbomIDs...)
+
+	tool := element("Tool", toolID)
+	tool.Name = toolName
+	agent := element("Organization", agentID)
+	agent.Name = "Snyk"
+
+	out := spdx30Document{
+		Context: spdx30Context,
+		Graph: []any{
+			spdx30CreationInfoElement{
+				Type:         "CreationInfo",
+				ID:           spdx30CreationInfo,
+				SpecVersion:  spdx30SpecVersion,
+				Created:      created,
+				CreatedBy:    []string{agentID},
+				CreatedUsing: []string{toolID},
+			},
+			agent, tool, doc, sbom,
+		},
+	}
+	for _, e := range elements {
+		out.Graph = append(out.Graph, e)
+	}
+	return out
+}
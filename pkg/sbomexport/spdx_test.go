@@ -0,0 +1,171 @@
+package sbomexport
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/snyk/dep-graph/go/pkg/depgraph"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/snyk/cli-extension-dep-graph/v2/pkg/ecosystems"
+)
+
+const (
+	testNamespace = "https://snyk.io/spdx/app-1"
+	testCreated   = "2026-01-01T00:00:00Z"
+)
+
+// withPURL records purl on the package of result's dep-graph with the given ID.
+func withPURL(t *testing.T, result ecosystems.SCAResult, pkgID, purl string) ecosystems.SCAResult {
+	t.Helper()
+	for i := range result.DepGraph.Pkgs {
+		if result.DepGraph.Pkgs[i].ID == pkgID {
+			result.DepGraph.Pkgs[i].Info.PackageURL = purl
+			return result
+		}
+	}
+	t.Fatalf("no package %s", pkgID)
+	return result
+}
+
+func TestNewSPDX23_SingleProject(t *testing.T) {
+	result := newResult(t, "pipenv", "Pipfile", depgraph.PkgInfo{Name: "app", Version: "0.0.0"},
+		edge{"app@0.0.0", "requests@2.31.0"},
+		edge{"requests@2.31.0", "idna@3.6"},
+	)
+	result = withPURL(t, result, "requests@2.31.0", "pkg:pypi/requests@2.31.0?checksum=sha256%3Aaaa%2Csha256%3Abbb")
+
+	doc := newSPDX23(newSPDXGraph(newProjects([]ecosystems.SCAResult{result}), "repo"), testNamespace, testCreated)
+
+	assert.Equal(t, "SPDX-2.3", doc.SPDXVersion)
+	assert.Equal(t, "app", doc.Name, "a document covering one project is named after it")
+	assert.Equal(t, testNamespace, doc.DocumentNamespace)
+
+	require.Len(t, doc.Packages, 3)
+	root := doc.Packages[0]
+	assert.Equal(t, "SPDXRef-Project-1", root.SPDXID)
+	assert.Equal(t, "APPLICATION", root.PrimaryPackagePurpose)
+	assert.Contains(t, root.Annotations, spdx23Annotation{
+		AnnotationDate: testCreated,
+		AnnotationType: "OTHER",
+		Annotator:      "Tool: " + toolName,
+		Comment:        "snyk:targetFile=Pipfile",
+	})
+
+	assert.Equal(t, spdx23Package{
+		SPDXID:                "SPDXRef-Package-1",
+		Name:                  "requests",
+		VersionInfo:           "2.31.0",
+		DownloadLocation:      "NOASSERTION",
+		PrimaryPackagePurpose: "LIBRARY",
+		Checksums:             []spdx23Checksum{{"SHA256", "aaa"}, {"SHA256", "bbb"}},
+		ExternalRefs: []spdx23ExternalRef{{
+			ReferenceCategory: "PACKAGE-MANAGER",
+			ReferenceType:     "purl",
+			ReferenceLocator:  "pkg:pypi/requests@2.31.0?checksum=sha256%3Aaaa%2Csha256%3Abbb",
+		}},
+	}, doc.Packages[1])
+	assert.Empty(t, doc.Packages[2].Checksums)
+
+	assert.Equal(t, []spdx23Relationship{
+		{"SPDXRef-DOCUMENT", "DESCRIBES", "SPDXRef-Project-1"},
+		{"SPDXRef-Project-1", "DEPENDS_ON", "SPDXRef-Package-1"},
+		{"SPDXRef-Package-1", "DEPENDS_ON", "SPDXRef-Package-2"},
+	}, doc.Relationships)
+}
+
+func TestNewSPDX23_MultipleProjectsShareComponents(t *testing.T) {
+	results := []ecosystems.SCAResult{
+		newResult(t, "cargo", "Cargo.lock", depgraph.PkgInfo{Name: "cli", Version: "0.1.0"}, edge{"cli@0.1.0", "serde@1.0.0"}),
+		newResult(t, "cargo", "lib/Cargo.lock", depgraph.PkgInfo{Name: "lib", Version: "0.1.0"}, edge{"lib@0.1.0", "serde@1.0.0"}),
+	}
+
+	doc := newSPDX23(newSPDXGraph(newProjects(results), "repo"), testNamespace, testCreated)
+
+	assert.Equal(t, "repo", doc.Name)
+	require.Len(t, doc.Packages, 3, "a package shared by two projects is one package")
+	assert.Equal(t, []spdx23Relationship{
+		{"SPDXRef-DOCUMENT", "DESCRIBES", "SPDXRef-Project-1"},
+		{"SPDXRef-DOCUMENT", "DESCRIBES", "SPDXRef-Project-2"},
+		{"SPDXRef-Project-1", "DEPENDS_ON", "SPDXRef-Package-1"},
+		{"SPDXRef-Project-2", "DEPENDS_ON", "SPDXRef-Package-1"},
+	}, doc.Relationships)
+}
+
+func TestNewSPDX30(t *testing.T) {
+	result := newResult(t, "gradle", "build.gradle", depgraph.PkgInfo{Name: "app", Version: "1.0.0"},
+		edge{"app@1.0.0", "org.example:lib@2.0.0"},
+	)
+	result = withPURL(t, result, "org.example:lib@2.0.0", "pkg:maven/org.example/lib@2.0.0?checksum=sha1%3Aabcd")
+
+	doc := newSPDX30(newSPDXGraph(newProjects([]ecosystems.SCAResult{result}), "repo"), testNamespace, testCreated)
+
+	assert.Equal(t, spdx30Context, doc.Context)
+	elements := make(map[string]spdx30Element)
+	for _, e := range doc.Graph {
+		if element, ok := e.(spdx30Element); ok {
+			elements[element.SPDXID] = element
+		}
+	}
+	ref := func(id string) string { return testNamespace + "#" + id }
+
+	assert.Equal(t, []string{ref("SPDXRef-SBOM")}, elements[testNamespace].RootElement)
+	assert.Equal(t, []string{ref("SPDXRef-Project-1")}, elements[ref("SPDXRef-SBOM")].RootElement)
+
+	lib := elements[ref("SPDXRef-Package-1")]
+	assert.Equal(t, "software_Package", lib.Type)
+	assert.Equal(t, "org.example:lib", lib.Name)
+	assert.Equal(t, "library", lib.PrimaryPurpose)
+	assert.Equal(t, []spdx30Hash{{Type: "Hash", Algorithm: "sha1", HashValue: "abcd"}}, lib.VerifiedUsing)
+
+	var relationships []spdx30Element
+	for _, e := range elements {
+		if e.Type == "Relationship" {
+			relationships = append(relationships, e)
+		}
+	}
+	require.Len(t, relationships, 1)
+	assert.Equal(t, ref("SPDXRef-Project-1"), relationships[0].From)
+	assert.Equal(t, "dependsOn", relationships[0].RelationshipType)
+	assert.Equal(t, []string{ref("SPDXRef-Package-1")}, relationships[0].To)
+
+	for id, e := range elements {
+		if id != testNamespace && id != ref("SPDXRef-SBOM") {
+			assert.Contains(t, elements[testNamespace].Element, id, "the document lists every element")
+		}
+		assert.Equal(t, spdx30CreationInfo, e.CreationInfo)
+	}
+}
+
+func TestWriteSPDX(t *testing.T) {
+	result := newResult(t, "uv", "uv.lock", depgraph.PkgInfo{Name: "app", Version: "1.0.0"}, edge{"app@1.0.0", "idna@3.6"})
+
+	t.Run("2.3", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, WriteSPDX(&buf, []ecosystems.SCAResult{result}, SPDX23, "repo"))
+
+		var doc map[string]any
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &doc))
+		assert.Equal(t, "SPDX-2.3", doc["spdxVersion"])
+		assert.Regexp(t, `^https://snyk\.io/spdx/app-[0-9a-f-]{36}$`, doc["documentNamespace"])
+		assert.NotEmpty(t, doc["creationInfo"].(map[string]any)["created"])
+	})
+
+	t.Run("3.0", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, WriteSPDX(&buf, []ecosystems.SCAResult{result}, SPDX30, "repo"))
+
+		var doc map[string]any
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &doc))
+		assert.Equal(t, spdx30Context, doc["@context"])
+		creationInfo := doc["@graph"].([]any)[0].(map[string]any)
+		assert.Equal(t, "3.0.1", creationInfo["specVersion"])
+	})
+
+	t.Run("unsupported version", func(t *testing.T) {
+		err := WriteSPDX(&bytes.Buffer{}, nil, "2.2", "repo")
+		assert.ErrorContains(t, err, "unsupported SPDX spec version")
+	})
+}